@@ -10,7 +10,15 @@ fi
 go mod tidy
 
 rm -rf ./bin
+
+# Build metadata reported by `containix --version`
+VERSION=${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+PKG=github.com/shubhamku044/containix/internal/version
+LDFLAGS="-X ${PKG}.Version=${VERSION} -X ${PKG}.Commit=${COMMIT} -X ${PKG}.Date=${DATE}"
+
 # Build the application
-go build -o ./bin/containix ./cmd/containix
+go build -ldflags "${LDFLAGS}" -o ./bin/containix ./cmd/containix
 
 echo "Build completed successfully. Run ./containix to start the application."
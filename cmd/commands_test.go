@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testScenario = `warmup: 10s
+containers:
+  - name: web
+    image: nginx:1.25
+    ports: ["8080:80"]
+    log_every: 1s
+    logs: ['GET /health {n}']
+  - name: job
+    image: busybox
+    status: exited
+`
+
+// testFlags returns global flags that run the commands against a small
+// simulated host, with an empty config file
+func testFlags(t *testing.T) []string {
+	t.Helper()
+	dir := t.TempDir()
+	scenario := filepath.Join(dir, "scenario.yaml")
+	if err := os.WriteFile(scenario, []byte(testScenario), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(config, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return []string{"--config", config, "--demo-scenario", scenario}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout []string // substrings the output must contain
+		absent []string // substrings it must not contain
+		stderr string
+	}{
+		{
+			name:   "ps lists running containers",
+			args:   []string{"ps"},
+			code:   exitOK,
+			stdout: []string{"NAME", "web", "running", "0.0.0.0:8080->80/tcp"},
+			absent: []string{"job"},
+		},
+		{
+			name:   "ps --all includes stopped containers",
+			args:   []string{"ps", "--all"},
+			code:   exitOK,
+			stdout: []string{"web", "job", "exited"},
+		},
+		{
+			name:   "ps -a includes stopped containers",
+			args:   []string{"ps", "-a"},
+			code:   exitOK,
+			stdout: []string{"job"},
+		},
+		{
+			name:   "ps as json",
+			args:   []string{"ps", "--output", "json"},
+			code:   exitOK,
+			stdout: []string{`"name": "web"`, `"status": "running"`},
+			absent: []string{"NAME"},
+		},
+		{
+			name:   "ps as yaml",
+			args:   []string{"ps", "-o", "yaml"},
+			code:   exitOK,
+			stdout: []string{"name: web"},
+		},
+		{
+			name:   "invalid output",
+			args:   []string{"ps", "--output", "xml"},
+			code:   exitUsage,
+			stderr: "Usage: containix ps",
+		},
+		{
+			name:   "unknown flag",
+			args:   []string{"ps", "--bogus"},
+			code:   exitUsage,
+			stderr: "flag provided but not defined",
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"bogus"},
+			code:   exitUsage,
+			stderr: `unknown command "bogus"`,
+		},
+		{
+			name:   "stop",
+			args:   []string{"stop", "web"},
+			code:   exitOK,
+			stdout: []string{"CONTAINER", "web", "stop", "ok"},
+		},
+		{
+			name:   "stop without a name",
+			args:   []string{"stop"},
+			code:   exitUsage,
+			stderr: "at least one container name is required",
+		},
+		{
+			name:   "start a missing container",
+			args:   []string{"start", "web", "missing"},
+			code:   exitFailure,
+			stdout: []string{"missing"},
+			stderr: "1 of 2 container(s) failed to start",
+		},
+		{
+			name:   "restart as json",
+			args:   []string{"restart", "web", "-o", "json"},
+			code:   exitOK,
+			stdout: []string{`"action": "restart"`, `"ok": true`},
+		},
+		{
+			name:   "logs",
+			args:   []string{"logs", "web", "--tail", "2"},
+			code:   exitOK,
+			stdout: []string{"GET /health"},
+		},
+		{
+			name:   "logs of a missing container",
+			args:   []string{"logs", "missing"},
+			code:   exitFailure,
+			stderr: "missing",
+		},
+		{
+			name:   "logs with an invalid tail",
+			args:   []string{"logs", "web", "--tail", "many"},
+			code:   exitUsage,
+			stderr: `invalid --tail value "many"`,
+		},
+		{
+			name:   "stats",
+			args:   []string{"stats", "web"},
+			code:   exitOK,
+			stdout: []string{"CPU %", "web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append(testFlags(t), tt.args...), &stdout, &stderr)
+			if code != tt.code {
+				t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			for _, s := range tt.stdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout does not contain %q:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout contains %q:\n%s", s, stdout.String())
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr does not contain %q:\n%s", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestLogsJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := append(testFlags(t), "logs", "web", "--tail", "3", "--output", "json")
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("exit code = %d, stderr:\n%s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), stdout.String())
+	}
+	for _, line := range lines {
+		var e logEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		if e.Container != "web" || e.Stream != "stdout" || !strings.HasPrefix(e.Message, "GET /health") {
+			t.Errorf("entry = %+v", e)
+		}
+	}
+}
@@ -1,22 +1,7 @@
 package main
 
-import (
-	"fmt"
-	"os"
-
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/shubhamku044/containix/ui"
-)
+import "github.com/shubhamku044/containix/cmd"
 
 func main() {
-	p := tea.NewProgram(
-		ui.NewModel(),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
-
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
-		os.Exit(1)
-	}
+	cmd.Execute()
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+type actionEntry struct {
+	Container string `json:"container" yaml:"container"`
+	Action    string `json:"action" yaml:"action"`
+	OK        bool   `json:"ok" yaml:"ok"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type actionResult []actionEntry
+
+func (r actionResult) header() []string {
+	return []string{"CONTAINER", "ACTION", "RESULT"}
+}
+
+func (r actionResult) rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, e := range r {
+		result := "ok"
+		if !e.OK {
+			result = e.Error
+		}
+		rows[i] = []string{e.Container, e.Action, result}
+	}
+	return rows
+}
+
+// lifecycle builds the start, stop and restart commands
+func lifecycle(action string) func(context.Context, docker.Runtime, config.Config, []string, io.Writer, io.Writer) error {
+	return func(ctx context.Context, client docker.Runtime, _ config.Config, args []string, stdout, _ io.Writer) error {
+		fs, output := newFlagSet(action)
+		names, err := parseFlags(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := validateOutput(*output); err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return usageError{errors.New("at least one container name is required")}
+		}
+
+		op := map[string]func(context.Context, string) error{
+			"start":   client.StartContainer,
+			"stop":    client.StopContainer,
+			"restart": client.RestartContainer,
+		}[action]
+
+		// Docker accepts names and IDs alike, so no lookup is needed
+		result := actionResult{}
+		failed := 0
+		for _, name := range names {
+			entry := actionEntry{Container: name, Action: action, OK: true}
+			if err := op(ctx, name); err != nil {
+				entry.OK, entry.Error = false, err.Error()
+				failed++
+			}
+			result = append(result, entry)
+		}
+
+		if err := writeOutput(stdout, *output, result); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d container(s) failed to %s", failed, len(names), action)
+		}
+		return nil
+	}
+}
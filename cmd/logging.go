@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// setupLogging installs the default slog logger at the given level. The
+// returned func restores the previous logger.
+func setupLogging(level string, w io.Writer) (func(), error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid --log-level %q", level)
+	}
+
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: l})))
+	return func() { slog.SetDefault(previous) }, nil
+}
+
+// openLogFile opens the log file used while the UI owns the terminal
+func openLogFile() (*os.File, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, "containix")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, "containix.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
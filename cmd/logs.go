@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"gopkg.in/yaml.v3"
+)
+
+type logEntry struct {
+	Container string `json:"container" yaml:"container"`
+	Stream    string `json:"stream" yaml:"stream"`
+	Message   string `json:"message" yaml:"message"`
+}
+
+func runLogs(ctx context.Context, client docker.Runtime, cfg config.Config, args []string, stdout, stderr io.Writer) error {
+	fs, output := newFlagSet("logs")
+	follow := fs.Bool("follow", false, "keep streaming new log lines")
+	fs.BoolVar(follow, "f", false, "shorthand for --follow")
+	defaultTail := "all"
+	if cfg.Logs.Tail > 0 {
+		defaultTail = strconv.Itoa(cfg.Logs.Tail)
+	}
+	tail := fs.String("tail", defaultTail, "number of lines to show from the end")
+	names, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validateOutput(*output); err != nil {
+		return err
+	}
+	if len(names) != 1 {
+		return usageError{errors.New("exactly one container name is required")}
+	}
+	if *tail != "all" {
+		if _, err := strconv.Atoi(*tail); err != nil {
+			return usageError{fmt.Errorf("invalid --tail value %q", *tail)}
+		}
+	}
+
+	opts := docker.LogOptions{Follow: *follow, Tail: *tail}
+	if *output == outputTable {
+		return stopped(ctx, client.StreamContainerLogs(ctx, names[0], opts, stdout, stderr))
+	}
+
+	// Structured output emits one record per line as it arrives so it also
+	// works while following
+	var mu sync.Mutex
+	emit := func(stream string) io.Writer {
+		return &lineWriter{fn: func(line string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return writeLogEntry(stdout, *output, logEntry{Container: names[0], Stream: stream, Message: line})
+		}}
+	}
+	outW, errW := emit("stdout"), emit("stderr")
+	if err := stopped(ctx, client.StreamContainerLogs(ctx, names[0], opts, outW, errW)); err != nil {
+		return err
+	}
+	if err := outW.(*lineWriter).flush(); err != nil {
+		return err
+	}
+	return errW.(*lineWriter).flush()
+}
+
+// stopped treats an interrupted stream as finished: following only ends
+// when the user stops it
+func stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// writeLogEntry writes a single NDJSON line or YAML document
+func writeLogEntry(w io.Writer, format string, e logEntry) error {
+	if format == outputJSON {
+		return json.NewEncoder(w).Encode(e)
+	}
+	out, err := yaml.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "---\n%s", out)
+	return err
+}
+
+// lineWriter calls fn for every complete line written to it
+type lineWriter struct {
+	buf bytes.Buffer
+	fn  func(string) error
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			return len(p), nil
+		}
+		line := string(bytes.TrimRight(w.buf.Next(idx+1), "\r\n"))
+		if err := w.fn(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush emits a trailing line that has no newline
+func (w *lineWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := w.buf.String()
+	w.buf.Reset()
+	return w.fn(line)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+type psEntry struct {
+	ID      string   `json:"id" yaml:"id"`
+	Host    string   `json:"host,omitempty" yaml:"host,omitempty"`
+	Name    string   `json:"name" yaml:"name"`
+	Status  string   `json:"status" yaml:"status"`
+	Project string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Service string   `json:"service,omitempty" yaml:"service,omitempty"`
+	Ports   []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+type psResult []psEntry
+
+// fleet reports whether the containers come from several hosts, which adds
+// a HOST column
+func (r psResult) fleet() bool {
+	for _, e := range r {
+		if e.Host != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r psResult) header() []string {
+	if r.fleet() {
+		return []string{"HOST", "ID", "NAME", "STATUS", "PROJECT", "PORTS"}
+	}
+	return []string{"ID", "NAME", "STATUS", "PROJECT", "PORTS"}
+}
+
+func (r psResult) rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, e := range r {
+		rows[i] = []string{shortID(e.ID), e.Name, e.Status, e.Project, strings.Join(e.Ports, ", ")}
+		if r.fleet() {
+			rows[i] = append([]string{e.Host}, rows[i]...)
+		}
+	}
+	return rows
+}
+
+func runPs(ctx context.Context, client docker.Runtime, _ config.Config, args []string, stdout, _ io.Writer) error {
+	fs, output := newFlagSet("ps")
+	all := fs.Bool("all", false, "include stopped containers")
+	fs.BoolVar(all, "a", false, "shorthand for --all")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := validateOutput(*output); err != nil {
+		return err
+	}
+
+	containers, err := client.ListContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := psResult{}
+	for _, c := range containers {
+		if !*all && c.Status != "running" {
+			continue
+		}
+		var ports []string
+		for _, p := range c.Ports {
+			ports = append(ports, p.String())
+		}
+		result = append(result, psEntry{
+			ID:      c.ID,
+			Host:    c.Host,
+			Name:    c.Name,
+			Status:  c.Status,
+			Project: c.Project(),
+			Service: c.Service(),
+			Ports:   ports,
+		})
+	}
+
+	return writeOutput(stdout, *output, result)
+}
+
+// shortID trims a Docker ID to the usual 12 characters, keeping the host
+// prefix of an ID from a fleet
+func shortID(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		return id[:i+1] + shortID(id[i+1:])
+	}
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
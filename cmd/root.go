@@ -1,14 +1,335 @@
 package cmd
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/shubhamku044/containix/ui"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/app"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/demo"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/theme"
+	"github.com/shubhamku044/containix/internal/ui"
+	"github.com/shubhamku044/containix/internal/version"
+)
+
+// Exit codes returned by the headless commands
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
 )
 
+// usageError marks errors caused by invalid arguments
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+
+// command is a headless subcommand
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, client docker.Runtime, cfg config.Config, args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "ps", usage: "ps [--all] [--output table|json|yaml]", summary: "List containers", run: runPs},
+	{name: "start", usage: "start <name>... [--output ...]", summary: "Start containers", run: lifecycle("start")},
+	{name: "stop", usage: "stop <name>... [--output ...]", summary: "Stop containers", run: lifecycle("stop")},
+	{name: "restart", usage: "restart <name>... [--output ...]", summary: "Restart containers", run: lifecycle("restart")},
+	{name: "logs", usage: "logs <name> [--follow] [--tail N] [--output ...]", summary: "Print container logs", run: runLogs},
+	{name: "stats", usage: "stats [<name>...] [--output ...]", summary: "Print resource usage of running containers", run: runStats},
+}
+
+// globalOptions are the flags accepted before a subcommand
+type globalOptions struct {
+	host     string
+	hosts    string // comma separated host names of the config, or all
+	context  string
+	config   string
+	theme    string
+	layout   string
+	logLevel string
+	readOnly bool
+	version  bool
+	demo     bool
+	scenario string
+}
+
+// Execute runs the TUI, or a headless subcommand when one is given
 func Execute() {
-	p := tea.NewProgram(ui.NewModel())
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	opts, args, err := parseGlobalFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(stdout)
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	if opts.version || (len(args) > 0 && args[0] == "version") {
+		fmt.Fprintln(stdout, version.String())
+		return exitOK
+	}
+	if len(args) > 0 && args[0] == "help" {
+		printUsage(stdout)
+		return exitOK
+	}
+
+	cfg, err := config.Load(opts.config)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitUsage
+	}
+	// Checked here rather than with the other flags, as --theme may name a
+	// theme of the config file. Headless commands have no use for it but
+	// still refuse a name that would fail the UI.
+	if opts.theme != "" {
+		if _, err := theme.Resolve(opts.theme, cfg.Themes); err != nil {
+			fmt.Fprintln(stderr, "Error: --theme:", err)
+			return exitUsage
+		}
+	}
+
+	if len(args) == 0 {
+		return runTUI(opts, cfg, stderr)
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+
+		closeLog, err := setupLogging(opts.logLevel, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitUsage
+		}
+		defer closeLog()
+
+		client, stop, err := newRuntime(opts, cfg)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitFailure
+		}
+		defer stop()
+
+		// Ctrl+C cancels the command's requests instead of killing it
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		if err := c.run(ctx, client, cfg, args[1:], stdout, stderr); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			var usage usageError
+			if errors.As(err, &usage) {
+				fmt.Fprintln(stderr, "Usage: containix", c.usage)
+				return exitUsage
+			}
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "Error: unknown command %q\n", args[0])
+	printUsage(stderr)
+	return exitUsage
+}
+
+// parseGlobalFlags parses flags up to the first positional argument, which
+// names the subcommand
+func parseGlobalFlags(args []string) (globalOptions, []string, error) {
+	var opts globalOptions
+	fs := flag.NewFlagSet("containix", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.host, "host", "", "docker daemon address or configured host name (overrides DOCKER_HOST)")
+	fs.StringVar(&opts.hosts, "hosts", "", "comma separated host names from the config to show together, or all")
+	fs.StringVar(&opts.context, "context", "", "docker CLI context to connect to")
+	fs.StringVar(&opts.config, "config", "", "path to the config file (default "+config.DefaultPath()+")")
+	fs.StringVar(&opts.theme, "theme", "", "color theme")
+	fs.StringVar(&opts.layout, "layout", "", "screen layout: dashboard or classic")
+	fs.StringVar(&opts.logLevel, "log-level", "warn", "log level: debug, info, warn or error")
+	fs.BoolVar(&opts.readOnly, "readonly", false, "disable every action that changes containers, networks or files")
+	fs.BoolVar(&opts.version, "version", false, "print version information and exit")
+	fs.BoolVar(&opts.demo, "demo", false, "run against a simulated host instead of Docker")
+	fs.StringVar(&opts.scenario, "demo-scenario", "", "built-in demo scenario or scenario file, implies --demo")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, err
+	}
+	if opts.host != "" && opts.context != "" {
+		return opts, nil, errors.New("--host and --context are mutually exclusive")
+	}
+	if opts.scenario != "" {
+		opts.demo = true
+	}
+	if opts.hosts != "" && (opts.host != "" || opts.context != "") {
+		return opts, nil, errors.New("--hosts cannot be combined with --host or --context")
+	}
+	if opts.demo && (opts.host != "" || opts.context != "" || opts.hosts != "") {
+		return opts, nil, errors.New("--demo cannot be combined with --host, --hosts or --context")
+	}
+	if opts.layout != "" {
+		if _, err := ui.ParseLayout(opts.layout); err != nil {
+			return opts, nil, err
+		}
+	}
+	if opts.config != "" {
+		// An explicit config file must exist, the default one is optional
+		if _, err := os.Stat(opts.config); err != nil {
+			return opts, nil, fmt.Errorf("config file: %w", err)
+		}
+	} else {
+		opts.config = config.DefaultPath()
+	}
+	return opts, fs.Args(), nil
+}
+
+// dockerOptions selects the daemon. --host may name a host of the config
+// file instead of giving an address.
+func (o globalOptions) dockerOptions(cfg config.Config) docker.Options {
+	host := o.host
+	if h, ok := cfg.Hosts[host]; ok {
+		host = h.Address
+	}
+	return docker.Options{
+		Host:     host,
+		Context:  o.context,
+		TLS:      hostTLS(cfg),
+		ReadOnly: o.readOnly,
+		Timeouts: docker.Timeouts{
+			Query:   time.Duration(cfg.Timeouts.Query),
+			Action:  time.Duration(cfg.Timeouts.Action),
+			Compose: time.Duration(cfg.Timeouts.Compose),
+			Files:   time.Duration(cfg.Timeouts.Files),
+			Connect: time.Duration(cfg.Timeouts.Connect),
+		},
+	}
+}
+
+// hostTLS collects the certificates configured for each daemon address
+func hostTLS(cfg config.Config) map[string]docker.TLSFiles {
+	files := map[string]docker.TLSFiles{}
+	for _, h := range cfg.Hosts {
+		if h.TLS.IsZero() {
+			continue
+		}
+		files[h.Address] = docker.TLSFiles{
+			CA:     config.ExpandHome(h.TLS.CA),
+			Cert:   config.ExpandHome(h.TLS.Cert),
+			Key:    config.ExpandHome(h.TLS.Key),
+			Verify: !h.TLS.SkipVerify,
+		}
+	}
+	return files
+}
+
+func runTUI(opts globalOptions, cfg config.Config, stderr io.Writer) int {
+	// The UI owns the terminal, so logs go to a file instead
+	logFile, err := openLogFile()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+	defer logFile.Close()
+
+	closeLog, err := setupLogging(opts.logLevel, logFile)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	client, stop, err := newRuntime(opts, cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+	defer stop()
+
+	// Every daemon call of the UI is cancelled once it exits
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = app.Run(ctx, client, app.Options{
+		Config:     cfg,
+		ConfigPath: opts.config,
+		Theme:      opts.theme,
+		Layout:     ui.Layout(opts.layout),
+		Target:     target(opts, cfg),
+		Connect:    connector(opts, cfg),
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error running program: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: containix [flags] [command]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command the interactive UI is started.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  --host <address>      Docker daemon address or host name from the config (overrides DOCKER_HOST)")
+	fmt.Fprintln(w, "  --hosts <names>       Show several configured hosts together: a,b,c or all")
+	fmt.Fprintln(w, "  --context <name>      Docker CLI context to connect to")
+	fmt.Fprintln(w, "  --config <path>       Path to the config file (default "+config.DefaultPath()+")")
+	fmt.Fprintln(w, "  --theme <name>        Color theme (overrides the config and NO_COLOR)")
+	fmt.Fprintln(w, "  --layout <name>       Screen layout: dashboard or classic (overrides the config)")
+	fmt.Fprintln(w, "  --readonly            Disable every action that changes state")
+	fmt.Fprintln(w, "  --log-level <level>   debug, info, warn (default) or error")
+	fmt.Fprintln(w, "  --demo                Run against a simulated host instead of Docker")
+	fmt.Fprintln(w, "  --demo-scenario <s>   Demo scenario: "+strings.Join(demo.Names(), ", ")+" or a YAML file")
+	fmt.Fprintln(w, "  --version             Print version information and exit")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-52s %s\n", c.usage, c.summary)
+	}
+	fmt.Fprintf(w, "  %-52s %s\n", "version", "Print version information")
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting and
+// registers the shared --output flag
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	fs.StringVar(output, "o", outputTable, "shorthand for --output")
+	return fs, output
+}
 
-	if err := p.Start(); err != nil {
-		panic(err)
+// parseFlags parses flags that may appear before or after positional
+// arguments, so `logs web --follow` works like `logs --follow web`
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError{err}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
 }
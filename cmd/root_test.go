@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shubhamku044/containix/internal/config"
+)
+
+func TestParseGlobalFlags(t *testing.T) {
+	configFile := testFlags(t)[1]
+
+	tests := []struct {
+		name string
+		args []string
+		want globalOptions
+		rest []string
+		err  string
+	}{
+		{
+			name: "defaults",
+			want: globalOptions{config: config.DefaultPath(), logLevel: "warn"},
+		},
+		{
+			name: "stops at the command",
+			args: []string{"--readonly", "--theme", "light", "ps", "--all"},
+			want: globalOptions{config: config.DefaultPath(), logLevel: "warn", readOnly: true, theme: "light"},
+			rest: []string{"ps", "--all"},
+		},
+		{
+			name: "explicit config",
+			args: []string{"--config", configFile, "--layout", "classic"},
+			want: globalOptions{config: configFile, logLevel: "warn", layout: "classic"},
+		},
+		{
+			name: "scenario implies demo",
+			args: []string{"--demo-scenario", "oom"},
+			want: globalOptions{config: config.DefaultPath(), logLevel: "warn", demo: true, scenario: "oom"},
+		},
+		{
+			name: "host and context",
+			args: []string{"--host", "tcp://a:2375", "--context", "b"},
+			err:  "--host and --context are mutually exclusive",
+		},
+		{
+			name: "hosts and host",
+			args: []string{"--hosts", "all", "--host", "tcp://a:2375"},
+			err:  "--hosts cannot be combined with --host or --context",
+		},
+		{
+			name: "demo and context",
+			args: []string{"--demo-scenario", "oom", "--context", "b"},
+			err:  "--demo cannot be combined with --host, --hosts or --context",
+		},
+		{
+			name: "unknown layout",
+			args: []string{"--layout", "grid"},
+			err:  `unknown layout "grid"`,
+		},
+		{
+			name: "missing config file",
+			args: []string{"--config", filepath.Join(t.TempDir(), "missing.yaml")},
+			err:  "config file:",
+		},
+		{
+			name: "unknown flag",
+			args: []string{"--bogus"},
+			err:  "flag provided but not defined: -bogus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, rest, err := parseGlobalFlags(tt.args)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts != tt.want {
+				t.Errorf("options = %+v, want %+v", opts, tt.want)
+			}
+			if strings.Join(rest, " ") != strings.Join(tt.rest, " ") {
+				t.Errorf("arguments = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestRunGlobalFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{name: "version flag", args: []string{"--version"}, code: exitOK, stdout: "containix "},
+		{name: "version command", args: []string{"version"}, code: exitOK, stdout: "containix "},
+		{name: "help flag", args: []string{"--help"}, code: exitOK, stdout: "Usage: containix"},
+		{name: "help command", args: []string{"help"}, code: exitOK, stdout: "Commands:"},
+		{name: "invalid flag", args: []string{"--layout", "grid", "ps"}, code: exitUsage, stderr: "Usage: containix"},
+		{name: "unknown theme", args: []string{"--theme", "sepia", "ps"}, code: exitUsage, stderr: `--theme: unknown theme "sepia"`},
+		{name: "known theme", args: []string{"--theme", "light", "ps"}, code: exitOK, stdout: "web"},
+		{name: "invalid log level", args: []string{"--log-level", "loud", "ps"}, code: exitUsage},
+		{name: "read-only", args: []string{"--readonly", "stop", "web"}, code: exitFailure, stdout: "read-only mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append(testFlags(t), tt.args...), &stdout, &stderr)
+			if code != tt.code {
+				t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("stdout does not contain %q:\n%s", tt.stdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr does not contain %q:\n%s", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
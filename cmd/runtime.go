@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/demo"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// target names the daemon newRuntime connects to, for the status bar
+func target(opts globalOptions, cfg config.Config) string {
+	if opts.demo {
+		scenario := opts.scenario
+		if scenario == "" {
+			scenario = demo.DefaultScenario
+		}
+		return "demo (" + scenario + ")"
+	}
+	if opts.hosts != "" {
+		names, err := fleetHosts(opts.hosts, cfg)
+		if err == nil {
+			return fmt.Sprintf("fleet (%d hosts)", len(names))
+		}
+	}
+	return opts.dockerOptions(cfg).Target()
+}
+
+// connector opens a client for a context picked in the UI, keeping the
+// read-only setting. It returns nil in demo mode, where there is nothing to
+// switch to.
+func connector(opts globalOptions, cfg config.Config) func(ctx context.Context, dockerContext, host string) (docker.Runtime, error) {
+	if opts.demo {
+		return nil
+	}
+	return func(ctx context.Context, dockerContext, host string) (docker.Runtime, error) {
+		picked := opts
+		picked.host, picked.context = host, dockerContext
+		return docker.Connect(ctx, picked.dockerOptions(cfg))
+	}
+}
+
+// newRuntime connects to the Docker daemon, or to every host of --hosts,
+// or in demo mode builds the simulated host and starts its clock. The
+// returned func closes the connections or stops the clock.
+func newRuntime(opts globalOptions, cfg config.Config) (docker.Runtime, func(), error) {
+	if opts.hosts != "" {
+		fleet, err := newFleet(opts, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return fleet, func() { fleet.Close() }, nil
+	}
+	if !opts.demo {
+		client, err := docker.NewClient(opts.dockerOptions(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, func() { client.Close() }, nil
+	}
+
+	scenario, err := demo.Load(opts.scenario)
+	if err != nil {
+		return nil, nil, err
+	}
+	runtime := demo.New(scenario, time.Now())
+	runtime.SetReadOnly(opts.readOnly)
+	slog.Info("demo mode", "scenario", opts.scenario)
+
+	stop := make(chan struct{})
+	go demo.Run(runtime, scenario, stop)
+	return runtime, func() { close(stop) }, nil
+}
+
+// fleetHosts resolves --hosts to host names of the config file
+func fleetHosts(list string, cfg config.Config) ([]string, error) {
+	if list == "all" {
+		if len(cfg.Hosts) == 0 {
+			return nil, errors.New("--hosts all: no hosts in the config file")
+		}
+		names := make([]string, 0, len(cfg.Hosts))
+		for name := range cfg.Hosts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := cfg.Hosts[name]; !ok {
+			return nil, fmt.Errorf("--hosts: unknown host %q; add it under hosts in the config file", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("--hosts: no host names given")
+	}
+	return names, nil
+}
+
+// newFleet opens a client for every host of --hosts. Clients connect on
+// first use, so an unreachable host only shows up as down in the UI.
+func newFleet(opts globalOptions, cfg config.Config) (*docker.Fleet, error) {
+	names, err := fleetHosts(opts.hosts, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []docker.FleetMember
+	for _, name := range names {
+		host := opts
+		host.host, host.hosts = name, ""
+		client, err := docker.NewClient(host.dockerOptions(cfg))
+		if err != nil {
+			for _, m := range members {
+				m.Runtime.(*docker.Client).Close()
+			}
+			return nil, fmt.Errorf("host %s: %w", name, err)
+		}
+		members = append(members, docker.FleetMember{Name: name, Address: cfg.Hosts[name].Address, Runtime: client})
+	}
+	return docker.NewFleet(members, 0)
+}
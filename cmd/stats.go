@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+type statsEntry struct {
+	Name          string  `json:"name" yaml:"name"`
+	CPUPercent    float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryUsage   uint64  `json:"memory_usage" yaml:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit" yaml:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent" yaml:"memory_percent"`
+	NetworkRx     uint64  `json:"network_rx" yaml:"network_rx"`
+	NetworkTx     uint64  `json:"network_tx" yaml:"network_tx"`
+	BlockRead     uint64  `json:"block_read" yaml:"block_read"`
+	BlockWrite    uint64  `json:"block_write" yaml:"block_write"`
+	PIDs          uint64  `json:"pids" yaml:"pids"`
+}
+
+type statsResult []statsEntry
+
+func (r statsResult) header() []string {
+	return []string{"NAME", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O", "BLOCK I/O", "PIDS"}
+}
+
+func (r statsResult) rows() [][]string {
+	rows := make([][]string, len(r))
+	for i, e := range r {
+		rows[i] = []string{
+			e.Name,
+			fmt.Sprintf("%.2f%%", e.CPUPercent),
+			formatBytes(e.MemoryUsage) + " / " + formatBytes(e.MemoryLimit),
+			fmt.Sprintf("%.2f%%", e.MemoryPercent),
+			formatBytes(e.NetworkRx) + " / " + formatBytes(e.NetworkTx),
+			formatBytes(e.BlockRead) + " / " + formatBytes(e.BlockWrite),
+			fmt.Sprint(e.PIDs),
+		}
+	}
+	return rows
+}
+
+func runStats(ctx context.Context, client docker.Runtime, _ config.Config, args []string, stdout, _ io.Writer) error {
+	fs, output := newFlagSet("stats")
+	names, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validateOutput(*output); err != nil {
+		return err
+	}
+
+	// Without names, report every running container
+	if len(names) == 0 {
+		containers, err := client.ListContainers(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			if c.Status == "running" {
+				names = append(names, c.Ref())
+			}
+		}
+	}
+
+	result := statsResult{}
+	for _, name := range names {
+		stats, err := client.GetContainerStats(ctx, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		result = append(result, statsEntry{
+			Name:          name,
+			CPUPercent:    stats.CPUPercentage,
+			MemoryUsage:   stats.MemoryUsage,
+			MemoryLimit:   stats.MemoryLimit,
+			MemoryPercent: stats.MemoryPercentage,
+			NetworkRx:     stats.NetworkRx,
+			NetworkTx:     stats.NetworkTx,
+			BlockRead:     stats.BlockRead,
+			BlockWrite:    stats.BlockWrite,
+			PIDs:          stats.PIDs,
+		})
+	}
+
+	return writeOutput(stdout, *output, result)
+}
+
+// formatBytes formats bytes in binary units like the docker CLI
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
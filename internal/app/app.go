@@ -1,15 +1,45 @@
 package app
 
 import (
+	"context"
+	"log/slog"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
 	"github.com/shubhamku044/containix/internal/ui"
 )
 
-func Run() {
-	p := tea.NewProgram(ui.NewMainModel())
+// Options configures the interactive UI
+type Options struct {
+	Config     config.Config
+	ConfigPath string
+	Theme      string
+	Layout     ui.Layout
+	Target     string
+	Connect    func(ctx context.Context, dockerContext, host string) (docker.Runtime, error)
+}
+
+// Run starts the interactive UI and blocks until it exits. Daemon calls
+// still running when it does are cancelled with ctx.
+func Run(ctx context.Context, client docker.Runtime, opts Options) error {
+	slog.Info("starting UI", "target", opts.Target, "layout", opts.Layout, "theme", opts.Theme, "config", opts.ConfigPath)
+
+	model := ui.NewMainModel(client, ui.Options{
+		Config:     opts.Config,
+		ConfigPath: opts.ConfigPath,
+		Layout:     opts.Layout,
+		Theme:      opts.Theme,
+		Target:     opts.Target,
+		Connect:    opts.Connect,
+		Context:    ctx,
+	})
+	p := tea.NewProgram(
+		model,
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
 
 	_, err := p.Run()
-	if err != nil {
-		panic(err)
-	}
+	return err
 }
@@ -0,0 +1,313 @@
+// Package compose reads docker-compose.yml files into a normalized model
+// that the rest of containix can act on without the compose CLI.
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Project is a parsed compose project
+type Project struct {
+	Name       string
+	WorkingDir string
+	Files      []string
+	Services   []Service
+	Networks   map[string]Network
+	Volumes    map[string]Volume
+}
+
+// Service is a single service definition with short and long syntaxes
+// normalized
+type Service struct {
+	Name          string
+	Image         string
+	ContainerName string
+	Command       []string
+	Entrypoint    []string
+	Environment   []string // KEY=VALUE, sorted
+	Ports         []string // docker CLI syntax, e.g. 127.0.0.1:8080:80/tcp
+	Volumes       []string // bind syntax, e.g. data:/var/lib/data:ro
+	Networks      map[string]ServiceNetwork
+	NetworkMode   string // e.g. host, none or service:db, instead of Networks
+	Labels        map[string]string
+	Restart       string
+	DependsOn     []string
+	WorkingDir    string
+	User          string
+	Hostname      string
+}
+
+// ServiceNetwork is a service's attachment to a network
+type ServiceNetwork struct {
+	Aliases     []string
+	IPv4Address string
+}
+
+// Network is a top-level network definition
+type Network struct {
+	Name     string // name on the daemon
+	Driver   string
+	External bool
+	Internal bool
+	Labels   map[string]string
+}
+
+// Volume is a top-level named volume definition
+type Volume struct {
+	Name     string // name on the daemon
+	Driver   string
+	External bool
+	Labels   map[string]string
+}
+
+// Service returns the service with the given name
+func (p *Project) Service(name string) (Service, bool) {
+	for _, s := range p.Services {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Service{}, false
+}
+
+// DefaultNetwork is the key of the implicit network services join when they
+// declare none
+const DefaultNetwork = "default"
+
+// ContainerName returns the name compose gives the first replica of a service
+func (p *Project) ContainerName(s Service) string {
+	if s.ContainerName != "" {
+		return s.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-1", p.Name, s.Name)
+}
+
+// ServiceNetworks returns the networks a service joins, falling back to the
+// project's default network. A service with a network mode joins none.
+func (p *Project) ServiceNetworks(s Service) map[string]ServiceNetwork {
+	if s.NetworkMode != "" {
+		return nil
+	}
+	if len(s.Networks) > 0 {
+		return s.Networks
+	}
+	return map[string]ServiceNetwork{DefaultNetwork: {}}
+}
+
+// NetworkMode returns the network mode the container of a service is
+// created with, with service:<name> turned into the container of that
+// service, or "" when the service joins the project's networks
+func (p *Project) NetworkMode(s Service) string {
+	if name, ok := strings.CutPrefix(s.NetworkMode, "service:"); ok {
+		if dep, ok := p.Service(name); ok {
+			return "container:" + p.ContainerName(dep)
+		}
+	}
+	return s.NetworkMode
+}
+
+// dependencies are the services that must start before s, including the
+// one whose network it shares
+func (s Service) dependencies() []string {
+	if name, ok := strings.CutPrefix(s.NetworkMode, "service:"); ok && !slices.Contains(s.DependsOn, name) {
+		return append(slices.Clone(s.DependsOn), name)
+	}
+	return s.DependsOn
+}
+
+// Load reads and merges one or more compose files. A service, network or
+// volume defined again in a later file is merged into the earlier
+// definition the way compose does it. An empty projectName is derived from
+// the directory of the first file.
+func Load(paths []string, projectName string) (*Project, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no compose file given")
+	}
+
+	// The files are recorded in a label of every container, which has to
+	// hold wherever compose or containix later reads it from
+	files := make([]string, len(paths))
+	for i, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = abs
+	}
+	workingDir := filepath.Dir(files[0])
+	explicitName := projectName != ""
+	if !explicitName {
+		projectName = normalizeProjectName(filepath.Base(workingDir))
+	}
+
+	env := loadEnv(workingDir)
+	project := &Project{
+		Name:       projectName,
+		WorkingDir: workingDir,
+		Files:      files,
+		Networks:   map[string]Network{},
+		Volumes:    map[string]Volume{},
+	}
+	services := map[string]rawService{}
+	networks := map[string]rawNetwork{}
+	volumes := map[string]rawVolume{}
+
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file composeFile
+		if err := yaml.Unmarshal([]byte(interpolate(string(raw), env)), &file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if file.Name != "" && !explicitName {
+			project.Name = normalizeProjectName(file.Name)
+		}
+
+		for name, s := range file.Services {
+			if prev, ok := services[name]; ok {
+				s = prev.merge(s)
+			}
+			services[name] = s
+		}
+		for name, n := range file.Networks {
+			if prev, ok := networks[name]; ok {
+				n = prev.merge(n)
+			}
+			networks[name] = n
+		}
+		for name, v := range file.Volumes {
+			if prev, ok := volumes[name]; ok {
+				v = prev.merge(v)
+			}
+			volumes[name] = v
+		}
+	}
+
+	for name, n := range networks {
+		project.Networks[name] = n.normalize(project.Name, name)
+	}
+	for name, v := range volumes {
+		project.Volumes[name] = v.normalize(project.Name, name)
+	}
+	for name, raw := range services {
+		s := raw.normalize(name, workingDir, env)
+		s.Volumes = project.resolveVolumes(s.Volumes)
+		project.Services = append(project.Services, s)
+	}
+	sort.Slice(project.Services, func(i, j int) bool {
+		return project.Services[i].Name < project.Services[j].Name
+	})
+
+	// Every project has a default network unless it is overridden
+	if _, ok := project.Networks[DefaultNetwork]; !ok {
+		project.Networks[DefaultNetwork] = Network{Name: project.Name + "_default", Driver: "bridge"}
+	}
+
+	if err := project.validate(); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// resolveVolumes maps named volume sources to their names on the daemon
+func (p *Project) resolveVolumes(binds []string) []string {
+	result := make([]string, len(binds))
+	for i, bind := range binds {
+		source, rest, ok := strings.Cut(bind, ":")
+		if v, named := p.Volumes[source]; ok && named {
+			bind = v.Name + ":" + rest
+		}
+		result[i] = bind
+	}
+	return result
+}
+
+// validate checks references between services, networks and volumes
+func (p *Project) validate() error {
+	for _, s := range p.Services {
+		if s.Image == "" {
+			return fmt.Errorf("service %s: only services with an image are supported", s.Name)
+		}
+		for n := range s.Networks {
+			if _, ok := p.Networks[n]; !ok {
+				return fmt.Errorf("service %s refers to undefined network %s", s.Name, n)
+			}
+		}
+		for _, dep := range s.DependsOn {
+			if _, ok := p.Service(dep); !ok {
+				return fmt.Errorf("service %s depends on undefined service %s", s.Name, dep)
+			}
+		}
+		if s.NetworkMode != "" && len(s.Networks) > 0 {
+			return fmt.Errorf("service %s: network_mode and networks cannot be combined", s.Name)
+		}
+		if name, ok := strings.CutPrefix(s.NetworkMode, "service:"); ok {
+			if _, ok := p.Service(name); !ok {
+				return fmt.Errorf("service %s: network_mode refers to undefined service %s", s.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// StartOrder returns service names so that dependencies come first
+func (p *Project) StartOrder() ([]string, error) {
+	var order []string
+	state := map[string]int{} // 0 unvisited, 1 visiting, 2 done
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("dependency cycle involving service %s", name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		s, _ := p.Service(name)
+		for _, dep := range s.dependencies() {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+		return nil
+	}
+
+	for _, s := range p.Services {
+		if err := visit(s.Name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+var projectNameRe = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// normalizeProjectName mirrors how compose turns a directory into a project name
+func normalizeProjectName(name string) string {
+	return projectNameRe.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// ConfigFilesFromLabel splits the comma-separated config_files label
+func ConfigFilesFromLabel(label string) []string {
+	var files []string
+	for _, f := range strings.Split(label, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
@@ -0,0 +1,211 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "shop")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"compose.yml": `
+services:
+  web:
+    image: nginx:1.25
+    command: nginx -g 'daemon off;'
+    environment:
+      MODE: production
+      LOG: info
+    ports: ["8080:80"]
+    volumes:
+      - ./html:/usr/share/nginx/html:ro
+      - cache:/var/cache/nginx
+    networks: [front]
+    labels: [tier=web]
+  db:
+    image: postgres:16
+networks:
+  front:
+    driver: bridge
+volumes:
+  cache:
+    labels: {keep: "yes"}
+`,
+		"compose.override.yml": `
+services:
+  web:
+    ports: ["8443:443"]
+    environment: [LOG=debug]
+    volumes:
+      - ./dev:/usr/share/nginx/html
+    networks:
+      back: {aliases: [www]}
+    depends_on: [db]
+networks:
+  front:
+    internal: true
+  back: {}
+volumes:
+  cache:
+    driver: local
+`,
+	})
+
+	project, err := Load([]string{filepath.Join(dir, "compose.yml"), filepath.Join(dir, "compose.override.yml")}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, ok := project.Service("web")
+	if !ok {
+		t.Fatal("web is missing")
+	}
+
+	if web.Image != "nginx:1.25" {
+		t.Errorf("image = %q, want the one of the first file", web.Image)
+	}
+	if want := []string{"nginx", "-g", "daemon off;"}; !slices.Equal(web.Command, want) {
+		t.Errorf("command = %q, want %q", web.Command, want)
+	}
+	if want := []string{"LOG=debug", "MODE=production"}; !slices.Equal(web.Environment, want) {
+		t.Errorf("environment = %q, want %q", web.Environment, want)
+	}
+	if want := []string{"8080:80", "8443:443"}; !slices.Equal(web.Ports, want) {
+		t.Errorf("ports = %q, want %q", web.Ports, want)
+	}
+	// A mount on the same target is replaced, the others are kept
+	if want := []string{filepath.Join(dir, "dev") + ":/usr/share/nginx/html", "shop_cache:/var/cache/nginx"}; !slices.Equal(web.Volumes, want) {
+		t.Errorf("volumes = %q, want %q", web.Volumes, want)
+	}
+	if _, ok := web.Networks["front"]; !ok || !slices.Equal(web.Networks["back"].Aliases, []string{"www"}) {
+		t.Errorf("networks = %+v, want front and back as www", web.Networks)
+	}
+	if web.Labels["tier"] != "web" || !slices.Equal(web.DependsOn, []string{"db"}) {
+		t.Errorf("labels = %v, depends on %q", web.Labels, web.DependsOn)
+	}
+
+	if front := project.Networks["front"]; front.Driver != "bridge" || !front.Internal {
+		t.Errorf("front = %+v, want an internal bridge", front)
+	}
+	if cache := project.Volumes["cache"]; cache.Driver != "local" || cache.Labels["keep"] != "yes" {
+		t.Errorf("cache = %+v, want the driver and the labels of both files", cache)
+	}
+}
+
+func TestLoadOverrideCommand(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.yml": "services:\n  app:\n    image: alpine\n    command: [sleep, infinity]\n",
+		"b.yml": "name: Other\nservices:\n  app:\n    command: echo hi\n",
+	})
+	project, err := Load([]string{filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml")}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, _ := project.Service("app")
+	if !slices.Equal(app.Command, []string{"echo", "hi"}) {
+		t.Errorf("command = %q, want the later one", app.Command)
+	}
+	// Networks are named after the project the last file names
+	if project.Name != "other" || project.Networks[DefaultNetwork].Name != "other_default" {
+		t.Errorf("project %q with default network %q", project.Name, project.Networks[DefaultNetwork].Name)
+	}
+}
+
+func TestLoadNetworkMode(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"compose.yml": `
+services:
+  db:
+    image: postgres
+  sidecar:
+    image: busybox
+    network_mode: service:db
+  tool:
+    image: busybox
+    network_mode: host
+`})
+	project, err := Load([]string{filepath.Join(dir, "compose.yml")}, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		service, mode string
+	}{
+		{"db", ""},
+		{"sidecar", "container:shop-db-1"},
+		{"tool", "host"},
+	} {
+		s, _ := project.Service(tc.service)
+		if got := project.NetworkMode(s); got != tc.mode {
+			t.Errorf("%s: network mode %q, want %q", tc.service, got, tc.mode)
+		}
+		// Only services without a mode join the project's networks
+		if joins := len(project.ServiceNetworks(s)) > 0; joins != (tc.mode == "") {
+			t.Errorf("%s joins %v", tc.service, project.ServiceNetworks(s))
+		}
+	}
+
+	// The service whose network is shared starts first
+	order, err := project.StartOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Index(order, "db") > slices.Index(order, "sidecar") {
+		t.Errorf("start order = %q", order)
+	}
+}
+
+func TestLoadNetworkModeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, content, want string
+	}{
+		{"with networks", "services:\n  app:\n    image: alpine\n    network_mode: host\n    networks: [default]\n", "service app: network_mode and networks cannot be combined"},
+		{"undefined service", "services:\n  app:\n    image: alpine\n    network_mode: service:db\n", "service app: network_mode refers to undefined service db"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"compose.yml": tc.content})
+			if _, err := Load([]string{filepath.Join(dir, "compose.yml")}, ""); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadRecordsAbsoluteFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"compose.yml":          "services:\n  app:\n    image: alpine\n",
+		"compose.override.yml": "services:\n  app:\n    user: nobody\n",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	project, err := Load([]string{"compose.yml", "../shop/compose.override.yml"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "compose.yml"), filepath.Join(dir, "compose.override.yml")}
+	if !slices.Equal(project.Files, want) || project.WorkingDir != dir {
+		t.Errorf("files %q in %s, want %q", project.Files, project.WorkingDir, want)
+	}
+}
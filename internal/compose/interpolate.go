@@ -0,0 +1,72 @@
+package compose
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// variableRe matches $$, $VAR, ${VAR}, ${VAR:-default} and ${VAR-default}
+var variableRe = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?-)([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// interpolate substitutes variables in the raw compose file like compose does
+func interpolate(raw string, env map[string]string) string {
+	return variableRe.ReplaceAllStringFunc(raw, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		groups := variableRe.FindStringSubmatch(match)
+		name, op, fallback := groups[1], groups[2], groups[3]
+		if name == "" {
+			name = groups[4]
+		}
+
+		value, ok := env[name]
+		switch {
+		case op == ":-" && value == "":
+			return fallback
+		case op == "-" && !ok:
+			return fallback
+		}
+		return value
+	})
+}
+
+// loadEnv returns the process environment overlaid on the project's .env file
+func loadEnv(workingDir string) map[string]string {
+	env := map[string]string{}
+
+	if file, err := os.Open(filepath.Join(workingDir, ".env")); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+		file.Close()
+	}
+
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/"
+	}
+	return home
+}
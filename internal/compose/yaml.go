@@ -0,0 +1,447 @@
+package compose
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/shellwords"
+	"gopkg.in/yaml.v3"
+)
+
+// composeFile mirrors the subset of the compose file format containix uses
+type composeFile struct {
+	Name     string                `yaml:"name"`
+	Services map[string]rawService `yaml:"services"`
+	Networks map[string]rawNetwork `yaml:"networks"`
+	Volumes  map[string]rawVolume  `yaml:"volumes"`
+}
+
+type rawService struct {
+	Image         string        `yaml:"image"`
+	ContainerName string        `yaml:"container_name"`
+	Command       shellCommand  `yaml:"command"`
+	Entrypoint    shellCommand  `yaml:"entrypoint"`
+	Environment   mappingOrList `yaml:"environment"`
+	Ports         []rawPort     `yaml:"ports"`
+	Volumes       []rawMount    `yaml:"volumes"`
+	Networks      rawNetworks   `yaml:"networks"`
+	NetworkMode   string        `yaml:"network_mode"`
+	Labels        mappingOrList `yaml:"labels"`
+	Restart       string        `yaml:"restart"`
+	DependsOn     namesOrMap    `yaml:"depends_on"`
+	WorkingDir    string        `yaml:"working_dir"`
+	User          string        `yaml:"user"`
+	Hostname      string        `yaml:"hostname"`
+}
+
+type rawNetwork struct {
+	Name     string        `yaml:"name"`
+	Driver   string        `yaml:"driver"`
+	External externalFlag  `yaml:"external"`
+	Internal bool          `yaml:"internal"`
+	Labels   mappingOrList `yaml:"labels"`
+}
+
+type rawVolume struct {
+	Name     string        `yaml:"name"`
+	Driver   string        `yaml:"driver"`
+	External externalFlag  `yaml:"external"`
+	Labels   mappingOrList `yaml:"labels"`
+}
+
+// merge applies the definition of a service in a later file over s the
+// way compose does: scalars and commands are overridden, maps are merged by
+// key and lists are combined, mounts by their target
+func (s rawService) merge(o rawService) rawService {
+	s.Image = cmp.Or(o.Image, s.Image)
+	s.ContainerName = cmp.Or(o.ContainerName, s.ContainerName)
+	s.Restart = cmp.Or(o.Restart, s.Restart)
+	s.WorkingDir = cmp.Or(o.WorkingDir, s.WorkingDir)
+	s.User = cmp.Or(o.User, s.User)
+	s.Hostname = cmp.Or(o.Hostname, s.Hostname)
+	s.NetworkMode = cmp.Or(o.NetworkMode, s.NetworkMode)
+	if o.Command != nil {
+		s.Command = o.Command
+	}
+	if o.Entrypoint != nil {
+		s.Entrypoint = o.Entrypoint
+	}
+
+	s.Environment = s.Environment.merge(o.Environment)
+	s.Labels = s.Labels.merge(o.Labels)
+	if o.Networks != nil {
+		networks := maps.Clone(s.Networks)
+		if networks == nil {
+			networks = rawNetworks{}
+		}
+		maps.Copy(networks, o.Networks)
+		s.Networks = networks
+	}
+
+	s.Ports = slices.Clone(s.Ports)
+	for _, p := range o.Ports {
+		if !slices.Contains(s.Ports, p) {
+			s.Ports = append(s.Ports, p)
+		}
+	}
+	s.DependsOn = slices.Clone(s.DependsOn)
+	for _, dep := range o.DependsOn {
+		if !slices.Contains(s.DependsOn, dep) {
+			s.DependsOn = append(s.DependsOn, dep)
+		}
+	}
+	s.Volumes = slices.Clone(s.Volumes)
+	for _, m := range o.Volumes {
+		i := slices.IndexFunc(s.Volumes, func(v rawMount) bool { return v.target == m.target })
+		if i >= 0 {
+			s.Volumes[i] = m
+		} else {
+			s.Volumes = append(s.Volumes, m)
+		}
+	}
+	return s
+}
+
+// merge applies a network definition from a later file over n
+func (n rawNetwork) merge(o rawNetwork) rawNetwork {
+	n.Name = cmp.Or(o.Name, n.Name)
+	n.Driver = cmp.Or(o.Driver, n.Driver)
+	if o.External.external {
+		n.External = o.External
+	}
+	n.Internal = n.Internal || o.Internal
+	n.Labels = n.Labels.merge(o.Labels)
+	return n
+}
+
+// merge applies a volume definition from a later file over v
+func (v rawVolume) merge(o rawVolume) rawVolume {
+	v.Name = cmp.Or(o.Name, v.Name)
+	v.Driver = cmp.Or(o.Driver, v.Driver)
+	if o.External.external {
+		v.External = o.External
+	}
+	v.Labels = v.Labels.merge(o.Labels)
+	return v
+}
+
+func (s rawService) normalize(name, workingDir string, env map[string]string) Service {
+	service := Service{
+		Name:          name,
+		Image:         s.Image,
+		ContainerName: s.ContainerName,
+		Command:       s.Command,
+		Entrypoint:    s.Entrypoint,
+		Labels:        s.Labels.values(env),
+		Restart:       s.Restart,
+		DependsOn:     s.DependsOn,
+		WorkingDir:    s.WorkingDir,
+		User:          s.User,
+		Hostname:      s.Hostname,
+		Networks:      s.Networks,
+		NetworkMode:   s.NetworkMode,
+	}
+
+	for k, v := range s.Environment.values(env) {
+		service.Environment = append(service.Environment, k+"="+v)
+	}
+	sort.Strings(service.Environment)
+
+	for _, p := range s.Ports {
+		service.Ports = append(service.Ports, string(p))
+	}
+	for _, m := range s.Volumes {
+		service.Volumes = append(service.Volumes, m.bind(workingDir))
+	}
+
+	return service
+}
+
+func (n rawNetwork) normalize(project, key string) Network {
+	network := Network{
+		Name:     n.Name,
+		Driver:   n.Driver,
+		External: n.External.external,
+		Internal: n.Internal,
+		Labels:   n.Labels.values(nil),
+	}
+	if n.External.name != "" {
+		network.Name = n.External.name
+	}
+	if network.Name == "" {
+		network.Name = key
+		if !network.External {
+			network.Name = project + "_" + key
+		}
+	}
+	if network.Driver == "" {
+		network.Driver = "bridge"
+	}
+	return network
+}
+
+func (v rawVolume) normalize(project, key string) Volume {
+	volume := Volume{
+		Name:     v.Name,
+		Driver:   v.Driver,
+		External: v.External.external,
+		Labels:   v.Labels.values(nil),
+	}
+	if v.External.name != "" {
+		volume.Name = v.External.name
+	}
+	if volume.Name == "" {
+		volume.Name = key
+		if !volume.External {
+			volume.Name = project + "_" + key
+		}
+	}
+	return volume
+}
+
+// shellCommand accepts either a string, split like a shell would, or a list
+type shellCommand []string
+
+func (c *shellCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		words, err := shellwords.Split(node.Value)
+		if err != nil {
+			return err
+		}
+		*c = words
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*c = list
+	return nil
+}
+
+// mappingOrList accepts KEY: value maps and KEY=value lists. Entries without
+// a value are kept as nil so they can be filled from the environment.
+type mappingOrList map[string]*string
+
+func (m *mappingOrList) UnmarshalYAML(node *yaml.Node) error {
+	result := mappingOrList{}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Tag == "!!null" {
+				result[key] = nil
+				continue
+			}
+			v := value.Value
+			result[key] = &v
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			key, value, ok := strings.Cut(item.Value, "=")
+			if !ok {
+				result[key] = nil
+				continue
+			}
+			result[key] = &value
+		}
+	default:
+		return fmt.Errorf("line %d: expected a mapping or a list", node.Line)
+	}
+	*m = result
+	return nil
+}
+
+// merge returns the entries of m with those of o on top
+func (m mappingOrList) merge(o mappingOrList) mappingOrList {
+	if o == nil {
+		return m
+	}
+	result := maps.Clone(m)
+	if result == nil {
+		result = mappingOrList{}
+	}
+	maps.Copy(result, o)
+	return result
+}
+
+// values resolves unset entries from env, dropping those that are missing
+func (m mappingOrList) values(env map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		if v != nil {
+			result[k] = *v
+		} else if value, ok := env[k]; ok {
+			result[k] = value
+		}
+	}
+	return result
+}
+
+// namesOrMap accepts a list of names or a map keyed by name
+type namesOrMap []string
+
+func (n *namesOrMap) UnmarshalYAML(node *yaml.Node) error {
+	var names []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			names = append(names, node.Content[i].Value)
+		}
+		sort.Strings(names)
+	default:
+		return fmt.Errorf("line %d: expected a list or a mapping", node.Line)
+	}
+	*n = names
+	return nil
+}
+
+// rawNetworks accepts a list of network names or a map with per-network
+// settings
+type rawNetworks map[string]ServiceNetwork
+
+func (n *rawNetworks) UnmarshalYAML(node *yaml.Node) error {
+	result := rawNetworks{}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			result[item.Value] = ServiceNetwork{}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var settings struct {
+				Aliases     []string `yaml:"aliases"`
+				IPv4Address string   `yaml:"ipv4_address"`
+			}
+			if err := node.Content[i+1].Decode(&settings); err != nil {
+				return err
+			}
+			result[node.Content[i].Value] = ServiceNetwork{
+				Aliases:     settings.Aliases,
+				IPv4Address: settings.IPv4Address,
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or a mapping", node.Line)
+	}
+	*n = result
+	return nil
+}
+
+// externalFlag accepts `external: true` and the legacy `external: {name: x}`
+type externalFlag struct {
+	external bool
+	name     string
+}
+
+func (e *externalFlag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var legacy struct {
+			Name string `yaml:"name"`
+		}
+		if err := node.Decode(&legacy); err != nil {
+			return err
+		}
+		e.external, e.name = true, legacy.Name
+		return nil
+	}
+	return node.Decode(&e.external)
+}
+
+// rawPort accepts short syntax strings and numbers as well as the long
+// syntax, normalized to the docker CLI format
+type rawPort string
+
+func (p *rawPort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = rawPort(node.Value)
+		return nil
+	}
+
+	var long struct {
+		Target    int    `yaml:"target"`
+		Published string `yaml:"published"`
+		Protocol  string `yaml:"protocol"`
+		HostIP    string `yaml:"host_ip"`
+	}
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+
+	spec := strconv.Itoa(long.Target)
+	if long.Published != "" {
+		spec = long.Published + ":" + spec
+		if long.HostIP != "" {
+			spec = long.HostIP + ":" + spec
+		}
+	}
+	if long.Protocol != "" {
+		spec += "/" + long.Protocol
+	}
+	*p = rawPort(spec)
+	return nil
+}
+
+// rawMount accepts short and long volume syntax
+type rawMount struct {
+	source   string
+	target   string
+	readOnly bool
+}
+
+func (m *rawMount) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		switch len(parts) {
+		case 1:
+			m.target = parts[0]
+		default:
+			m.source, m.target = parts[0], parts[1]
+			m.readOnly = len(parts) > 2 && strings.Contains(parts[2], "ro")
+		}
+		return nil
+	}
+
+	var long struct {
+		Source   string `yaml:"source"`
+		Target   string `yaml:"target"`
+		ReadOnly bool   `yaml:"read_only"`
+	}
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+	m.source, m.target, m.readOnly = long.Source, long.Target, long.ReadOnly
+	return nil
+}
+
+// bind formats the mount for HostConfig.Binds, resolving relative host
+// paths against the project directory
+func (m rawMount) bind(workingDir string) string {
+	source := m.source
+	if strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+		if strings.HasPrefix(source, "~") {
+			source = filepath.Join(homeDir(), strings.TrimPrefix(source, "~"))
+		} else {
+			source = filepath.Join(workingDir, source)
+		}
+	}
+
+	bind := m.target
+	if source != "" {
+		bind = source + ":" + m.target
+	}
+	if m.readOnly {
+		bind += ":ro"
+	}
+	return bind
+}
@@ -0,0 +1,347 @@
+// Package config loads containix's user preferences from
+// $XDG_CONFIG_HOME/containix/config.yaml.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the full set of user preferences. Every field has a default, so
+// a config file only needs to list what it changes.
+type Config struct {
+	Theme    string                   `yaml:"theme"`
+	Themes   map[string]theme.Palette `yaml:"themes"`
+	Layout   Layout                   `yaml:"layout"`
+	Refresh  Refresh                  `yaml:"refresh"`
+	Timeouts Timeouts                 `yaml:"timeouts"`
+	Logs     Logs                     `yaml:"logs"`
+	Keymap   Keymap                   `yaml:"keymap"`
+	Confirm  Confirm                  `yaml:"confirm"`
+	Hosts    map[string]Host          `yaml:"hosts"`
+
+	// Keys are the bindings built from the defaults and Keymap
+	Keys keymap.KeyMap `yaml:"-"`
+}
+
+// Keymap overrides key bindings, keyed by scope and action, e.g.
+//
+//	containers:
+//	  stop: s
+//	  restart: [x, ctrl+r]
+type Keymap map[string]map[string]KeyList
+
+// KeyList is one key or a list of keys
+type KeyList []string
+
+func (k *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = KeyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// overrides converts the keymap to the form the keymap package expects
+func (k Keymap) overrides() map[string]map[string][]string {
+	result := make(map[string]map[string][]string, len(k))
+	for scope, actions := range k {
+		result[scope] = make(map[string][]string, len(actions))
+		for name, keys := range actions {
+			result[scope][name] = keys
+		}
+	}
+	return result
+}
+
+// Layout controls how the main screen is divided
+type Layout struct {
+	Mode        string `yaml:"mode"`         // dashboard or classic
+	Split       int    `yaml:"split"`        // width of the container list, in percent
+	StatsHeight int    `yaml:"stats_height"` // height of the stats pane, in percent
+}
+
+// Refresh sets how often data is polled. Zero disables polling.
+type Refresh struct {
+	Containers Duration `yaml:"containers"`
+	Stats      Duration `yaml:"stats"`
+	Config     Duration `yaml:"config"` // how often the config file is checked for changes
+	Health     Duration `yaml:"health"` // how often the daemon is pinged while it answers
+}
+
+// Timeouts bound how long each kind of daemon call may take. Zero means no
+// limit.
+type Timeouts struct {
+	Query   Duration `yaml:"query"`   // lists, inspects, stats, logs and host information
+	Action  Duration `yaml:"action"`  // start, stop and restart, which wait for the stop grace period
+	Compose Duration `yaml:"compose"` // compose up, down and recreate, and running containers, which may pull images
+	Files   Duration `yaml:"files"`   // browsing, downloading and uploading container files
+	Connect Duration `yaml:"connect"` // reaching a daemon picked in the context picker
+}
+
+// Logs controls how logs are fetched
+type Logs struct {
+	Tail int `yaml:"tail"` // lines from the end, 0 for all
+}
+
+// Confirm lists the actions that ask before they run
+type Confirm struct {
+	Stop          bool `yaml:"stop"`
+	Restart       bool `yaml:"restart"`
+	RemoveNetwork bool `yaml:"remove_network"`
+	ComposeDown   bool `yaml:"compose_down"`
+	Recreate      bool `yaml:"recreate"`
+	Overwrite     bool `yaml:"overwrite"`
+}
+
+// Host is a daemon containix can connect to, keyed by a short name
+type Host struct {
+	Address string  `yaml:"address"` // unix://, tcp:// or ssh:// address
+	TLS     HostTLS `yaml:"tls"`
+}
+
+// HostTLS are the certificates for a tcp:// daemon with mutual TLS
+type HostTLS struct {
+	CA         string `yaml:"ca"`
+	Cert       string `yaml:"cert"`
+	Key        string `yaml:"key"`
+	SkipVerify bool   `yaml:"skip_verify"` // do not check the daemon's certificate
+}
+
+// IsZero reports whether no certificates are set
+func (t HostTLS) IsZero() bool {
+	return t.CA == "" && t.Cert == "" && t.Key == ""
+}
+
+// ExpandHome replaces a leading ~ in a path with the home directory
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[1:])
+	}
+	return path
+}
+
+// Duration is a time.Duration written like "5s" or "1m30s"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid duration %q", node.Line, node.Value)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+// Default returns the built-in configuration
+func Default() Config {
+	return Config{
+		Theme: theme.Dark,
+		Layout: Layout{
+			Mode:        "dashboard",
+			Split:       50,
+			StatsHeight: 50,
+		},
+		Refresh: Refresh{
+			Containers: Duration(5 * time.Second),
+			Stats:      Duration(2 * time.Second),
+			Config:     Duration(time.Second),
+			Health:     Duration(5 * time.Second),
+		},
+		Timeouts: Timeouts{
+			Query:   Duration(30 * time.Second),
+			Action:  Duration(2 * time.Minute),
+			Compose: Duration(10 * time.Minute),
+			Files:   Duration(10 * time.Minute),
+			Connect: Duration(10 * time.Second),
+		},
+		Logs: Logs{Tail: 1000},
+		Keys: keymap.Default(),
+		Confirm: Confirm{
+			RemoveNetwork: true,
+			ComposeDown:   true,
+			Recreate:      true,
+			Overwrite:     true,
+		},
+	}
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/containix/config.yaml, falling back
+// to ~/.config when XDG_CONFIG_HOME is unset
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "containix", "config.yaml")
+}
+
+// Load reads the config file at path on top of the defaults. A missing
+// file is not an error and yields the defaults.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Keys, _ = keymap.New(cfg.Keymap.overrides())
+	return cfg, nil
+}
+
+// Validate reports every problem in the configuration at once
+func (c Config) Validate() error {
+	var problems []string
+
+	if err := theme.Validate(c.Themes); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, custom := c.Themes[c.Theme]; !custom {
+		if _, err := theme.Resolve(c.Theme, nil); err != nil {
+			problems = append(problems, "theme: "+err.Error())
+		}
+	}
+
+	switch c.Layout.Mode {
+	case "dashboard", "classic":
+	default:
+		problems = append(problems, fmt.Sprintf("layout.mode: unknown layout %q (want dashboard or classic)", c.Layout.Mode))
+	}
+	if c.Layout.Split < 20 || c.Layout.Split > 80 {
+		problems = append(problems, fmt.Sprintf("layout.split: %d is outside 20-80", c.Layout.Split))
+	}
+	if c.Layout.StatsHeight < 20 || c.Layout.StatsHeight > 80 {
+		problems = append(problems, fmt.Sprintf("layout.stats_height: %d is outside 20-80", c.Layout.StatsHeight))
+	}
+
+	for _, r := range []struct {
+		name string
+		d    Duration
+	}{
+		{"containers", c.Refresh.Containers},
+		{"stats", c.Refresh.Stats},
+		{"config", c.Refresh.Config},
+		{"health", c.Refresh.Health},
+	} {
+		name, d := r.name, r.d
+		if d < 0 || (d > 0 && time.Duration(d) < 500*time.Millisecond) {
+			problems = append(problems, fmt.Sprintf("refresh.%s: %s is too short (minimum 500ms, or 0 to disable)", name, time.Duration(d)))
+		}
+	}
+
+	for _, t := range []struct {
+		name string
+		d    Duration
+	}{
+		{"query", c.Timeouts.Query},
+		{"action", c.Timeouts.Action},
+		{"compose", c.Timeouts.Compose},
+		{"files", c.Timeouts.Files},
+		{"connect", c.Timeouts.Connect},
+	} {
+		if t.d < 0 {
+			problems = append(problems, fmt.Sprintf("timeouts.%s: %s must not be negative (0 for no limit)", t.name, time.Duration(t.d)))
+		}
+	}
+
+	if c.Logs.Tail < 0 {
+		problems = append(problems, fmt.Sprintf("logs.tail: %d must not be negative", c.Logs.Tail))
+	}
+
+	if _, err := keymap.New(c.Keymap.overrides()); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, name := range sortedNames(c.Hosts) {
+		if strings.Contains(name, "/") {
+			// The name prefixes container IDs in a fleet, e.g. build-1/4f2a9c
+			problems = append(problems, fmt.Sprintf("hosts.%s: the name may not contain /", name))
+		}
+		problems = append(problems, c.Hosts[name].validate("hosts."+name)...)
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (h Host) validate(prefix string) []string {
+	var problems []string
+	scheme, _, found := strings.Cut(h.Address, "://")
+	switch {
+	case h.Address == "":
+		problems = append(problems, prefix+".address: required")
+	case !found:
+		problems = append(problems, fmt.Sprintf("%s.address: %q has no scheme (want unix://, tcp:// or ssh://)", prefix, h.Address))
+	case scheme != "unix" && scheme != "tcp" && scheme != "ssh" && scheme != "npipe":
+		problems = append(problems, fmt.Sprintf("%s.address: unsupported scheme %q (want unix, tcp or ssh)", prefix, scheme))
+	}
+
+	if h.TLS.IsZero() {
+		return problems
+	}
+	if scheme != "tcp" {
+		problems = append(problems, prefix+".tls: only applies to tcp:// addresses")
+	}
+	if (h.TLS.Cert == "") != (h.TLS.Key == "") {
+		problems = append(problems, prefix+".tls: cert and key must be set together")
+	}
+	if h.TLS.CA == "" && !h.TLS.SkipVerify {
+		problems = append(problems, prefix+".tls.ca: required unless skip_verify is set")
+	}
+	for _, file := range []struct{ name, path string }{{"ca", h.TLS.CA}, {"cert", h.TLS.Cert}, {"key", h.TLS.Key}} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(ExpandHome(file.path)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.tls.%s: %v", prefix, file.name, err))
+		}
+	}
+	return problems
+}
+
+// sortedNames returns the keys of a map in order, so problems are reported
+// the same way every time
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+theme: light
+refresh:
+  stats: 1s
+keymap:
+  containers:
+    stop: [S, ctrl+s]
+hosts:
+  build:
+    address: ssh://ci@build.example.com
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != "light" || time.Duration(cfg.Refresh.Stats) != time.Second {
+		t.Errorf("theme %q, stats refresh %s", cfg.Theme, time.Duration(cfg.Refresh.Stats))
+	}
+	// What the file does not set keeps its default
+	if cfg.Refresh.Containers != Default().Refresh.Containers || cfg.Logs.Tail != 1000 {
+		t.Errorf("defaults lost: %+v", cfg.Refresh)
+	}
+	if keys := cfg.Keys.Get(keymap.Containers, "stop").Keys(); !slices.Equal(keys, []string{"S", "ctrl+s"}) {
+		t.Errorf("stop is bound to %q, want the override", keys)
+	}
+	if cfg.Hosts["build"].Address != "ssh://ci@build.example.com" {
+		t.Errorf("hosts = %+v", cfg.Hosts)
+	}
+
+	// A missing file is the defaults
+	if cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || cfg.Theme != Default().Theme {
+		t.Errorf("missing file: %+v, %v", cfg.Theme, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", "refrsh:\n  stats: 1s\n", "field refrsh not found"},
+		{"unknown nested key", "layout:\n  width: 50\n", "field width not found"},
+		{"bad duration", "refresh:\n  stats: soon\n", `invalid duration "soon"`},
+		{"duration without unit", "timeouts:\n  query: 30\n", `invalid duration "30"`},
+		{"too short refresh", "refresh:\n  containers: 100ms\n", "refresh.containers: 100ms is too short"},
+		{"negative timeout", "timeouts:\n  action: -1s\n", "timeouts.action: -1s must not be negative"},
+		{"negative connect timeout", "timeouts:\n  connect: -1s\n", "timeouts.connect: -1s must not be negative"},
+		{"unknown layout", "layout:\n  mode: grid\n", `unknown layout "grid"`},
+		{"unknown theme", "theme: neon\n", "theme:"},
+		{"host without scheme", "hosts:\n  prod:\n    address: prod.example.com\n", `hosts.prod.address: "prod.example.com" has no scheme`},
+		{"host with bad scheme", "hosts:\n  prod:\n    address: http://prod:2375\n", `unsupported scheme "http"`},
+		{"host without address", "hosts:\n  prod: {}\n", "hosts.prod.address: required"},
+		{"host name with slash", "hosts:\n  a/b:\n    address: unix:///var/run/docker.sock\n", "hosts.a/b: the name may not contain /"},
+		{"tls on ssh", "hosts:\n  prod:\n    address: ssh://prod\n    tls: {ca: ca.pem}\n", "hosts.prod.tls: only applies to tcp://"},
+		{"cert without key", "hosts:\n  prod:\n    address: tcp://prod:2376\n    tls: {cert: cert.pem, skip_verify: true}\n", "cert and key must be set together"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := Load(writeConfig(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want it to mention %q", err, tc.want)
+			}
+			// A broken file falls back to the defaults as a whole
+			if cfg.Layout != Default().Layout || cfg.Theme != Default().Theme {
+				t.Errorf("got %+v alongside the error, want the defaults", cfg.Layout)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.Layout.Split = 95
+	cfg.Logs.Tail = -1
+	cfg.Hosts = map[string]Host{"b": {}, "a": {Address: "ftp://a"}}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("no problems found")
+	}
+	want := []string{"layout.split: 95 is outside 20-80", "logs.tail: -1", "hosts.a.address", "hosts.b.address"}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(err.Error(), w)
+		if i < 0 {
+			t.Errorf("%q is missing from %v", w, err)
+		}
+		if i < last {
+			t.Errorf("%q is out of order in %v", w, err)
+		}
+		last = i
+	}
+}
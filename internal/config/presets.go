@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preset is a saved answer to the run container wizard, kept as typed
+type Preset struct {
+	Image   string   `yaml:"image"`
+	Name    string   `yaml:"name,omitempty"`
+	Command string   `yaml:"command,omitempty"`
+	Env     []string `yaml:"env,omitempty"`
+	Ports   []string `yaml:"ports,omitempty"`
+	Volumes []string `yaml:"volumes,omitempty"`
+	Network string   `yaml:"network,omitempty"`
+	Restart string   `yaml:"restart,omitempty"`
+	CPUs    string   `yaml:"cpus,omitempty"`
+	Memory  string   `yaml:"memory,omitempty"`
+}
+
+// PresetsPath returns the presets file that sits next to the config file
+func PresetsPath(configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configPath), "presets.yaml")
+}
+
+// LoadPresets reads the presets file by name. A missing file has none.
+func LoadPresets(path string) (map[string]Preset, error) {
+	presets := map[string]Preset{}
+	if path == "" {
+		return presets, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return presets, nil
+	}
+	if err != nil {
+		return presets, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&presets); err != nil && !errors.Is(err, io.EOF) {
+		return map[string]Preset{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if presets == nil {
+		presets = map[string]Preset{}
+	}
+	return presets, nil
+}
+
+// SavePreset adds or replaces a preset in the presets file. The file may
+// hold secrets in environment variables, so only the user can read it.
+func SavePreset(path, name string, preset Preset) error {
+	if path == "" {
+		return errors.New("presets need a config directory")
+	}
+	if name == "" {
+		return errors.New("a preset needs a name")
+	}
+	presets, err := LoadPresets(path)
+	if err != nil {
+		return err
+	}
+	presets[name] = preset
+
+	raw, err := yaml.Marshal(presets)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// A rename replaces the file whole, so a crash never leaves half of it
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".presets-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// PresetNames returns the names of presets in order
+func PresetNames(presets map[string]Preset) []string {
+	return sortedNames(presets)
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Stamp identifies a version of the config file on disk
+type Stamp struct {
+	Exists  bool
+	ModTime time.Time
+	Size    int64
+}
+
+// StatStamp returns the current stamp of the file at path
+func StatStamp(path string) Stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Stamp{}
+	}
+	return Stamp{Exists: true, ModTime: info.ModTime(), Size: info.Size()}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatStamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if stamp := StatStamp(path); stamp.Exists {
+		t.Fatalf("stamp of a missing file = %+v", stamp)
+	}
+
+	write := func(content string, modTime time.Time) Stamp {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return StatStamp(path)
+	}
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := write("theme: dark\n", base)
+	if !created.Exists {
+		t.Fatal("the created file has no stamp")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		change  func() Stamp
+		changed bool
+	}{
+		{"untouched", func() Stamp { return StatStamp(path) }, false},
+		{"same size, new time", func() Stamp { return write("theme: ligh\n", base.Add(time.Second)) }, true},
+		// Editors that keep the time still change the size
+		{"new size, same time", func() Stamp { return write("theme: light\n", base) }, true},
+		{"removed", func() Stamp { os.Remove(path); return StatStamp(path) }, true},
+	} {
+		if got := tc.change(); (got != created) != tc.changed {
+			t.Errorf("%s: stamp %+v against %+v, want changed %v", tc.name, got, created, tc.changed)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/docker/fake"
+	"github.com/shubhamku044/containix/internal/shellwords"
+)
+
+// tick is how often the simulation moves forward while the demo runs
+const tick = 250 * time.Millisecond
+
+// New builds the scenario's host with its warmup already simulated, so
+// logs and stats have history when the UI opens at now
+func New(s *Scenario, now time.Time) *fake.Runtime {
+	warmup := time.Duration(s.Warmup)
+	r := fake.New(now.Add(-warmup))
+
+	for _, p := range s.Projects {
+		network := p.Name + "_default"
+		if _, err := r.CreateNetwork(context.Background(), docker.NetworkOptions{Name: network}); err != nil {
+			slog.Warn("demo network not created", "network", network, "error", err)
+		}
+		for _, svc := range p.Services {
+			for i := 1; i <= max(svc.Replicas, 1); i++ {
+				spec := svc.spec(replicaName(p.Name, svc.Name, i))
+				spec.Networks = []string{network}
+				spec.Labels = map[string]string{
+					docker.ComposeProjectLabel: p.Name,
+					docker.ComposeServiceLabel: svc.Name,
+					docker.ComposeNumberLabel:  strconv.Itoa(i),
+				}
+				for k, v := range svc.Labels {
+					spec.Labels[k] = v
+				}
+				r.AddContainer(spec)
+			}
+		}
+	}
+	for _, c := range s.Containers {
+		r.AddContainer(c.spec(c.Name))
+	}
+
+	r.Advance(warmup)
+	return r
+}
+
+// spec converts a scenario service into a simulated container
+func (svc Service) spec(name string) fake.ContainerSpec {
+	// Validated with the scenario
+	command, _ := shellwords.Split(svc.Command)
+	spec := fake.ContainerSpec{
+		Name:         name,
+		Image:        svc.Image,
+		Command:      command,
+		Env:          svc.Env,
+		Status:       svc.Status,
+		Labels:       svc.Labels,
+		CPU:          svc.CPU,
+		Memory:       uint64(svc.Memory),
+		MemoryLimit:  uint64(svc.MemoryLimit),
+		PIDs:         svc.PIDs,
+		LogLines:     svc.Logs,
+		LogEvery:     time.Duration(svc.LogEvery),
+		Restart:      svc.Restart,
+		CrashAfter:   time.Duration(svc.CrashAfter),
+		CrashLog:     svc.CrashLog,
+		MemoryGrowth: uint64(svc.MemoryGrowth),
+		Files: map[string]string{
+			"/etc/hostname":   name + "\n",
+			"/etc/os-release": "NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.19.1\n",
+		},
+	}
+	for p, content := range svc.Files {
+		spec.Files[p] = content
+	}
+	for _, p := range svc.Ports {
+		port, _ := parsePort(p)
+		spec.Ports = append(spec.Ports, port)
+	}
+	return spec
+}
+
+// parsePort reads a port like the compose short syntax: 80, 8080:80 or
+// 8080:80/udp
+func parsePort(spec string) (docker.Port, error) {
+	port := docker.Port{Type: "tcp"}
+	rest, proto, ok := strings.Cut(spec, "/")
+	if ok {
+		port.Type = proto
+	}
+	public, private, published := strings.Cut(rest, ":")
+	if !published {
+		private = public
+	}
+	n, err := strconv.ParseUint(private, 10, 16)
+	if err != nil {
+		return docker.Port{}, fmt.Errorf("invalid port %q", spec)
+	}
+	port.PrivatePort = uint16(n)
+	if published {
+		n, err := strconv.ParseUint(public, 10, 16)
+		if err != nil {
+			return docker.Port{}, fmt.Errorf("invalid port %q", spec)
+		}
+		port.IP = "0.0.0.0"
+		port.PublicPort = uint16(n)
+	}
+	return port, nil
+}
+
+// Run moves the simulation along in real time, scaled by the scenario's
+// speed, and plays its timeline. It returns when stop is closed.
+func Run(r *fake.Runtime, s *Scenario, stop <-chan struct{}) {
+	speed := s.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	start := r.Now()
+	next := make([]time.Time, len(s.Timeline))
+	for i, step := range s.Timeline {
+		next[i] = start.Add(time.Duration(step.At))
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		r.Advance(time.Duration(float64(tick) * speed))
+		now := r.Now()
+		for i, step := range s.Timeline {
+			if next[i].IsZero() || next[i].After(now) {
+				continue
+			}
+			if err := play(r, step); err != nil {
+				slog.Warn("demo step failed", "container", step.Container, "action", step.Action, "error", err)
+			}
+			next[i] = time.Time{}
+			if step.Every > 0 {
+				next[i] = now.Add(time.Duration(step.Every))
+			}
+		}
+	}
+}
+
+// play applies a timeline step. It bypasses read-only mode, which only
+// restricts what the user can do.
+func play(r *fake.Runtime, step Step) error {
+	switch step.Action {
+	case "start":
+		return r.SetStatus(step.Container, fake.StatusRunning)
+	case "stop":
+		return r.SetStatus(step.Container, fake.StatusExited)
+	case "restart":
+		if err := r.SetStatus(step.Container, fake.StatusExited); err != nil {
+			return err
+		}
+		return r.SetStatus(step.Container, fake.StatusRunning)
+	case "crash":
+		return r.Crash(step.Container, step.Message)
+	case "oom":
+		return r.OOM(step.Container)
+	case "log":
+		return r.AppendLog(step.Container, step.Message)
+	}
+	return fmt.Errorf("unknown action %q", step.Action)
+}
@@ -0,0 +1,49 @@
+package demo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSpecCommand(t *testing.T) {
+	svc := Service{Command: `sh -c 'echo "hello world"' --name=a\ b`}
+	got := svc.spec("web").Command
+	want := []string{"sh", "-c", `echo "hello world"`, "--name=a b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+		err  bool
+	}{
+		{spec: "80", want: "80/tcp"},
+		{spec: "8080:80", want: "0.0.0.0:8080->80/tcp"},
+		{spec: "53/udp", want: "53/udp"},
+		{spec: "5353:53/udp", want: "0.0.0.0:5353->53/udp"},
+		{spec: "http", err: true},
+		{spec: "x:80", err: true},
+		{spec: "70000", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			port, err := parsePort(tt.spec)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("got %v, want an error", port)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if port.String() != tt.want {
+				t.Errorf("port = %s, want %s", port, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Package demo builds a simulated Docker host from a scenario file, so the
+// UI can be shown without a daemon. Scenarios describe compose projects,
+// standalone containers and a timeline of things that happen to them.
+package demo
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/shellwords"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed scenarios/*.yaml
+var builtins embed.FS
+
+// DefaultScenario is the scenario used when none is given
+const DefaultScenario = "default"
+
+// Scenario is a simulated host
+type Scenario struct {
+	Warmup     config.Duration `yaml:"warmup"` // history simulated before the UI opens
+	Speed      float64         `yaml:"speed"`  // simulated seconds per real second
+	Projects   []Project       `yaml:"projects"`
+	Containers []Service       `yaml:"containers"` // standalone containers
+	Timeline   []Step          `yaml:"timeline"`
+}
+
+// Project is a compose project
+type Project struct {
+	Name     string    `yaml:"name"`
+	Services []Service `yaml:"services"`
+}
+
+// Service is a compose service or a standalone container
+type Service struct {
+	Name        string            `yaml:"name"`
+	Image       string            `yaml:"image"`
+	Command     string            `yaml:"command"`
+	Replicas    int               `yaml:"replicas"`
+	Status      string            `yaml:"status"` // running by default
+	Ports       []string          `yaml:"ports"`  // e.g. 8080:80 or 53/udp
+	Env         []string          `yaml:"env"`
+	Labels      map[string]string `yaml:"labels"`
+	Files       map[string]string `yaml:"files"`
+	CPU         float64           `yaml:"cpu"` // percent
+	Memory      Bytes             `yaml:"memory"`
+	MemoryLimit Bytes             `yaml:"memory_limit"`
+	PIDs        uint64            `yaml:"pids"`
+	Logs        []string          `yaml:"logs"` // emitted in turn, {n} counts lines
+	LogEvery    config.Duration   `yaml:"log_every"`
+
+	Restart      string          `yaml:"restart"`
+	CrashAfter   config.Duration `yaml:"crash_after"`
+	CrashLog     string          `yaml:"crash_log"`
+	MemoryGrowth Bytes           `yaml:"memory_growth"` // per second
+}
+
+// Step is something that happens to a container at a point in the demo
+type Step struct {
+	At        config.Duration `yaml:"at"`    // after the UI opens
+	Every     config.Duration `yaml:"every"` // repeat interval, 0 for once
+	Container string          `yaml:"container"`
+	Action    string          `yaml:"action"` // start, stop, restart, crash, oom or log
+	Message   string          `yaml:"message"`
+}
+
+// Bytes is a size written like "512Mi", "2G" or "1024"
+type Bytes uint64
+
+var byteUnits = map[string]float64{
+	"":   1,
+	"B":  1,
+	"K":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+}
+
+func (b *Bytes) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimSpace(node.Value)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(value)
+	}
+	n, err := strconv.ParseFloat(value[:i], 64)
+	unit, ok := byteUnits[strings.TrimSpace(value[i:])]
+	if err != nil || !ok {
+		return fmt.Errorf("line %d: invalid size %q", node.Line, node.Value)
+	}
+	*b = Bytes(n * unit)
+	return nil
+}
+
+// Names returns the built-in scenario names
+func Names() []string {
+	entries, _ := builtins.ReadDir("scenarios")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load reads a built-in scenario by name, or a scenario file by path
+func Load(nameOrPath string) (*Scenario, error) {
+	if nameOrPath == "" {
+		nameOrPath = DefaultScenario
+	}
+	data, err := builtins.ReadFile(path.Join("scenarios", nameOrPath+".yaml"))
+	if err != nil {
+		data, err = os.ReadFile(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: not a file or one of %s", nameOrPath, strings.Join(Names(), ", "))
+		}
+	}
+
+	var s Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("scenario %s: %w", nameOrPath, err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", nameOrPath, err)
+	}
+	return &s, nil
+}
+
+// containerNames returns the name of every container the scenario creates
+func (s *Scenario) containerNames() map[string]bool {
+	names := map[string]bool{}
+	for _, p := range s.Projects {
+		for _, svc := range p.Services {
+			for i := 1; i <= max(svc.Replicas, 1); i++ {
+				names[replicaName(p.Name, svc.Name, i)] = true
+			}
+		}
+	}
+	for _, c := range s.Containers {
+		names[c.Name] = true
+	}
+	return names
+}
+
+func replicaName(project, service string, n int) string {
+	return fmt.Sprintf("%s-%s-%d", project, service, n)
+}
+
+func (s *Scenario) validate() error {
+	var problems []string
+	if s.Speed < 0 {
+		problems = append(problems, "speed: must not be negative")
+	}
+	for _, p := range s.Projects {
+		if p.Name == "" {
+			problems = append(problems, "projects: a project has no name")
+		}
+		for _, svc := range p.Services {
+			problems = append(problems, svc.validate("projects."+p.Name+"."+svc.Name)...)
+		}
+	}
+	for _, c := range s.Containers {
+		problems = append(problems, c.validate("containers."+c.Name)...)
+	}
+
+	names := s.containerNames()
+	for i, step := range s.Timeline {
+		field := fmt.Sprintf("timeline[%d]", i)
+		if !names[step.Container] {
+			problems = append(problems, fmt.Sprintf("%s.container: unknown container %q", field, step.Container))
+		}
+		switch step.Action {
+		case "start", "stop", "restart", "crash", "oom", "log":
+		default:
+			problems = append(problems, fmt.Sprintf("%s.action: unknown action %q (want start, stop, restart, crash, oom or log)", field, step.Action))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (svc Service) validate(field string) []string {
+	var problems []string
+	if svc.Name == "" {
+		problems = append(problems, field+": missing name")
+	}
+	if svc.Replicas < 0 {
+		problems = append(problems, field+".replicas: must not be negative")
+	}
+	if _, err := shellwords.Split(svc.Command); err != nil {
+		problems = append(problems, fmt.Sprintf("%s.command: %v", field, err))
+	}
+	switch svc.Status {
+	case "", "created", "running", "paused", "exited", "dead":
+	default:
+		problems = append(problems, fmt.Sprintf("%s.status: unknown status %q", field, svc.Status))
+	}
+	switch svc.Restart {
+	case "", "no", "always", "on-failure", "unless-stopped":
+	default:
+		problems = append(problems, fmt.Sprintf("%s.restart: unknown policy %q", field, svc.Restart))
+	}
+	for _, p := range svc.Ports {
+		if _, err := parsePort(p); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.ports: %v", field, err))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,205 @@
+package demo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuiltinsLoad(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("no built-in scenarios")
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			s, err := Load(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s.containerNames()) == 0 {
+				t.Error("scenario has no containers")
+			}
+		})
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	if _, err := Load(""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scenario.yaml")
+	data := `speed: 2
+projects:
+  - name: shop
+    services:
+      - name: api
+        image: shop/api
+        command: serve --addr ':8080' "--name=shop api"
+        replicas: 2
+        memory: 64Mi
+containers:
+  - name: cache
+    image: redis
+timeline:
+  - at: 5s
+    container: shop-api-2
+    action: crash
+`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Speed != 2 || len(s.Projects) != 1 || len(s.Containers) != 1 || len(s.Timeline) != 1 {
+		t.Errorf("scenario = %+v", s)
+	}
+	if s.Projects[0].Services[0].Memory != 64<<20 {
+		t.Errorf("memory = %d, want %d", s.Projects[0].Services[0].Memory, 64<<20)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		err      string
+	}{
+		{
+			name:     "unknown field",
+			scenario: "containers:\n  - name: web\n    imgae: nginx\n",
+			err:      "field imgae not found",
+		},
+		{
+			name:     "negative speed",
+			scenario: "speed: -1\n",
+			err:      "speed: must not be negative",
+		},
+		{
+			name:     "unnamed project",
+			scenario: "projects:\n  - services: []\n",
+			err:      "projects: a project has no name",
+		},
+		{
+			name:     "unnamed container",
+			scenario: "containers:\n  - image: nginx\n",
+			err:      "containers.: missing name",
+		},
+		{
+			name:     "negative replicas",
+			scenario: "projects:\n  - name: shop\n    services:\n      - name: api\n        replicas: -1\n",
+			err:      "projects.shop.api.replicas: must not be negative",
+		},
+		{
+			name:     "unknown status",
+			scenario: "containers:\n  - name: web\n    status: sleeping\n",
+			err:      `containers.web.status: unknown status "sleeping"`,
+		},
+		{
+			name:     "unknown restart policy",
+			scenario: "containers:\n  - name: web\n    restart: sometimes\n",
+			err:      `containers.web.restart: unknown policy "sometimes"`,
+		},
+		{
+			name:     "invalid port",
+			scenario: "containers:\n  - name: web\n    ports: [\"http\"]\n",
+			err:      `containers.web.ports: invalid port "http"`,
+		},
+		{
+			name:     "unterminated quote",
+			scenario: "containers:\n  - name: web\n    command: sh -c 'echo\n",
+			err:      "containers.web.command:",
+		},
+		{
+			name:     "invalid size",
+			scenario: "containers:\n  - name: web\n    memory: 2X\n",
+			err:      `invalid size "2X"`,
+		},
+		{
+			name:     "unknown timeline container",
+			scenario: "containers:\n  - name: web\ntimeline:\n  - container: db\n    action: stop\n",
+			err:      `timeline[0].container: unknown container "db"`,
+		},
+		{
+			name:     "unknown timeline action",
+			scenario: "containers:\n  - name: web\ntimeline:\n  - container: web\n    action: explode\n",
+			err:      `timeline[0].action: unknown action "explode"`,
+		},
+		{
+			name:     "every problem at once",
+			scenario: "speed: -1\ncontainers:\n  - name: web\n    status: sleeping\n",
+			err:      `speed: must not be negative; containers.web.status: unknown status "sleeping"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "scenario.yaml")
+			if err := os.WriteFile(file, []byte(tt.scenario), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(file)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "not a file or one of") {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Bytes
+		err   bool
+	}{
+		{value: "1024", want: 1024},
+		{value: "512B", want: 512},
+		{value: "2K", want: 2000},
+		{value: "1.5M", want: 1500000},
+		{value: "2G", want: 2e9},
+		{value: "4Ki", want: 4096},
+		{value: "512Mi", want: 512 << 20},
+		{value: "1.5Gi", want: 3 << 29},
+		{value: "64 Mi", want: 64 << 20},
+		{value: "0", want: 0},
+		{value: "Mi", err: true},
+		{value: "2X", err: true},
+		{value: "2mi", err: true},
+		{value: "-1", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var b Bytes
+			err := yaml.Unmarshal([]byte("'"+tt.value+"'"), &b)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("got %d, want an error", b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b != tt.want {
+				t.Errorf("got %d, want %d", b, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Capabilities describes the connected daemon, from /version and /info
+type Capabilities struct {
+	APIVersion    string // API version requests use
+	OSType        string // "linux" or "windows"
+	CgroupVersion string // "1" or "2", empty on Windows
+	StorageDriver string
+	Swarm         string // local node state: inactive, active, pending, ...
+	Rootless      bool
+	Experimental  bool
+	Podman        bool
+}
+
+// DefaultCapabilities are assumed until the daemon has answered: a Linux
+// daemon that can do everything containix asks of it
+func DefaultCapabilities() Capabilities {
+	return Capabilities{OSType: "linux", CgroupVersion: "2", Swarm: "inactive"}
+}
+
+// Feature is something a daemon may not be able to do
+type Feature int
+
+const (
+	// FeatureStats is per-container CPU and memory usage
+	FeatureStats Feature = iota
+	// FeatureFiles is browsing, copying and uploading container files
+	FeatureFiles
+	// FeatureOverlayNetworks is creating networks that span hosts
+	FeatureOverlayNetworks
+	// FeatureResourceLimits is changing CPU and memory limits
+	FeatureResourceLimits
+)
+
+// UnsupportedError says why the daemon cannot do something. It matches
+// ErrUnsupported with errors.Is.
+type UnsupportedError struct {
+	Feature Feature
+	Reason  string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s not available: %s", e.Feature, e.Reason)
+}
+
+func (e *UnsupportedError) Unwrap() error { return ErrUnsupported }
+
+// Supports returns nil when the daemon can do f, otherwise an
+// *UnsupportedError
+func (c Capabilities) Supports(f Feature) error {
+	var reason string
+	switch f {
+	case FeatureStats, FeatureResourceLimits:
+		// Rootless daemons only get cgroups delegated on cgroup v2
+		if c.Rootless && c.CgroupVersion == "1" {
+			reason = "a rootless daemon needs cgroup v2"
+		}
+	case FeatureFiles:
+		if c.OSType == "windows" {
+			reason = "the daemon runs Windows containers"
+		}
+	case FeatureOverlayNetworks:
+		if c.Swarm != "active" {
+			reason = "swarm mode is not active"
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+	return &UnsupportedError{Feature: f, Reason: reason}
+}
+
+func (f Feature) String() string {
+	switch f {
+	case FeatureStats:
+		return "container stats"
+	case FeatureFiles:
+		return "file browsing"
+	case FeatureOverlayNetworks:
+		return "overlay networks"
+	case FeatureResourceLimits:
+		return "changing resource limits"
+	}
+	return fmt.Sprintf("feature %d", int(f))
+}
+
+// DefaultNetworkDriver is the driver of networks created without one
+func (c Capabilities) DefaultNetworkDriver() string {
+	if c.OSType == "windows" {
+		return "nat"
+	}
+	return "bridge"
+}
+
+// Capabilities asks the daemon what it is and what it can do. The first
+// call also negotiates the API version.
+func (c *Client) Capabilities(ctx context.Context) (_ Capabilities, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	version, err := c.api(ctx).ServerVersion(ctx)
+	if err != nil {
+		return DefaultCapabilities(), explainTLS(c.host, err)
+	}
+	info, err := c.api(ctx).Info(ctx)
+	if err != nil {
+		return DefaultCapabilities(), explainTLS(c.host, err)
+	}
+
+	caps := Capabilities{
+		APIVersion:    c.api(ctx).ClientVersion(),
+		OSType:        info.OSType,
+		CgroupVersion: info.CgroupVersion,
+		StorageDriver: info.Driver,
+		Swarm:         string(info.Swarm.LocalNodeState),
+		Experimental:  version.Experimental || info.ExperimentalBuild,
+		Podman:        c.Podman(),
+	}
+	if caps.CgroupVersion == "" && caps.OSType == "linux" {
+		// Daemons before API 1.40 only ran on cgroup v1
+		caps.CgroupVersion = "1"
+	}
+	for _, opt := range info.SecurityOptions {
+		if strings.Contains(opt, "name=rootless") {
+			caps.Rootless = true
+		}
+	}
+	for _, component := range version.Components {
+		if component.Name == "Podman Engine" {
+			caps.Podman = true
+		}
+	}
+	return caps, nil
+}
@@ -3,8 +3,14 @@ package docker
 import (
 	"context"
 	"encoding/json"
-	"io"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
@@ -12,45 +18,237 @@ import (
 
 // Client wraps the Docker client and provides container operations
 type Client struct {
-	client *client.Client
+	client   *client.Client
+	host     string
+	context  string
+	tls      *TLSFiles  // set for tcp:// hosts with TLS
+	tunnel   *sshTunnel // set for ssh:// hosts
+	readOnly bool
+	timeouts Timeouts
+
+	mu        sync.Mutex
+	detected  bool          // the API version was negotiated with the daemon
+	detecting chan struct{} // closed when the ping in flight is answered
+	libpod    string        // libpod API version, set when the daemon is Podman
+}
+
+// Options selects the daemon a Client talks to
+type Options struct {
+	Host     string              // daemon address, e.g. unix:///var/run/docker.sock
+	Context  string              // docker CLI context, used when Host is empty
+	TLS      map[string]TLSFiles // certificates by daemon address
+	ReadOnly bool                // reject every operation that changes state
+	Timeouts Timeouts            // per kind of operation, zero for no limit
 }
 
-// NewClient creates a new Docker client
-func NewClient() (*Client, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.41"))
+// ErrReadOnly is returned by mutating operations on a read-only client
+var ErrReadOnly = errors.New("containix is running in read-only mode")
+
+// Target describes the daemon the options select for display: the host,
+// or the name of the context
+func (o Options) Target() string {
+	switch {
+	case o.Host != "":
+		return o.Host
+	case o.Context != "":
+		return o.Context
+	case os.Getenv("DOCKER_HOST") != "":
+		return os.Getenv("DOCKER_HOST")
+	}
+	return CurrentContext()
+}
+
+// NewClient creates a new Docker client. Without a host or context the
+// daemon is chosen like the docker CLI does: DOCKER_HOST, then
+// DOCKER_CONTEXT, then the current context of ~/.docker/config.json.
+func NewClient(opts Options) (*Client, error) {
+	host, name := opts.Host, ""
+	if host == "" {
+		name = opts.Context
+		if name == "" && os.Getenv("DOCKER_HOST") == "" {
+			name = CurrentContext()
+		}
+		resolved, err := ContextHost(name)
+		if err != nil {
+			return nil, err
+		}
+		host = resolved
+	}
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+	if host == "" {
+		host = localHost()
+	}
+
+	// The version is negotiated on first use unless it is pinned
+	var clientOpts []client.Opt
+	if v := os.Getenv("DOCKER_API_VERSION"); v != "" {
+		clientOpts = append(clientOpts, client.WithVersion(v))
+	}
+
+	var tunnel *sshTunnel
+	files := resolveTLS(host, name, opts.TLS)
+	switch {
+	case strings.HasPrefix(host, "ssh://"):
+		var err error
+		if tunnel, err = newSSHTunnel(host); err != nil {
+			return nil, err
+		}
+		// Requests go through the tunnel, the URL host is only a placeholder.
+		// The HTTP client comes last so no proxy settings apply to it.
+		clientOpts = append(clientOpts,
+			client.WithHost("http://docker.example.com"),
+			client.WithHTTPClient(&http.Client{Transport: &http.Transport{DialContext: tunnel.DialContext}}))
+	case files != nil:
+		cfg, err := files.config()
+		if err != nil {
+			return nil, fmt.Errorf("TLS for %s: %w", host, err)
+		}
+		// The host is applied to the TLS transport, so it comes second
+		clientOpts = append(clientOpts, client.WithHTTPClient(tlsHTTPClient(cfg)), client.WithHost(host))
+	default:
+		clientOpts = append(clientOpts, client.WithHost(host))
+	}
+	cli, err := client.NewClientWithOpts(clientOpts...)
 	if err != nil {
 		return nil, err
 	}
+	slog.Debug("docker client created", "host", host, "context", name, "tls", files != nil, "readonly", opts.ReadOnly)
 
 	return &Client{
-		client: cli,
+		client:   cli,
+		host:     host,
+		context:  name,
+		tls:      files,
+		tunnel:   tunnel,
+		readOnly: opts.ReadOnly,
+		timeouts: opts.Timeouts,
 	}, nil
 }
 
+// Ping checks that the daemon answers. A failed ping leaves the client
+// open: the connection belongs to whoever created the Client, and the
+// transport dials again for the next request.
+func (c *Client) Ping(ctx context.Context) (err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	_, err = c.api(ctx).Ping(ctx)
+	return explainTLS(c.host, err)
+}
+
+// Connect opens a client and checks that the daemon answers within the
+// connect timeout, for a daemon picked while containix runs
+func Connect(ctx context.Context, opts Options) (_ *Client, err error) {
+	client, err := NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done := limit(ctx, opts.Timeouts.Connect)
+	defer done(&err)
+	// Creating a client does not connect, so make sure the daemon answers
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Close releases the connection to the daemon
+func (c *Client) Close() error {
+	if c.tunnel != nil {
+		c.tunnel.Close()
+	}
+	return c.client.Close()
+}
+
+// ReadOnly reports whether mutating operations are disabled
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
+
+// checkWritable guards every operation that changes daemon state
+func (c *Client) checkWritable(op string) error {
+	if c.readOnly {
+		slog.Warn("blocked by read-only mode", "operation", op)
+		return ErrReadOnly
+	}
+	return nil
+}
+
 // Container represents a Docker container
 type Container struct {
 	ID     string
 	Name   string
 	Status string
+	Ports  []Port
+	Labels map[string]string
+	Host   string // fleet host the container runs on, empty for one daemon
+	Pod    string // Podman pod the container belongs to
+}
+
+// Ref is how commands refer to the container: its name, qualified with the
+// host when it belongs to a fleet
+func (c Container) Ref() string {
+	if c.Host == "" {
+		return c.Name
+	}
+	return c.Host + hostSeparator + c.Name
+}
+
+// Port is a container port, optionally published on the host
+type Port struct {
+	IP          string
+	PrivatePort uint16
+	PublicPort  uint16
+	Type        string
+}
+
+// IsPublished reports whether the port is bound on the host
+func (p Port) IsPublished() bool {
+	return p.PublicPort != 0
+}
+
+// String formats the port like the docker CLI, e.g. 0.0.0.0:8080->80/tcp
+func (p Port) String() string {
+	if !p.IsPublished() {
+		return fmt.Sprintf("%d/%s", p.PrivatePort, p.Type)
+	}
+	return fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type)
 }
 
 // ListContainers returns a list of all containers
-func (c *Client) ListContainers() ([]Container, error) {
-	containers, err := c.client.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+func (c *Client) ListContainers(ctx context.Context) (_ []Container, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	containers, err := c.api(ctx).ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
-		return nil, err
+		return nil, explainTLS(c.host, err)
 	}
 
+	pods := c.pods(ctx)
 	result := make([]Container, len(containers))
 	for i, container := range containers {
 		name := "Unnamed"
 		if len(container.Names) > 0 {
 			name = strings.TrimPrefix(container.Names[0], "/")
 		}
+		ports := make([]Port, len(container.Ports))
+		for j, p := range container.Ports {
+			ports[j] = Port{
+				IP:          p.IP,
+				PrivatePort: p.PrivatePort,
+				PublicPort:  p.PublicPort,
+				Type:        p.Type,
+			}
+		}
 		result[i] = Container{
 			ID:     container.ID,
 			Name:   name,
 			Status: container.State,
+			Ports:  ports,
+			Labels: container.Labels,
+			Pod:    pods[container.ID],
 		}
 	}
 
@@ -58,37 +256,91 @@ func (c *Client) ListContainers() ([]Container, error) {
 }
 
 // StopContainer stops a container
-func (c *Client) StopContainer(containerID string) error {
-	return c.client.ContainerStop(context.Background(), containerID, nil)
+func (c *Client) StopContainer(ctx context.Context, containerID string) (err error) {
+	if err := c.checkWritable("stop container"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	return c.api(ctx).ContainerStop(ctx, containerID, nil)
 }
 
 // StartContainer starts a container
-func (c *Client) StartContainer(containerID string) error {
-	return c.client.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{})
+func (c *Client) StartContainer(ctx context.Context, containerID string) (err error) {
+	if err := c.checkWritable("start container"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	return c.api(ctx).ContainerStart(ctx, containerID, types.ContainerStartOptions{})
 }
 
 // RestartContainer restarts a container
-func (c *Client) RestartContainer(containerID string) error {
-	return c.client.ContainerRestart(context.Background(), containerID, nil)
+func (c *Client) RestartContainer(ctx context.Context, containerID string) (err error) {
+	if err := c.checkWritable("restart container"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	return c.api(ctx).ContainerRestart(ctx, containerID, nil)
 }
 
-// GetContainerLogs returns the logs of a container
-func (c *Client) GetContainerLogs(containerID string) (string, error) {
-	reader, err := c.client.ContainerLogs(context.Background(), containerID, types.ContainerLogsOptions{
+// GetContainerLogs returns the last tail lines of a container's logs, or
+// all of them when tail is 0
+func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tail int) (_ string, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	return c.readLogs(ctx, containerID, types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
+		Tail:       tailOption(tail),
 	})
+}
+
+// ContainerDetails holds the inspected configuration of a container
+type ContainerDetails struct {
+	ID       string
+	Name     string
+	Image    string
+	State    string
+	Created  string
+	Command  string
+	Ports    []string
+	Networks []ContainerNetwork
+}
+
+// InspectContainer returns detailed information about a container
+func (c *Client) InspectContainer(ctx context.Context, containerID string) (_ *ContainerDetails, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	info, err := c.api(ctx).ContainerInspect(ctx, containerID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer reader.Close()
 
-	logs, err := io.ReadAll(reader)
-	if err != nil {
-		return "", err
+	details := &ContainerDetails{
+		ID:      info.ID,
+		Name:    strings.TrimPrefix(info.Name, "/"),
+		Created: info.Created,
+	}
+	if info.Config != nil {
+		details.Image = info.Config.Image
+		details.Command = strings.Join(info.Config.Cmd, " ")
+	}
+	if info.State != nil {
+		details.State = info.State.Status
+	}
+	if info.NetworkSettings != nil {
+		for port, bindings := range info.NetworkSettings.Ports {
+			for _, b := range bindings {
+				details.Ports = append(details.Ports, b.HostIP+":"+b.HostPort+"->"+string(port))
+			}
+		}
+		sort.Strings(details.Ports)
+		details.Networks = toContainerNetworks(info.NetworkSettings.Networks)
 	}
 
-	return string(logs), nil
+	return details, nil
 }
 
 // ContainerStats represents statistics of a container
@@ -105,13 +357,14 @@ type ContainerStats struct {
 }
 
 // GetContainerStats returns stats for a specific container
-func (c *Client) GetContainerStats(containerID string) (*ContainerStats, error) {
-	ctx := context.Background()
+func (c *Client) GetContainerStats(ctx context.Context, containerID string) (_ *ContainerStats, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
 
 	// Get stats with stream=false for a one-time stats fetch
-	stats, err := c.client.ContainerStats(ctx, containerID, false)
+	stats, err := c.api(ctx).ContainerStats(ctx, containerID, false)
 	if err != nil {
-		return nil, err
+		return nil, c.unsupported("container stats", err)
 	}
 	defer stats.Body.Close()
 
@@ -125,7 +378,7 @@ func (c *Client) GetContainerStats(containerID string) (*ContainerStats, error)
 	cpuPercentage := calculateCPUPercentage(&statsJSON)
 
 	// Calculate memory info
-	memoryUsage := statsJSON.MemoryStats.Usage - statsJSON.MemoryStats.Stats["cache"]
+	memoryUsage := memoryUsage(statsJSON.MemoryStats)
 	memoryLimit := statsJSON.MemoryStats.Limit
 	var memoryPercentage float64
 	if memoryLimit > 0 {
@@ -168,10 +421,27 @@ func calculateCPUPercentage(stats *types.StatsJSON) float64 {
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
 
 	if systemDelta > 0.0 && cpuDelta > 0.0 {
-		cpuCount := float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+		// cgroup v2 hosts and Podman leave the per-CPU usage empty
+		cpuCount := float64(stats.CPUStats.OnlineCPUs)
+		if cpuCount == 0 {
+			cpuCount = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+		}
 		if cpuCount > 0 {
 			return (cpuDelta / systemDelta) * cpuCount * 100.0
 		}
 	}
 	return 0.0
 }
+
+// memoryUsage is the memory a container uses without the page cache, which
+// cgroup v1 reports as cache and cgroup v2 as inactive_file
+func memoryUsage(stats types.MemoryStats) uint64 {
+	cache, ok := stats.Stats["cache"]
+	if !ok {
+		cache = stats.Stats["inactive_file"]
+	}
+	if cache > stats.Usage {
+		return stats.Usage
+	}
+	return stats.Usage - cache
+}
@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// Labels set by Docker Compose on the containers it creates
+const (
+	ComposeProjectLabel     = "com.docker.compose.project"
+	ComposeServiceLabel     = "com.docker.compose.service"
+	ComposeConfigFilesLabel = "com.docker.compose.project.config_files"
+	ComposeWorkingDirLabel  = "com.docker.compose.project.working_dir"
+	ComposeNumberLabel      = "com.docker.compose.container-number"
+)
+
+// Project returns the compose project of a container, or "" if it was not
+// created by compose
+func (c Container) Project() string {
+	return c.Labels[ComposeProjectLabel]
+}
+
+// Service returns the compose service of a container, or "" if it was not
+// created by compose
+func (c Container) Service() string {
+	return c.Labels[ComposeServiceLabel]
+}
+
+// ListProjectContainers returns every container of a compose project
+func (c *Client) ListProjectContainers(ctx context.Context, project string) (_ []Container, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	containers, err := c.api(ctx).ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", ComposeProjectLabel+"="+project)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Container, len(containers))
+	for i, container := range containers {
+		name := "Unnamed"
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		result[i] = Container{
+			ID:     container.ID,
+			Name:   name,
+			Status: container.State,
+			Labels: container.Labels,
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// StartProject starts every container of a compose project
+func (c *Client) StartProject(ctx context.Context, project string) error {
+	return c.eachProjectContainer(ctx, project, c.StartContainer)
+}
+
+// StopProject stops every container of a compose project
+func (c *Client) StopProject(ctx context.Context, project string) error {
+	return c.eachProjectContainer(ctx, project, c.StopContainer)
+}
+
+// RestartProject restarts every container of a compose project
+func (c *Client) RestartProject(ctx context.Context, project string) error {
+	return c.eachProjectContainer(ctx, project, c.RestartContainer)
+}
+
+// eachProjectContainer applies fn to all containers of a project and
+// reports every failure rather than stopping at the first one
+func (c *Client) eachProjectContainer(ctx context.Context, project string, fn func(context.Context, string) error) error {
+	containers, err := c.ListProjectContainers(ctx, project)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no containers found for project %s", project)
+	}
+
+	var failures []string
+	for _, container := range containers {
+		if err := fn(ctx, container.ID); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", container.Name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("project %s: %s", project, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// GetProjectLogs returns the logs of every service in a compose project,
+// merged in time order and prefixed with the service name. tail limits the
+// lines read per service, 0 reads all of them.
+func (c *Client) GetProjectLogs(ctx context.Context, project string, tail int) (_ string, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	containers, err := c.ListProjectContainers(ctx, project)
+	if err != nil {
+		return "", err
+	}
+
+	type logLine struct {
+		timestamp time.Time
+		text      string
+	}
+	var lines []logLine
+	width := 0
+	for _, container := range containers {
+		width = max(width, len(container.Service()))
+	}
+
+	for _, container := range containers {
+		logs, err := c.readLogs(ctx, container.ID, types.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Timestamps: true,
+			Tail:       tailOption(tail),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		prefix := fmt.Sprintf("%-*s | ", width, container.Service())
+		for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			// Each line starts with an RFC3339Nano timestamp and a space
+			stamp, text, _ := strings.Cut(line, " ")
+			timestamp, _ := time.Parse(time.RFC3339Nano, stamp)
+			lines = append(lines, logLine{timestamp: timestamp, text: prefix + text})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].timestamp.Before(lines[j].timestamp)
+	})
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
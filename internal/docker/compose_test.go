@@ -0,0 +1,200 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/shubhamku044/containix/internal/compose"
+)
+
+func TestContainerProject(t *testing.T) {
+	c := Container{Labels: map[string]string{ComposeProjectLabel: "shop", ComposeServiceLabel: "web"}}
+	if c.Project() != "shop" || c.Service() != "web" {
+		t.Errorf("project %q, service %q", c.Project(), c.Service())
+	}
+	if lone := (Container{Name: "scratch"}); lone.Project() != "" || lone.Service() != "" {
+		t.Errorf("a container without labels is in project %q", lone.Project())
+	}
+}
+
+func TestProjectActions(t *testing.T) {
+	var mu sync.Mutex
+	var filter string
+	var stopped []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.41/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		filter = r.URL.Query().Get("filters")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(filter, ComposeProjectLabel+"=shop") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"Id":"w1","Names":["/shop-web-1"],"State":"running","Labels":{"com.docker.compose.project":"shop","com.docker.compose.service":"web"}},
+			{"Id":"d1","Names":["/shop-db-1"],"State":"running","Labels":{"com.docker.compose.project":"shop","com.docker.compose.service":"db"}},
+			{"Id":"c1","Names":["/shop-cache-1"],"State":"running","Labels":{"com.docker.compose.project":"shop","com.docker.compose.service":"cache"}}
+		]`))
+	})
+	mux.HandleFunc("POST /v1.41/containers/{id}/stop", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "d1" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"cannot kill container"}`))
+			return
+		}
+		mu.Lock()
+		stopped = append(stopped, id)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c := serveDaemon(t, mux, Options{})
+	ctx := context.Background()
+
+	containers, err := c.ListProjectContainers(ctx, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, container := range containers {
+		names = append(names, container.Name+"="+container.Service())
+	}
+	// Stopped containers are part of the project too
+	if !slices.Equal(names, []string{"shop-cache-1=cache", "shop-db-1=db", "shop-web-1=web"}) || !strings.Contains(filter, "label") {
+		t.Errorf("containers = %q, filters %s", names, filter)
+	}
+
+	// One failure does not stop the rest, and every failure is reported
+	err = c.StopProject(ctx, "shop")
+	if err == nil || !strings.Contains(err.Error(), "project shop: shop-db-1: ") || !strings.Contains(err.Error(), "cannot kill container") {
+		t.Errorf("error = %v, want the failed container", err)
+	}
+	mu.Lock()
+	if !slices.Equal(stopped, []string{"c1", "w1"}) {
+		t.Errorf("stopped %q, want the other containers", stopped)
+	}
+	mu.Unlock()
+
+	if err := c.StartProject(ctx, "blog"); err == nil || !strings.Contains(err.Error(), "no containers found for project blog") {
+		t.Errorf("starting an empty project: %v", err)
+	}
+}
+
+func TestComposeUpNetworkMode(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yml")
+	err := os.WriteFile(file, []byte(`
+services:
+  db:
+    image: postgres
+  sidecar:
+    image: busybox
+    network_mode: service:db
+  tool:
+    image: busybox
+    network_mode: host
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := compose.Load([]string{file}, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type created struct {
+		container.Config
+		HostConfig       container.HostConfig
+		NetworkingConfig network.NetworkingConfig
+	}
+	var mu sync.Mutex
+	containers := map[string]created{}
+	var order, networks []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.41/networks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("POST /v1.41/networks/create", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Name string }
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		networks = append(networks, body.Name)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"n1"}`))
+	})
+	mux.HandleFunc("GET /v1.41/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("GET /v1.41/images/{name}/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Id":"sha256:abc"}`))
+	})
+	mux.HandleFunc("POST /v1.41/containers/create", func(w http.ResponseWriter, r *http.Request) {
+		var body created
+		json.NewDecoder(r.Body).Decode(&body)
+		name := r.URL.Query().Get("name")
+		mu.Lock()
+		containers[name] = body
+		order = append(order, name)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"` + name + `"}`))
+	})
+	mux.HandleFunc("POST /v1.41/containers/{id}/start", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c := serveDaemon(t, mux, Options{})
+
+	if err := c.ComposeUp(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Only the network a service joins is created
+	if !slices.Equal(networks, []string{"shop_default"}) {
+		t.Errorf("created networks %q", networks)
+	}
+	if slices.Index(order, "shop-db-1") > slices.Index(order, "shop-sidecar-1") {
+		t.Errorf("created %q, want db before the service sharing its network", order)
+	}
+	for _, tc := range []struct {
+		name, mode string
+		endpoints  []string
+	}{
+		{"shop-db-1", "shop_default", []string{"shop_default"}},
+		{"shop-sidecar-1", "container:shop-db-1", nil},
+		{"shop-tool-1", "host", nil},
+	} {
+		body, ok := containers[tc.name]
+		if !ok {
+			t.Errorf("%s was not created", tc.name)
+			continue
+		}
+		endpoints := slices.Sorted(maps.Keys(body.NetworkingConfig.EndpointsConfig))
+		if string(body.HostConfig.NetworkMode) != tc.mode || !slices.Equal(endpoints, tc.endpoints) {
+			t.Errorf("%s: network mode %q with endpoints %q, want %q with %q", tc.name, body.HostConfig.NetworkMode, endpoints, tc.mode, tc.endpoints)
+		}
+		// Compose and later rebuilds read the files from any directory
+		if files := body.Labels[ComposeConfigFilesLabel]; files != file {
+			t.Errorf("%s: config files label %q, want %q", tc.name, files, file)
+		}
+	}
+}
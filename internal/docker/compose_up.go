@@ -0,0 +1,503 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/shubhamku044/containix/internal/compose"
+)
+
+// composeNetworkLabel marks networks created for a compose project
+const composeNetworkLabel = "com.docker.compose.network"
+
+// ConfigDiff is a difference between a service's declared configuration
+// and its running container
+type ConfigDiff struct {
+	Field    string
+	Declared string
+	Running  string
+}
+
+// ServiceContainers maps each service of a project to its containers
+func (c *Client) ServiceContainers(ctx context.Context, project string) (map[string][]Container, error) {
+	containers, err := c.ListProjectContainers(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]Container{}
+	for _, container := range containers {
+		result[container.Service()] = append(result[container.Service()], container)
+	}
+	return result, nil
+}
+
+// ComposeUp creates the project's networks and volumes and starts every
+// service in dependency order. Running services are left untouched and
+// stopped ones are started again.
+func (c *Client) ComposeUp(ctx context.Context, p *compose.Project) (err error) {
+	if err := c.checkWritable("compose up"); err != nil {
+		return err
+	}
+
+	ctx, done := limit(ctx, c.timeouts.Compose)
+	defer done(&err)
+
+	if err := c.ensureProjectResources(ctx, p); err != nil {
+		return err
+	}
+
+	order, err := p.StartOrder()
+	if err != nil {
+		return err
+	}
+	existing, err := c.ServiceContainers(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range order {
+		if containers := existing[name]; len(containers) > 0 {
+			for _, ctr := range containers {
+				if ctr.Status != "running" {
+					if err := c.StartContainer(ctx, ctr.ID); err != nil {
+						return fmt.Errorf("start %s: %w", name, err)
+					}
+				}
+			}
+			continue
+		}
+
+		s, _ := p.Service(name)
+		if err := c.createAndStart(ctx, p, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ComposeDown stops and removes the project's containers and the networks
+// compose created for it. Volumes are kept, like `docker compose down`.
+func (c *Client) ComposeDown(ctx context.Context, p *compose.Project) (err error) {
+	if err := c.checkWritable("compose down"); err != nil {
+		return err
+	}
+
+	ctx, done := limit(ctx, c.timeouts.Compose)
+	defer done(&err)
+
+	containers, err := c.ListProjectContainers(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+	for _, ctr := range containers {
+		if err := c.api(ctx).ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("remove %s: %w", ctr.Name, err)
+		}
+	}
+
+	networks, err := c.api(ctx).NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", ComposeProjectLabel+"="+p.Name)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, n := range networks {
+		if err := c.api(ctx).NetworkRemove(ctx, n.ID); err != nil {
+			return fmt.Errorf("remove network %s: %w", n.Name, err)
+		}
+	}
+	return nil
+}
+
+// RecreateService removes a service's containers and creates a fresh one
+// from the declared configuration
+func (c *Client) RecreateService(ctx context.Context, p *compose.Project, service string) (err error) {
+	if err := c.checkWritable("recreate service"); err != nil {
+		return err
+	}
+
+	ctx, done := limit(ctx, c.timeouts.Compose)
+	defer done(&err)
+
+	s, ok := p.Service(service)
+	if !ok {
+		return fmt.Errorf("service %s is not defined in the compose file", service)
+	}
+	if err := c.ensureProjectResources(ctx, p); err != nil {
+		return err
+	}
+
+	existing, err := c.ServiceContainers(ctx, p.Name)
+	if err != nil {
+		return err
+	}
+	for _, ctr := range existing[service] {
+		if err := c.api(ctx).ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("remove %s: %w", ctr.Name, err)
+		}
+	}
+
+	return c.createAndStart(ctx, p, s)
+}
+
+// DiffService compares a service's declared configuration with its
+// running container
+func (c *Client) DiffService(ctx context.Context, p *compose.Project, service string) (_ []ConfigDiff, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+
+	s, ok := p.Service(service)
+	if !ok {
+		return nil, fmt.Errorf("service %s is not defined in the compose file", service)
+	}
+	existing, err := c.ServiceContainers(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing[service]) == 0 {
+		return nil, fmt.Errorf("service %s has no container", service)
+	}
+
+	info, err := c.api(ctx).ContainerInspect(ctx, existing[service][0].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ConfigDiff
+	add := func(field, declared, running string) {
+		if declared != running {
+			diffs = append(diffs, ConfigDiff{Field: field, Declared: declared, Running: running})
+		}
+	}
+
+	add("image", s.Image, info.Config.Image)
+	if len(s.Command) > 0 {
+		add("command", strings.Join(s.Command, " "), strings.Join(info.Config.Cmd, " "))
+	}
+	if len(s.Entrypoint) > 0 {
+		add("entrypoint", strings.Join(s.Entrypoint, " "), strings.Join(info.Config.Entrypoint, " "))
+	}
+	if s.User != "" {
+		add("user", s.User, info.Config.User)
+	}
+	if s.WorkingDir != "" {
+		add("working_dir", s.WorkingDir, info.Config.WorkingDir)
+	}
+
+	running := map[string]string{}
+	for _, kv := range info.Config.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		running[k] = v
+	}
+	for _, kv := range s.Environment {
+		k, v, _ := strings.Cut(kv, "=")
+		value, ok := running[k]
+		if !ok {
+			value = "<unset>"
+		}
+		add("environment."+k, v, value)
+	}
+
+	for k, v := range s.Labels {
+		value, ok := info.Config.Labels[k]
+		if !ok {
+			value = "<unset>"
+		}
+		add("labels."+k, v, value)
+	}
+
+	restart := s.Restart
+	if restart == "" {
+		restart = "no"
+	}
+	add("restart", restart, formatRestartPolicy(info.HostConfig.RestartPolicy))
+
+	_, declaredPorts, err := nat.ParsePortSpecs(s.Ports)
+	if err != nil {
+		return nil, err
+	}
+	add("ports", formatPortMap(declaredPorts), formatPortMap(info.HostConfig.PortBindings))
+
+	add("volumes", sortedJoin(bindsOnly(s.Volumes)), sortedJoin(info.HostConfig.Binds))
+
+	if mode := p.NetworkMode(s); mode != "" {
+		running := string(info.HostConfig.NetworkMode)
+		// The daemon records the ID of the container whose network is shared
+		if strings.HasPrefix(mode, "container:") && strings.HasPrefix(running, "container:") {
+			running = mode
+		}
+		add("network_mode", mode, running)
+	} else {
+		var declaredNets, runningNets []string
+		for key := range p.ServiceNetworks(s) {
+			declaredNets = append(declaredNets, p.Networks[key].Name)
+		}
+		if info.NetworkSettings != nil {
+			for name := range info.NetworkSettings.Networks {
+				runningNets = append(runningNets, name)
+			}
+		}
+		add("networks", sortedJoin(declaredNets), sortedJoin(runningNets))
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs, nil
+}
+
+// ensureProjectResources creates missing networks and volumes of a project
+func (c *Client) ensureProjectResources(ctx context.Context, p *compose.Project) error {
+	existing, err := c.api(ctx).NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+	present := map[string]bool{}
+	for _, n := range existing {
+		present[n.Name] = true
+	}
+
+	used := map[string]bool{}
+	for _, s := range p.Services {
+		for key := range p.ServiceNetworks(s) {
+			used[key] = true
+		}
+	}
+
+	for key, n := range p.Networks {
+		if !used[key] || present[n.Name] {
+			continue
+		}
+		if n.External {
+			return fmt.Errorf("external network %s not found", n.Name)
+		}
+		labels := map[string]string{ComposeProjectLabel: p.Name, composeNetworkLabel: key}
+		for k, v := range n.Labels {
+			labels[k] = v
+		}
+		_, err := c.api(ctx).NetworkCreate(ctx, n.Name, types.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         n.Driver,
+			Internal:       n.Internal,
+			Labels:         labels,
+		})
+		if err != nil {
+			return fmt.Errorf("create network %s: %w", n.Name, err)
+		}
+	}
+
+	for key, v := range p.Volumes {
+		if v.External {
+			continue
+		}
+		labels := map[string]string{ComposeProjectLabel: p.Name, "com.docker.compose.volume": key}
+		for k, val := range v.Labels {
+			labels[k] = val
+		}
+		// Creating a volume that already exists is a no-op on the daemon
+		_, err := c.api(ctx).VolumeCreate(ctx, volume.VolumeCreateBody{
+			Name:   v.Name,
+			Driver: v.Driver,
+			Labels: labels,
+		})
+		if err != nil {
+			return fmt.Errorf("create volume %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// createAndStart creates the container for a service and starts it
+func (c *Client) createAndStart(ctx context.Context, p *compose.Project, s compose.Service) error {
+	if err := c.ensureImage(ctx, s.Image); err != nil {
+		return fmt.Errorf("pull %s: %w", s.Image, err)
+	}
+
+	exposed, bindings, err := nat.ParsePortSpecs(s.Ports)
+	if err != nil {
+		return fmt.Errorf("service %s: %w", s.Name, err)
+	}
+	restart, err := parseRestartPolicy(s.Restart)
+	if err != nil {
+		return fmt.Errorf("service %s: %w", s.Name, err)
+	}
+
+	labels := map[string]string{
+		ComposeProjectLabel:         p.Name,
+		ComposeServiceLabel:         s.Name,
+		ComposeConfigFilesLabel:     strings.Join(p.Files, ","),
+		ComposeWorkingDirLabel:      p.WorkingDir,
+		ComposeNumberLabel:          "1",
+		"com.docker.compose.oneoff": "False",
+	}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+
+	config := &container.Config{
+		Image:        s.Image,
+		Env:          s.Environment,
+		Labels:       labels,
+		ExposedPorts: exposed,
+		WorkingDir:   s.WorkingDir,
+		User:         s.User,
+		Hostname:     s.Hostname,
+		Volumes:      map[string]struct{}{},
+	}
+	if len(s.Command) > 0 {
+		config.Cmd = strslice.StrSlice(s.Command)
+	}
+	if len(s.Entrypoint) > 0 {
+		config.Entrypoint = strslice.StrSlice(s.Entrypoint)
+	}
+	// Anonymous volumes have no source and go into Config.Volumes
+	for _, v := range s.Volumes {
+		if !strings.Contains(v, ":") {
+			config.Volumes[v] = struct{}{}
+		}
+	}
+
+	// The first network is set at create time, the rest are connected after.
+	// A service with a network mode joins none of them.
+	networks := p.ServiceNetworks(s)
+	var keys []string
+	for key := range networks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	endpoint := func(key string) *network.EndpointSettings {
+		settings := &network.EndpointSettings{
+			Aliases: append([]string{s.Name}, networks[key].Aliases...),
+		}
+		if networks[key].IPv4Address != "" {
+			settings.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: networks[key].IPv4Address}
+		}
+		return settings
+	}
+
+	hostConfig := &container.HostConfig{
+		Binds:         bindsOnly(s.Volumes),
+		PortBindings:  bindings,
+		RestartPolicy: restart,
+		NetworkMode:   container.NetworkMode(p.NetworkMode(s)),
+	}
+	networking := &network.NetworkingConfig{}
+	if len(keys) > 0 {
+		primary := p.Networks[keys[0]].Name
+		hostConfig.NetworkMode = container.NetworkMode(primary)
+		networking.EndpointsConfig = map[string]*network.EndpointSettings{primary: endpoint(keys[0])}
+		keys = keys[1:]
+	}
+
+	created, err := c.api(ctx).ContainerCreate(ctx, config, hostConfig, networking, nil, p.ContainerName(s))
+	if err != nil {
+		return fmt.Errorf("create %s: %w", s.Name, err)
+	}
+	for _, key := range keys {
+		if err := c.api(ctx).NetworkConnect(ctx, p.Networks[key].Name, created.ID, endpoint(key)); err != nil {
+			return fmt.Errorf("connect %s to %s: %w", s.Name, key, err)
+		}
+	}
+
+	if err := c.api(ctx).ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("start %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// ensureImage pulls an image if it is not present locally
+func (c *Client) ensureImage(ctx context.Context, image string) error {
+	_, _, err := c.api(ctx).ImageInspectWithRaw(ctx, image)
+	if err == nil {
+		return nil
+	}
+	if !client.IsErrNotFound(err) {
+		return err
+	}
+
+	reader, err := c.api(ctx).ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	// The pull only completes once the progress stream is drained
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// parseRestartPolicy converts compose restart values such as on-failure:3
+func parseRestartPolicy(restart string) (container.RestartPolicy, error) {
+	name, count, _ := strings.Cut(restart, ":")
+	policy := container.RestartPolicy{Name: name}
+	switch name {
+	case "", "no":
+		policy.Name = ""
+	case "always", "unless-stopped":
+	case "on-failure":
+		if count != "" {
+			n, err := strconv.Atoi(count)
+			if err != nil {
+				return policy, fmt.Errorf("invalid restart policy %q", restart)
+			}
+			policy.MaximumRetryCount = n
+		}
+	default:
+		return policy, fmt.Errorf("invalid restart policy %q", restart)
+	}
+	return policy, nil
+}
+
+func formatRestartPolicy(p container.RestartPolicy) string {
+	switch {
+	case p.Name == "":
+		return "no"
+	case p.Name == "on-failure" && p.MaximumRetryCount > 0:
+		return fmt.Sprintf("on-failure:%d", p.MaximumRetryCount)
+	}
+	return p.Name
+}
+
+// formatPortMap renders port bindings in a stable, comparable form
+func formatPortMap(ports nat.PortMap) string {
+	var result []string
+	for port, bindings := range ports {
+		for _, b := range bindings {
+			host := b.HostPort
+			if b.HostIP != "" {
+				host = b.HostIP + ":" + host
+			}
+			result = append(result, host+"->"+string(port))
+		}
+	}
+	return sortedJoin(result)
+}
+
+// bindsOnly drops anonymous volumes, which are not valid Binds entries
+func bindsOnly(volumes []string) []string {
+	var binds []string
+	for _, v := range volumes {
+		if strings.Contains(v, ":") {
+			binds = append(binds, v)
+		}
+	}
+	return binds
+}
+
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultContext is the context the docker CLI uses when none is selected.
+// It connects to DOCKER_HOST, or the local daemon when that is unset.
+const DefaultContext = "default"
+
+// Context is a docker CLI context
+type Context struct {
+	Name        string
+	Description string
+	Host        string
+}
+
+// contextMeta is the part of a docker CLI context's meta.json containix reads
+type contextMeta struct {
+	Name     string `json:"Name"`
+	Metadata struct {
+		Description string `json:"Description"`
+	} `json:"Metadata"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// configDir returns the docker CLI configuration directory
+func configDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker")
+}
+
+// CurrentContext returns the context the docker CLI would use: the one
+// named by DOCKER_CONTEXT, or else the currentContext of config.json
+func CurrentContext() string {
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+
+	raw, err := os.ReadFile(filepath.Join(configDir(), "config.json"))
+	if err != nil {
+		return DefaultContext
+	}
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil || cfg.CurrentContext == "" {
+		return DefaultContext
+	}
+	return cfg.CurrentContext
+}
+
+// ListContexts returns the default context followed by every context in
+// the docker CLI's context store, sorted by name
+func ListContexts() ([]Context, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = localHost()
+	}
+	contexts := []Context{{
+		Name:        DefaultContext,
+		Description: "DOCKER_HOST or the local daemon",
+		Host:        host,
+	}}
+
+	dirs, err := os.ReadDir(filepath.Join(configDir(), "contexts", "meta"))
+	if errors.Is(err, os.ErrNotExist) {
+		return contexts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []Context
+	for _, dir := range dirs {
+		meta, err := readContextMeta(filepath.Join(configDir(), "contexts", "meta", dir.Name(), "meta.json"))
+		if err != nil || meta.Name == "" {
+			// Not a context, or one the CLI could not read either
+			continue
+		}
+		stored = append(stored, Context{
+			Name:        meta.Name,
+			Description: meta.Metadata.Description,
+			Host:        meta.Endpoints["docker"].Host,
+		})
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].Name < stored[j].Name
+	})
+	return append(contexts, stored...), nil
+}
+
+// ContextHost returns the daemon address of a docker CLI context. The
+// "default" context and an empty name resolve to an empty host, meaning
+// the environment decides.
+func ContextHost(name string) (string, error) {
+	if name == "" || name == DefaultContext {
+		return "", nil
+	}
+
+	// The CLI stores each context under the SHA-256 of its name
+	sum := sha256.Sum256([]byte(name))
+	meta, err := readContextMeta(filepath.Join(configDir(), "contexts", "meta", hex.EncodeToString(sum[:]), "meta.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("docker context %q not found", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("docker context %q: %w", name, err)
+	}
+	endpoint, ok := meta.Endpoints["docker"]
+	if !ok || endpoint.Host == "" {
+		return "", fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+	return endpoint.Host, nil
+}
+
+func readContextMeta(path string) (contextMeta, error) {
+	var meta contextMeta
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(raw, &meta)
+	return meta, err
+}
@@ -0,0 +1,183 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// contextStore points DOCKER_CONFIG at an empty directory and returns it
+func contextStore(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_HOST", "")
+	return dir
+}
+
+// writeContext stores meta.json the way the docker CLI does, under the
+// SHA-256 of the context name, and returns its directory name
+func writeContext(t *testing.T, dir, name, meta string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(sum[:])
+	path := filepath.Join(dir, "contexts", "meta", id)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "meta.json"), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestCurrentContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		config string // config.json, none when empty
+		want   string
+	}{
+		{name: "no config", want: DefaultContext},
+		{name: "config", config: `{"currentContext": "staging"}`, want: "staging"},
+		{name: "environment wins", env: "prod", config: `{"currentContext": "staging"}`, want: "prod"},
+		{name: "no current context", config: `{"auths": {}}`, want: DefaultContext},
+		{name: "invalid config", config: `{"currentContext":`, want: DefaultContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := contextStore(t)
+			t.Setenv("DOCKER_CONTEXT", tt.env)
+			if tt.config != "" {
+				if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := CurrentContext(); got != tt.want {
+				t.Errorf("CurrentContext() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListContexts(t *testing.T) {
+	dir := contextStore(t)
+	t.Setenv("DOCKER_HOST", "tcp://local:2375")
+
+	// Without a store only the default context exists
+	contexts, err := ListContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contexts) != 1 || contexts[0].Name != DefaultContext || contexts[0].Host != "tcp://local:2375" {
+		t.Fatalf("contexts = %+v, want only the default one on DOCKER_HOST", contexts)
+	}
+
+	writeContext(t, dir, "staging", `{
+		"Name": "staging",
+		"Metadata": {"Description": "staging cluster"},
+		"Endpoints": {"docker": {"Host": "tcp://staging.internal:2376", "SkipTLSVerify": false}}
+	}`)
+	writeContext(t, dir, "colima", `{
+		"Name": "colima",
+		"Metadata": {},
+		"Endpoints": {"docker": {"Host": "unix:///Users/me/.colima/default/docker.sock"}}
+	}`)
+	writeContext(t, dir, "broken", `{"Name":`)
+	writeContext(t, dir, "unnamed", `{"Endpoints": {"docker": {"Host": "tcp://x:2375"}}}`)
+
+	contexts, err = ListContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Context{
+		{Name: DefaultContext, Description: "DOCKER_HOST or the local daemon", Host: "tcp://local:2375"},
+		{Name: "colima", Host: "unix:///Users/me/.colima/default/docker.sock"},
+		{Name: "staging", Description: "staging cluster", Host: "tcp://staging.internal:2376"},
+	}
+	if len(contexts) != len(want) {
+		t.Fatalf("contexts = %+v, want %+v", contexts, want)
+	}
+	for i := range want {
+		if contexts[i] != want[i] {
+			t.Errorf("contexts[%d] = %+v, want %+v", i, contexts[i], want[i])
+		}
+	}
+}
+
+func TestContextHost(t *testing.T) {
+	dir := contextStore(t)
+	writeContext(t, dir, "staging", `{
+		"Name": "staging",
+		"Endpoints": {"docker": {"Host": "ssh://me@staging"}}
+	}`)
+	writeContext(t, dir, "kube", `{
+		"Name": "kube",
+		"Endpoints": {"kubernetes": {"Host": "https://k8s:6443"}}
+	}`)
+	writeContext(t, dir, "broken", `not json`)
+
+	tests := []struct {
+		name string
+		want string
+		err  string
+	}{
+		{name: "", want: ""},
+		{name: DefaultContext, want: ""},
+		{name: "staging", want: "ssh://me@staging"},
+		{name: "missing", err: `docker context "missing" not found`},
+		{name: "kube", err: `docker context "kube" has no docker endpoint`},
+		{name: "broken", err: `docker context "broken": invalid character`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, err := ContextHost(tt.name)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if host != tt.want {
+				t.Errorf("host = %q, want %q", host, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextTLS(t *testing.T) {
+	dir := contextStore(t)
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	id := writeContext(t, dir, "staging", `{
+		"Name": "staging",
+		"Endpoints": {"docker": {"Host": "tcp://staging:2376", "SkipTLSVerify": true}}
+	}`)
+	certs := filepath.Join(dir, "contexts", "tls", id, "docker")
+	if err := os.MkdirAll(certs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := resolveTLS("tcp://staging:2376", "staging", nil)
+	if files == nil || files.CA != filepath.Join(certs, "ca.pem") || files.Verify {
+		t.Errorf("files = %+v, want the context's certificates without verification", files)
+	}
+	// Configured certificates come first
+	configured := map[string]TLSFiles{"tcp://staging:2376": {CA: "/etc/ca.pem", Verify: true}}
+	if files := resolveTLS("tcp://staging:2376", "staging", configured); files == nil || files.CA != "/etc/ca.pem" {
+		t.Errorf("files = %+v, want the configured ones", files)
+	}
+	// A context without certificates means plain TCP
+	if files := resolveTLS("tcp://other:2375", "other", nil); files != nil {
+		t.Errorf("files = %+v, want none", files)
+	}
+}
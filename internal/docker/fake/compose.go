@@ -0,0 +1,321 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/compose"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// projectContainers returns a project's containers sorted by name
+func (r *Runtime) projectContainers(project string) []*container {
+	var result []*container
+	for _, c := range r.containers {
+		if c.spec.Labels[docker.ComposeProjectLabel] == project {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].spec.Name < result[j].spec.Name
+	})
+	return result
+}
+
+// ListProjectContainers returns every container of a compose project
+func (r *Runtime) ListProjectContainers(ctx context.Context, project string) ([]docker.Container, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ListProjectContainers"); err != nil {
+		return nil, err
+	}
+	var result []docker.Container
+	for _, c := range r.projectContainers(project) {
+		result = append(result, c.toContainer())
+	}
+	return result, nil
+}
+
+// ServiceContainers maps each service of a project to its containers
+func (r *Runtime) ServiceContainers(ctx context.Context, project string) (map[string][]docker.Container, error) {
+	containers, err := r.ListProjectContainers(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]docker.Container{}
+	for _, c := range containers {
+		result[c.Service()] = append(result[c.Service()], c)
+	}
+	return result, nil
+}
+
+// StartProject starts every container of a compose project
+func (r *Runtime) StartProject(ctx context.Context, project string) error {
+	return r.eachProjectContainer("StartProject", project, func(c *container) {
+		r.setStatus(c, StatusRunning)
+	})
+}
+
+// StopProject stops every container of a compose project
+func (r *Runtime) StopProject(ctx context.Context, project string) error {
+	return r.eachProjectContainer("StopProject", project, r.stop)
+}
+
+// RestartProject restarts every container of a compose project
+func (r *Runtime) RestartProject(ctx context.Context, project string) error {
+	return r.eachProjectContainer("RestartProject", project, func(c *container) {
+		r.setStatus(c, StatusExited)
+		r.event(c, "restart")
+		r.setStatus(c, StatusRunning)
+	})
+}
+
+func (r *Runtime) eachProjectContainer(method, project string, fn func(*container)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable(method); err != nil {
+		return err
+	}
+	containers := r.projectContainers(project)
+	if len(containers) == 0 {
+		return fmt.Errorf("no containers found for project %s", project)
+	}
+	for _, c := range containers {
+		fn(c)
+		c.crashed = false
+		c.crashes = 0
+	}
+	return nil
+}
+
+// ComposeUp creates the project's networks and starts every service in
+// dependency order. Running services are left untouched and stopped ones
+// are started again.
+func (r *Runtime) ComposeUp(ctx context.Context, p *compose.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("ComposeUp"); err != nil {
+		return err
+	}
+	order, err := p.StartOrder()
+	if err != nil {
+		return err
+	}
+	r.ensureProjectNetworks(p)
+
+	existing := map[string][]*container{}
+	for _, c := range r.projectContainers(p.Name) {
+		service := c.spec.Labels[docker.ComposeServiceLabel]
+		existing[service] = append(existing[service], c)
+	}
+	for _, name := range order {
+		if containers := existing[name]; len(containers) > 0 {
+			for _, c := range containers {
+				r.setStatus(c, StatusRunning)
+			}
+			continue
+		}
+		s, _ := p.Service(name)
+		r.createService(p, s)
+	}
+	return nil
+}
+
+// ComposeDown removes the project's containers and the networks created
+// for it
+func (r *Runtime) ComposeDown(ctx context.Context, p *compose.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("ComposeDown"); err != nil {
+		return err
+	}
+	for _, c := range r.projectContainers(p.Name) {
+		r.stop(c)
+		r.removeContainer(c)
+	}
+	for _, n := range append([]*network(nil), r.networks...) {
+		if n.labels[docker.ComposeProjectLabel] == p.Name {
+			r.removeNetwork(n)
+		}
+	}
+	return nil
+}
+
+// RecreateService replaces a service's containers with a fresh one built
+// from the declared configuration
+func (r *Runtime) RecreateService(ctx context.Context, p *compose.Project, service string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("RecreateService"); err != nil {
+		return err
+	}
+	s, ok := p.Service(service)
+	if !ok {
+		return fmt.Errorf("service %s is not defined in the compose file", service)
+	}
+	r.ensureProjectNetworks(p)
+	for _, c := range r.projectContainers(p.Name) {
+		if c.spec.Labels[docker.ComposeServiceLabel] == service {
+			r.removeContainer(c)
+		}
+	}
+	r.createService(p, s)
+	return nil
+}
+
+// DiffService compares a service's declared image, command, environment,
+// labels and networks with its container
+func (r *Runtime) DiffService(ctx context.Context, p *compose.Project, service string) ([]docker.ConfigDiff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("DiffService"); err != nil {
+		return nil, err
+	}
+	s, ok := p.Service(service)
+	if !ok {
+		return nil, fmt.Errorf("service %s is not defined in the compose file", service)
+	}
+	var c *container
+	for _, candidate := range r.projectContainers(p.Name) {
+		if candidate.spec.Labels[docker.ComposeServiceLabel] == service {
+			c = candidate
+			break
+		}
+	}
+	if c == nil {
+		return nil, fmt.Errorf("service %s has no container", service)
+	}
+
+	var diffs []docker.ConfigDiff
+	add := func(field, declared, running string) {
+		if declared != running {
+			diffs = append(diffs, docker.ConfigDiff{Field: field, Declared: declared, Running: running})
+		}
+	}
+
+	add("image", s.Image, c.spec.Image)
+	if len(s.Command) > 0 {
+		add("command", strings.Join(s.Command, " "), strings.Join(c.spec.Command, " "))
+	}
+	running := map[string]string{}
+	for _, kv := range c.spec.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		running[k] = v
+	}
+	for _, kv := range s.Environment {
+		k, v, _ := strings.Cut(kv, "=")
+		value, ok := running[k]
+		if !ok {
+			value = "<unset>"
+		}
+		add("environment."+k, v, value)
+	}
+	for k, v := range s.Labels {
+		value, ok := c.spec.Labels[k]
+		if !ok {
+			value = "<unset>"
+		}
+		add("labels."+k, v, value)
+	}
+
+	declaredNets := r.serviceNetworks(p, s)
+	var runningNets []string
+	for _, n := range r.containerNetworks(c) {
+		runningNets = append(runningNets, n.Name)
+	}
+	sort.Strings(declaredNets)
+	sort.Strings(runningNets)
+	add("networks", strings.Join(declaredNets, ", "), strings.Join(runningNets, ", "))
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs, nil
+}
+
+// ensureProjectNetworks creates the project's missing, non-external networks
+func (r *Runtime) ensureProjectNetworks(p *compose.Project) {
+	keys := make([]string, 0, len(p.Networks))
+	for key := range p.Networks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		n := p.Networks[key]
+		if n.External || r.findNetwork(n.Name) != nil {
+			continue
+		}
+		r.addNetwork(docker.NetworkOptions{Name: n.Name, Driver: n.Driver, Internal: n.Internal}, map[string]string{
+			docker.ComposeProjectLabel: p.Name,
+		})
+	}
+}
+
+// serviceNetworks returns the names of the networks a service's container
+// is on, sorted like the keys of ServiceNetworks. A network mode stands for
+// a network of that name, and a shared network stack for the other
+// container's networks.
+func (r *Runtime) serviceNetworks(p *compose.Project, s compose.Service) []string {
+	mode := p.NetworkMode(s)
+	if ref, ok := strings.CutPrefix(mode, "container:"); ok {
+		if other, err := r.find(ref); err == nil {
+			return slices.Clone(other.spec.Networks)
+		}
+		return nil
+	}
+	if mode != "" {
+		return []string{mode}
+	}
+
+	networks := p.ServiceNetworks(s)
+	keys := slices.Sorted(maps.Keys(networks))
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = p.Networks[key].Name
+	}
+	return names
+}
+
+// createService adds and starts the container for a service
+func (r *Runtime) createService(p *compose.Project, s compose.Service) {
+	labels := map[string]string{
+		docker.ComposeProjectLabel:     p.Name,
+		docker.ComposeServiceLabel:     s.Name,
+		docker.ComposeConfigFilesLabel: strings.Join(p.Files, ","),
+		docker.ComposeWorkingDirLabel:  p.WorkingDir,
+		docker.ComposeNumberLabel:      "1",
+	}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+
+	networks := p.ServiceNetworks(s)
+	keys := make([]string, 0, len(networks))
+	for key := range networks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := r.serviceNetworks(p, s)
+
+	c := r.addContainer(ContainerSpec{
+		Name:     p.ContainerName(s),
+		Image:    s.Image,
+		Command:  s.Command,
+		Env:      s.Environment,
+		Status:   StatusCreated,
+		Labels:   labels,
+		Networks: names,
+	})
+	// Compose makes every service reachable under its name
+	for i, key := range keys {
+		if n := r.findNetwork(names[i]); n != nil {
+			n.endpoints[c.id].aliases = append([]string{s.Name}, networks[key].Aliases...)
+		}
+	}
+	r.setStatus(c, StatusRunning)
+}
@@ -0,0 +1,740 @@
+// Package fake is an in-memory container runtime. It simulates containers,
+// compose projects, networks, files, logs and stats so the UI can run in
+// tests and demos without a Docker daemon. Time only moves when Advance is
+// called, which keeps everything it produces reproducible.
+package fake
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// Container states as reported by the daemon
+const (
+	StatusCreated    = "created"
+	StatusRunning    = "running"
+	StatusPaused     = "paused"
+	StatusRestarting = "restarting"
+	StatusExited     = "exited"
+	StatusDead       = "dead"
+)
+
+// maxLogLines caps the log history kept per container
+const maxLogLines = 5000
+
+// Restart backoff after a crash, doubled for every consecutive crash
+const (
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+)
+
+// ContainerSpec describes a simulated container. Only Name is required.
+type ContainerSpec struct {
+	Name     string
+	Image    string
+	Command  []string
+	Env      []string
+	Status   string // running by default
+	Labels   map[string]string
+	Pod      string // Podman pod, empty for Docker
+	Ports    []docker.Port
+	Networks []string          // joined at creation, bridge by default
+	Files    map[string]string // absolute path to file content
+
+	CPU         float64 // average CPU usage in percent
+	Memory      uint64  // average memory usage in bytes
+	MemoryLimit uint64  // 0 means 2 GiB
+	PIDs        uint64
+
+	Logs     []string      // initial log history
+	LogLines []string      // lines emitted in turn while running, {n} is replaced by a counter
+	LogEvery time.Duration // interval between emitted lines, 0 for none
+
+	// Failure simulation
+	Restart      string        // restart policy: no, always, on-failure or unless-stopped
+	CrashAfter   time.Duration // a running container exits after this long, 0 for never
+	CrashLog     string        // last line logged before a crash
+	MemoryGrowth uint64        // bytes per second; reaching the limit gets it OOM killed
+}
+
+// Event is a container state change, like those reported by `docker events`
+type Event struct {
+	Time        time.Time
+	ContainerID string
+	Name        string
+	Action      string // create, start, stop, die, oom, restart, pause, update or destroy
+}
+
+type logEntry struct {
+	time time.Time
+	text string
+}
+
+type container struct {
+	spec     ContainerSpec
+	id       string
+	status   string
+	created  time.Time
+	started  time.Time
+	logs     []logEntry
+	nextLine int
+	lastLog  time.Time
+	files    map[string]string
+	crashed  bool // exited on its own, so the restart policy applies
+	crashes  int  // consecutive crashes, for the restart backoff
+	exited   time.Time
+
+	// CPU, swap and PIDs limits; memory and restart live in the spec
+	limits docker.Resources
+}
+
+// Runtime is an in-memory implementation of docker.Runtime
+type Runtime struct {
+	mu         sync.Mutex
+	now        time.Time
+	readOnly   bool
+	containers []*container
+	networks   []*network
+	events     []Event
+	failures   map[string]error
+	created    int
+	host       docker.HostInfo
+	caps       docker.Capabilities
+}
+
+var _ docker.Runtime = (*Runtime)(nil)
+
+// New creates an empty runtime whose clock starts at now. The predefined
+// bridge, host and none networks already exist.
+func New(now time.Time) *Runtime {
+	r := &Runtime{
+		now:      now,
+		failures: map[string]error{},
+		host: docker.HostInfo{
+			Address:       "fake://",
+			ServerVersion: "20.10.24",
+			APIVersion:    "1.41",
+			OS:            "linux",
+			Arch:          "amd64",
+		},
+		caps: docker.Capabilities{
+			APIVersion:    "1.41",
+			OSType:        "linux",
+			CgroupVersion: "2",
+			StorageDriver: "overlay2",
+			Swarm:         "inactive",
+		},
+	}
+	r.addNetwork(docker.NetworkOptions{Name: "bridge", Driver: "bridge"}, nil)
+	r.addNetwork(docker.NetworkOptions{Name: "host", Driver: "host"}, nil)
+	r.addNetwork(docker.NetworkOptions{Name: "none", Driver: "null"}, nil)
+	return r
+}
+
+// SetReadOnly makes every mutating operation fail with docker.ErrReadOnly
+func (r *Runtime) SetReadOnly(readOnly bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readOnly = readOnly
+}
+
+// ReadOnly reports whether mutating operations are disabled
+func (r *Runtime) ReadOnly() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.readOnly
+}
+
+// Ping answers unless a failure was injected with FailOn("Ping", ...)
+func (r *Runtime) Ping(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.guard("Ping")
+}
+
+// SetHostInfo replaces what HostInfo reports, e.g. to add certificates
+func (r *Runtime) SetHostInfo(info docker.HostInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.host = info
+}
+
+// HostInfo describes the simulated daemon
+func (r *Runtime) HostInfo(ctx context.Context) (docker.HostInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("HostInfo"); err != nil {
+		return docker.HostInfo{}, err
+	}
+	return r.host, nil
+}
+
+// Capabilities describes what the simulated daemon can do
+func (r *Runtime) Capabilities(ctx context.Context) (docker.Capabilities, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("Capabilities"); err != nil {
+		return docker.DefaultCapabilities(), err
+	}
+	return r.caps, nil
+}
+
+// SetCapabilities replaces what Capabilities reports. Operations the
+// capabilities rule out fail like they would on such a daemon.
+func (r *Runtime) SetCapabilities(caps docker.Capabilities) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.caps = caps
+}
+
+// FailOn makes every call to the named method return err, e.g.
+// FailOn("ListContainers", errors.New("daemon gone")). A nil err clears it.
+func (r *Runtime) FailOn(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		delete(r.failures, method)
+		return
+	}
+	r.failures[method] = err
+}
+
+// Now returns the simulated time
+func (r *Runtime) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now
+}
+
+// Events returns every state change so far, oldest first
+func (r *Runtime) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// AddContainer creates a container and returns its ID
+func (r *Runtime) AddContainer(spec ContainerSpec) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.addContainer(spec).id
+}
+
+// SetStatus moves a container to a new state, recording the matching event
+func (r *Runtime) SetStatus(containerID, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	r.setStatus(c, status)
+	return nil
+}
+
+// AppendLog adds a line to a container's logs at the current time
+func (r *Runtime) AppendLog(containerID, line string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	c.appendLog(r.now, line)
+	return nil
+}
+
+// Advance moves the clock forward. Running containers emit their log lines,
+// their stats move along their curves and crashes, OOM kills and restarts
+// happen at the moment they are due.
+func (r *Runtime) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	until := r.now.Add(d)
+	for {
+		c, at := r.nextTransition(until)
+		if c == nil {
+			break
+		}
+		r.settle(at)
+		r.transitionDue(c)
+	}
+	r.settle(until)
+}
+
+// Crash makes a running container exit on its own after logging message.
+// Its restart policy applies.
+func (r *Runtime) Crash(containerID, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	r.kill(c, false, message)
+	return nil
+}
+
+// OOM has the kernel kill a running container for running out of memory.
+// Its restart policy applies.
+func (r *Runtime) OOM(containerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	r.kill(c, true, "")
+	return nil
+}
+
+// settle moves the clock to at, emitting the logs written in between
+func (r *Runtime) settle(at time.Time) {
+	r.now = at
+	for _, c := range r.containers {
+		c.emitLogs(at)
+	}
+}
+
+// nextTransition finds the container whose crash, OOM kill or restart is
+// due first, no later than until
+func (r *Runtime) nextTransition(until time.Time) (*container, time.Time) {
+	var next *container
+	var nextAt time.Time
+	for _, c := range r.containers {
+		at, ok := c.dueAt()
+		if !ok || at.After(until) {
+			continue
+		}
+		if next == nil || at.Before(nextAt) {
+			next, nextAt = c, at
+		}
+	}
+	return next, nextAt
+}
+
+// dueAt is when the container next changes state on its own
+func (c *container) dueAt() (time.Time, bool) {
+	switch c.status {
+	case StatusRunning:
+		at, _ := c.killAt()
+		return at, !at.IsZero()
+	case StatusExited:
+		if !c.crashed || !c.restarts() {
+			return time.Time{}, false
+		}
+		return c.exited.Add(c.restartDelay()), true
+	}
+	return time.Time{}, false
+}
+
+// memoryLimit is the limit the container runs under, the host's memory
+// when it has none of its own
+func (c *container) memoryLimit() uint64 {
+	if c.spec.MemoryLimit == 0 {
+		return 2 << 30
+	}
+	return c.spec.MemoryLimit
+}
+
+// killAt is when a running container crashes or runs out of memory,
+// whichever comes first
+func (c *container) killAt() (time.Time, bool) {
+	var at time.Time
+	oom := false
+	if c.spec.CrashAfter > 0 {
+		at = c.started.Add(c.spec.CrashAfter)
+	}
+	if limit := c.memoryLimit(); c.spec.MemoryGrowth > 0 && limit > c.spec.Memory {
+		seconds := float64(limit-c.spec.Memory) / float64(c.spec.MemoryGrowth)
+		oomAt := c.started.Add(time.Duration(seconds * float64(time.Second)))
+		if at.IsZero() || oomAt.Before(at) {
+			at, oom = oomAt, true
+		}
+	}
+	return at, oom
+}
+
+func (c *container) restarts() bool {
+	switch c.spec.Restart {
+	case "always", "on-failure", "unless-stopped":
+		return true
+	}
+	return false
+}
+
+func (c *container) restartDelay() time.Duration {
+	delay := minRestartDelay
+	for i := 1; i < c.crashes && delay < maxRestartDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRestartDelay)
+}
+
+// transitionDue applies the state change dueAt announced
+func (r *Runtime) transitionDue(c *container) {
+	if c.status == StatusRunning {
+		_, oom := c.killAt()
+		r.kill(c, oom, c.spec.CrashLog)
+		return
+	}
+	r.event(c, "restart")
+	r.setStatus(c, StatusRunning)
+}
+
+// kill stops a running container as if its process died
+func (r *Runtime) kill(c *container, oom bool, message string) {
+	if c.status != StatusRunning {
+		return
+	}
+	if oom {
+		message = fmt.Sprintf("Out of memory: Killed process 1 (%s)", path.Base(strings.SplitN(c.spec.Image, ":", 2)[0]))
+		r.event(c, "oom")
+	}
+	if message != "" {
+		c.appendLog(r.now, message)
+	}
+	r.setStatus(c, StatusExited)
+	c.crashed = true
+	c.crashes++
+}
+
+// guard returns the injected failure for a method, if any
+func (r *Runtime) guard(method string) error {
+	return r.failures[method]
+}
+
+// checkWritable mirrors docker.Client's read-only mode
+func (r *Runtime) checkWritable(method string) error {
+	if err := r.guard(method); err != nil {
+		return err
+	}
+	if r.readOnly {
+		return docker.ErrReadOnly
+	}
+	return nil
+}
+
+func (r *Runtime) addContainer(spec ContainerSpec) *container {
+	r.created++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", spec.Name, r.created)))
+	if spec.Image == "" {
+		spec.Image = "alpine:latest"
+	}
+	if spec.Status == "" {
+		spec.Status = StatusRunning
+	}
+	if len(spec.Networks) == 0 {
+		spec.Networks = []string{"bridge"}
+	}
+
+	c := &container{
+		spec:    spec,
+		id:      hex.EncodeToString(sum[:]),
+		status:  StatusCreated,
+		created: r.now,
+		lastLog: r.now,
+		files:   map[string]string{},
+	}
+	for p, content := range spec.Files {
+		c.files[cleanPath(p)] = content
+	}
+	for _, line := range spec.Logs {
+		c.appendLog(r.now, line)
+	}
+	r.containers = append(r.containers, c)
+	r.event(c, "create")
+
+	for _, name := range spec.Networks {
+		if n := r.findNetwork(name); n != nil {
+			n.attach(c, nil)
+		}
+	}
+	if spec.Status != StatusCreated {
+		r.setStatus(c, spec.Status)
+	}
+	return c
+}
+
+func (r *Runtime) removeContainer(c *container) {
+	for _, n := range r.networks {
+		delete(n.endpoints, c.id)
+		delete(n.container, c.id)
+	}
+	for i, other := range r.containers {
+		if other == c {
+			r.containers = append(r.containers[:i], r.containers[i+1:]...)
+			break
+		}
+	}
+	r.event(c, "destroy")
+}
+
+func (r *Runtime) setStatus(c *container, status string) {
+	if c.status == status {
+		return
+	}
+	was := c.status
+	c.status = status
+	switch status {
+	case StatusRunning:
+		c.started = r.now
+		c.lastLog = r.now
+		r.event(c, "start")
+	case StatusExited, StatusDead:
+		c.exited = r.now
+		if was == StatusRunning {
+			r.event(c, "die")
+		}
+	case StatusRestarting:
+		r.event(c, "restart")
+	case StatusPaused:
+		r.event(c, "pause")
+	}
+}
+
+func (r *Runtime) event(c *container, action string) {
+	r.events = append(r.events, Event{
+		Time:        r.now,
+		ContainerID: c.id,
+		Name:        c.spec.Name,
+		Action:      action,
+	})
+}
+
+// find looks a container up by ID, ID prefix or name
+func (r *Runtime) find(ref string) (*container, error) {
+	for _, c := range r.containers {
+		if c.id == ref || c.spec.Name == ref || (len(ref) >= 4 && strings.HasPrefix(c.id, ref)) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("Error: No such container: %s", ref)
+}
+
+func (c *container) toContainer() docker.Container {
+	return docker.Container{
+		ID:     c.id,
+		Name:   c.spec.Name,
+		Status: c.status,
+		Ports:  append([]docker.Port(nil), c.spec.Ports...),
+		Labels: c.spec.Labels,
+		Pod:    c.spec.Pod,
+	}
+}
+
+// ListContainers returns every container, most recently created first like
+// the daemon does
+func (r *Runtime) ListContainers(ctx context.Context) ([]docker.Container, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ListContainers"); err != nil {
+		return nil, err
+	}
+	result := make([]docker.Container, 0, len(r.containers))
+	for i := len(r.containers) - 1; i >= 0; i-- {
+		result = append(result, r.containers[i].toContainer())
+	}
+	return result, nil
+}
+
+// InspectContainer returns detailed information about a container
+func (r *Runtime) InspectContainer(ctx context.Context, containerID string) (*docker.ContainerDetails, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("InspectContainer"); err != nil {
+		return nil, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &docker.ContainerDetails{
+		ID:      c.id,
+		Name:    c.spec.Name,
+		Image:   c.spec.Image,
+		State:   c.status,
+		Created: c.created.UTC().Format(time.RFC3339Nano),
+		Command: strings.Join(c.spec.Command, " "),
+	}
+	for _, p := range c.spec.Ports {
+		if p.IsPublished() {
+			details.Ports = append(details.Ports, fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type))
+		}
+	}
+	sort.Strings(details.Ports)
+	details.Networks = r.containerNetworks(c)
+	return details, nil
+}
+
+// RunOptionsOf rebuilds run options from the container's spec
+func (r *Runtime) RunOptionsOf(ctx context.Context, containerID string) (docker.RunOptions, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("RunOptionsOf"); err != nil {
+		return docker.RunOptions{}, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return docker.RunOptions{}, err
+	}
+
+	opts := docker.RunOptions{
+		Name:    c.spec.Name,
+		Image:   c.spec.Image,
+		Command: c.spec.Command,
+		Env:     c.spec.Env,
+		CPUs:    c.limits.CPUs,
+		Memory:  int64(c.spec.MemoryLimit),
+	}
+	if c.spec.Restart != "no" {
+		opts.Restart = c.spec.Restart
+	}
+	for k, v := range c.spec.Labels {
+		if !strings.HasPrefix(k, "com.docker.compose.") {
+			if opts.Labels == nil {
+				opts.Labels = map[string]string{}
+			}
+			opts.Labels[k] = v
+		}
+	}
+	for _, p := range c.spec.Ports {
+		port := strconv.Itoa(int(p.PrivatePort))
+		if p.Type != "" && p.Type != "tcp" {
+			port += "/" + p.Type
+		}
+		if p.IsPublished() {
+			port = strconv.Itoa(int(p.PublicPort)) + ":" + port
+			if p.IP != "" && p.IP != "0.0.0.0" && p.IP != "::" {
+				port = p.IP + ":" + port
+			}
+		}
+		opts.Ports = append(opts.Ports, port)
+	}
+	sort.Strings(opts.Ports)
+	if len(c.spec.Networks) > 0 {
+		if c.spec.Networks[0] != "bridge" {
+			opts.Network = c.spec.Networks[0]
+		}
+		opts.Networks = c.spec.Networks[1:]
+	}
+	return opts, nil
+}
+
+// StartContainer starts a container
+func (r *Runtime) StartContainer(ctx context.Context, containerID string) error {
+	return r.transition("StartContainer", containerID, func(c *container) {
+		r.setStatus(c, StatusRunning)
+	})
+}
+
+// StopContainer stops a container
+func (r *Runtime) StopContainer(ctx context.Context, containerID string) error {
+	return r.transition("StopContainer", containerID, func(c *container) {
+		r.stop(c)
+	})
+}
+
+// RestartContainer restarts a container
+func (r *Runtime) RestartContainer(ctx context.Context, containerID string) error {
+	return r.transition("RestartContainer", containerID, func(c *container) {
+		if c.status == StatusRunning {
+			r.setStatus(c, StatusExited)
+		}
+		r.event(c, "restart")
+		r.setStatus(c, StatusRunning)
+	})
+}
+
+// RunContainer creates and starts a container. Images are never pulled, any
+// name is taken to exist.
+func (r *Runtime) RunContainer(ctx context.Context, opts docker.RunOptions) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("RunContainer"); err != nil {
+		return "", err
+	}
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("container_%d", r.created+1)
+	}
+	if _, err := r.find(opts.Name); err == nil {
+		return "", fmt.Errorf("Conflict. The container name \"/%s\" is already in use", opts.Name)
+	}
+
+	// The fake has no images, so the entrypoint is just part of the command
+	spec := ContainerSpec{
+		Name:        opts.Name,
+		Image:       opts.Image,
+		Command:     append(append([]string(nil), opts.Entrypoint...), opts.Command...),
+		Env:         opts.ResolvedEnv(),
+		Labels:      opts.Labels,
+		Restart:     opts.Restart,
+		MemoryLimit: uint64(opts.Memory),
+	}
+	if opts.Network != "" || len(opts.Networks) > 0 {
+		spec.Networks = []string{cmp.Or(opts.Network, "bridge")}
+		spec.Networks = append(spec.Networks, opts.Networks...)
+	}
+	for _, n := range spec.Networks {
+		if r.findNetwork(n) == nil {
+			return "", fmt.Errorf("Error response from daemon: network %s not found", n)
+		}
+	}
+	_, bindings, _ := nat.ParsePortSpecs(opts.Ports)
+	for port, bs := range bindings {
+		for _, b := range bs {
+			public, _ := strconv.Atoi(b.HostPort)
+			spec.Ports = append(spec.Ports, docker.Port{IP: b.HostIP, PrivatePort: uint16(port.Int()), PublicPort: uint16(public), Type: port.Proto()})
+		}
+	}
+	sort.Slice(spec.Ports, func(i, j int) bool { return spec.Ports[i].PrivatePort < spec.Ports[j].PrivatePort })
+	c := r.addContainer(spec)
+	c.limits.CPUs = opts.CPUs
+	if opts.Memory > 0 {
+		// The daemon gives as much swap as memory unless told otherwise
+		c.limits.MemorySwap = 2 * opts.Memory
+	}
+	return c.id, nil
+}
+
+// stop stops a container on the user's request
+func (r *Runtime) stop(c *container) {
+	if c.status == StatusExited {
+		return
+	}
+	r.setStatus(c, StatusExited)
+	r.event(c, "stop")
+}
+
+func (r *Runtime) transition(method, containerID string, fn func(*container)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable(method); err != nil {
+		return err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	fn(c)
+	// The user took over, so the restart policy no longer applies
+	c.crashed = false
+	c.crashes = 0
+	return nil
+}
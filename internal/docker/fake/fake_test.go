@@ -0,0 +1,283 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// status returns the state ListContainers reports for a container
+func status(t *testing.T, r *Runtime, id string) string {
+	t.Helper()
+	containers, err := r.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range containers {
+		if c.ID == id {
+			return c.Status
+		}
+	}
+	t.Fatalf("container %s is not listed", id)
+	return ""
+}
+
+// actions returns the actions of the events after the first skip
+func actions(r *Runtime, skip int) []string {
+	var result []string
+	for _, e := range r.Events()[skip:] {
+		result = append(result, e.Action)
+	}
+	return result
+}
+
+func TestContainerTransitions(t *testing.T) {
+	ctx := context.Background()
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "web", Image: "nginx:1.25"})
+	if got := actions(r, 0); !slices.Equal(got, []string{"create", "start"}) {
+		t.Fatalf("events on creation = %q", got)
+	}
+
+	for _, step := range []struct {
+		name   string
+		do     func() error
+		status string
+		events []string
+	}{
+		{"stop", func() error { return r.StopContainer(ctx, id) }, StatusExited, []string{"die", "stop"}},
+		{"stop again", func() error { return r.StopContainer(ctx, "web") }, StatusExited, nil},
+		{"start", func() error { return r.StartContainer(ctx, id[:12]) }, StatusRunning, []string{"start"}},
+		{"restart", func() error { return r.RestartContainer(ctx, id) }, StatusRunning, []string{"die", "restart", "start"}},
+		{"pause", func() error { return r.SetStatus(id, StatusPaused) }, StatusPaused, []string{"pause"}},
+	} {
+		before := len(r.Events())
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := status(t, r, id); got != step.status {
+			t.Errorf("%s: status %s, want %s", step.name, got, step.status)
+		}
+		if got := actions(r, before); !slices.Equal(got, step.events) {
+			t.Errorf("%s: events %q, want %q", step.name, got, step.events)
+		}
+	}
+
+	if err := r.StartContainer(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "No such container") {
+		t.Errorf("starting a missing container: %v", err)
+	}
+}
+
+func TestCrashRestartBackoff(t *testing.T) {
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "worker", Restart: "always", CrashAfter: 10 * time.Second, CrashLog: "panic: boom"})
+	once := r.AddContainer(ContainerSpec{Name: "job", CrashAfter: 10 * time.Second})
+
+	r.Advance(10 * time.Second)
+	if status(t, r, id) != StatusExited || status(t, r, once) != StatusExited {
+		t.Fatalf("after the crash: worker %s, job %s", status(t, r, id), status(t, r, once))
+	}
+	logs, _ := r.GetContainerLogs(context.Background(), id, 1)
+	if !strings.Contains(logs, "panic: boom") {
+		t.Errorf("logs = %q, want the crash message", logs)
+	}
+
+	// The first restart comes after a second, the next one after two
+	r.Advance(minRestartDelay)
+	if got := status(t, r, id); got != StatusRunning {
+		t.Fatalf("after %s: %s, want restarted", minRestartDelay, got)
+	}
+	r.Advance(10*time.Second + minRestartDelay)
+	if got := status(t, r, id); got != StatusExited {
+		t.Fatalf("one second into the second backoff: %s, want exited", got)
+	}
+	r.Advance(minRestartDelay)
+	if got := status(t, r, id); got != StatusRunning {
+		t.Fatalf("after the second backoff: %s, want restarted", got)
+	}
+
+	// Without a restart policy a crashed container stays down
+	if got := status(t, r, once); got != StatusExited {
+		t.Errorf("job: %s, want it left exited", got)
+	}
+}
+
+func TestOOMKill(t *testing.T) {
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "leak", Image: "node:20", Memory: 64 << 20, MemoryLimit: 128 << 20, MemoryGrowth: 1 << 20})
+
+	r.Advance(63 * time.Second)
+	if got := status(t, r, id); got != StatusRunning {
+		t.Fatalf("below the limit: %s", got)
+	}
+	before := len(r.Events())
+	r.Advance(time.Second)
+	if got := actions(r, before); !slices.Equal(got, []string{"oom", "die"}) {
+		t.Errorf("events = %q, want oom then die", got)
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	ctx := context.Background()
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "web"})
+	boom := errors.New("daemon gone")
+
+	r.FailOn("StopContainer", boom)
+	if err := r.StopContainer(ctx, id); !errors.Is(err, boom) {
+		t.Fatalf("stop: %v, want the injected error", err)
+	}
+	if got := status(t, r, id); got != StatusRunning {
+		t.Errorf("a failed stop changed the status to %s", got)
+	}
+	// Other methods are not affected
+	if _, err := r.ListContainers(ctx); err != nil {
+		t.Errorf("list: %v", err)
+	}
+
+	r.FailOn("StopContainer", nil)
+	if err := r.StopContainer(ctx, id); err != nil {
+		t.Errorf("stop after clearing the failure: %v", err)
+	}
+
+	r.FailOn("ListContainers", boom)
+	if _, err := r.ListContainers(ctx); !errors.Is(err, boom) {
+		t.Errorf("list: %v, want the injected error", err)
+	}
+
+	r.SetReadOnly(true)
+	if err := r.StartContainer(ctx, id); !errors.Is(err, docker.ErrReadOnly) {
+		t.Errorf("start when read-only: %v", err)
+	}
+	if _, err := r.ReadFile(ctx, id, "/etc/hostname", 10); errors.Is(err, docker.ErrReadOnly) {
+		t.Errorf("reading is refused when read-only: %v", err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	ctx := context.Background()
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "web", Files: map[string]string{
+		"/etc/nginx/nginx.conf": "worker_processes 1;\n",
+		"/etc/hosts":            "127.0.0.1 localhost\n",
+	}})
+
+	entries, _, err := r.ListDir(ctx, id, "/etc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	// Directories first
+	if !slices.Equal(names, []string{"nginx", "hosts"}) || !entries[0].IsDir() {
+		t.Errorf("/etc lists %q", names)
+	}
+	if _, _, err := r.ListDir(ctx, id, "/etc/hosts"); err == nil {
+		t.Error("listed a file as a directory")
+	}
+
+	if content, err := r.ReadFile(ctx, id, "/etc/nginx/nginx.conf", 6); err != nil || content != "worker" {
+		t.Errorf("read = %q, %v, want the first 6 bytes", content, err)
+	}
+	if _, err := r.ReadFile(ctx, id, "/etc/nginx", 10); err == nil {
+		t.Error("read a directory")
+	}
+	if _, err := r.StatPath(ctx, id, "/nope"); err == nil {
+		t.Error("stat of a missing path succeeded")
+	}
+
+	dir := t.TempDir()
+	local := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(local, []byte("<h1>hi</h1>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var written, total int64
+	err = r.UploadToContainer(ctx, id, []string{local}, "/usr/share/nginx/html", false, func(w, n int64) { written, total = w, n })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := r.ReadFile(ctx, id, "/usr/share/nginx/html/index.html", 100); content != "<h1>hi</h1>" || written != total || total != 11 {
+		t.Errorf("uploaded %q, progress %d of %d", content, written, total)
+	}
+
+	saved := filepath.Join(dir, "nginx")
+	if err := r.SaveToHost(ctx, id, "/etc/nginx", saved, true); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(filepath.Join(saved, "nginx.conf")); err != nil || string(content) != "worker_processes 1;\n" {
+		t.Errorf("saved %q, %v", content, err)
+	}
+
+	// Windows containers cannot be browsed
+	r.SetCapabilities(docker.Capabilities{OSType: "windows"})
+	if _, _, err := r.ListDir(ctx, id, "/"); !errors.Is(err, docker.ErrUnsupported) {
+		t.Errorf("list on Windows: %v, want unsupported", err)
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	ctx := context.Background()
+	r := New(start)
+	id := r.AddContainer(ContainerSpec{Name: "api"})
+
+	netID, err := r.CreateNetwork(ctx, docker.NetworkOptions{Name: "back"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateNetwork(ctx, docker.NetworkOptions{Name: "back"}); err == nil {
+		t.Error("created a second network named back")
+	}
+
+	if err := r.ConnectNetwork(ctx, "back", id, []string{"api.internal"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ConnectNetwork(ctx, netID, "api", nil); err == nil {
+		t.Error("connected the same container twice")
+	}
+	back, err := r.InspectNetwork(ctx, netID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Driver != "bridge" || len(back.Containers) != 1 || back.Containers[0].Name != "api" || !strings.HasSuffix(back.Containers[0].IPv4Address, ".2/24") {
+		t.Errorf("back = %+v, want api on the first address of a bridge", back)
+	}
+
+	details, err := r.InspectContainer(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var joined []string
+	for _, n := range details.Networks {
+		joined = append(joined, n.Name)
+	}
+	if !slices.Equal(joined, []string{"back", "bridge"}) {
+		t.Errorf("api is on %q", joined)
+	}
+
+	if err := r.RemoveNetwork(ctx, "back"); err == nil {
+		t.Error("removed a network in use")
+	}
+	if err := r.DisconnectNetwork(ctx, "back", id); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DisconnectNetwork(ctx, "back", id); err == nil {
+		t.Error("disconnected a container that was not connected")
+	}
+	if err := r.RemoveNetwork(ctx, "back"); err != nil {
+		t.Errorf("remove: %v", err)
+	}
+	if err := r.RemoveNetwork(ctx, "bridge"); err == nil {
+		t.Error("removed the predefined bridge network")
+	}
+}
@@ -0,0 +1,289 @@
+package fake
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// stat describes a path of the container's file tree. Directories exist
+// implicitly above every file.
+func (c *container) stat(p string) (docker.FileEntry, bool) {
+	p = cleanPath(p)
+	if content, ok := c.files[p]; ok {
+		return docker.FileEntry{
+			Name: path.Base(p),
+			Path: p,
+			Size: int64(len(content)),
+			Mode: 0o644,
+		}, true
+	}
+	if p == "/" || c.hasChildren(p) {
+		return docker.FileEntry{
+			Name: path.Base(p),
+			Path: p,
+			Size: 4096,
+			Mode: fs.ModeDir | 0o755,
+		}, true
+	}
+	return docker.FileEntry{}, false
+}
+
+func (c *container) hasChildren(dir string) bool {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for p := range c.files {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// under returns the files at or below p, sorted
+func (c *container) under(p string) []string {
+	prefix := strings.TrimSuffix(p, "/") + "/"
+	var paths []string
+	for f := range c.files {
+		if f == p || strings.HasPrefix(f, prefix) {
+			paths = append(paths, f)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func noSuchPath(p string) error {
+	return fmt.Errorf("Error: No such container:path: %s", p)
+}
+
+// StatPath returns information about a path inside a container
+func (r *Runtime) StatPath(ctx context.Context, containerID, containerPath string) (docker.FileEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("StatPath"); err != nil {
+		return docker.FileEntry{}, err
+	}
+	if err := r.caps.Supports(docker.FeatureFiles); err != nil {
+		return docker.FileEntry{}, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return docker.FileEntry{}, err
+	}
+	entry, ok := c.stat(containerPath)
+	if !ok {
+		return docker.FileEntry{}, noSuchPath(containerPath)
+	}
+	entry.ModTime = c.created
+	return entry, nil
+}
+
+// ListDir returns the direct children of a directory inside a container
+func (r *Runtime) ListDir(ctx context.Context, containerID, dir string) ([]docker.FileEntry, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ListDir"); err != nil {
+		return nil, false, err
+	}
+	if err := r.caps.Supports(docker.FeatureFiles); err != nil {
+		return nil, false, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return nil, false, err
+	}
+	dir = cleanPath(dir)
+	entry, ok := c.stat(dir)
+	if !ok {
+		return nil, false, noSuchPath(dir)
+	}
+	if !entry.IsDir() {
+		return nil, false, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	seen := map[string]bool{}
+	var entries []docker.FileEntry
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for p := range c.files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(p, prefix), "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		child, _ := c.stat(path.Join(dir, name))
+		child.ModTime = c.created
+		entries = append(entries, child)
+	}
+
+	// Directories first, then alphabetical
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, false, nil
+}
+
+// ReadFile returns up to limit bytes of a regular file inside a container
+func (r *Runtime) ReadFile(ctx context.Context, containerID, filePath string, limit int64) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ReadFile"); err != nil {
+		return "", err
+	}
+	if err := r.caps.Supports(docker.FeatureFiles); err != nil {
+		return "", err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return "", err
+	}
+	filePath = cleanPath(filePath)
+	content, ok := c.files[filePath]
+	if !ok {
+		if c.hasChildren(filePath) {
+			return "", fmt.Errorf("%s is a directory", filePath)
+		}
+		return "", noSuchPath(filePath)
+	}
+	if int64(len(content)) > limit {
+		content = content[:limit]
+	}
+	return content, nil
+}
+
+// SaveToHost copies a file or directory from a container to the host.
+// When extract is false a tar archive is written to dst, otherwise the
+// file or directory itself is recreated at dst.
+func (r *Runtime) SaveToHost(ctx context.Context, containerID, srcPath, dst string, extract bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("SaveToHost"); err != nil {
+		return err
+	}
+	if err := r.caps.Supports(docker.FeatureFiles); err != nil {
+		return err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	srcPath = cleanPath(srcPath)
+	paths := c.under(srcPath)
+	if len(paths) == 0 {
+		return noSuchPath(srcPath)
+	}
+	// Paths in the archive and on the host are relative to the source's parent
+	base := path.Dir(srcPath)
+
+	if !extract {
+		file, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		tw := tar.NewWriter(file)
+		for _, p := range paths {
+			name := strings.TrimPrefix(strings.TrimPrefix(p, base), "/")
+			hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(c.files[p])), ModTime: c.created}
+			if err := tw.WriteHeader(hdr); err != nil {
+				file.Close()
+				return err
+			}
+			if _, err := tw.Write([]byte(c.files[p])); err != nil {
+				file.Close()
+				return err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	for _, p := range paths {
+		target := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(p, srcPath)))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(c.files[p]), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UploadToContainer copies local files and directories into dstDir inside
+// a container. A directory is only replaced by a file when overwrite is set.
+func (r *Runtime) UploadToContainer(ctx context.Context, containerID string, srcPaths []string, dstDir string, overwrite bool, progress docker.UploadProgress) error {
+	total, err := docker.LocalSize(srcPaths)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("UploadToContainer"); err != nil {
+		return err
+	}
+	if err := r.caps.Supports(docker.FeatureFiles); err != nil {
+		return err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	dstDir = cleanPath(dstDir)
+	if _, ok := c.files[dstDir]; ok {
+		return fmt.Errorf("%s is not a directory", dstDir)
+	}
+
+	var written int64
+	for _, src := range srcPaths {
+		src = filepath.Clean(src)
+		root := filepath.Dir(src)
+		err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			target := path.Join(dstDir, filepath.ToSlash(rel))
+			if c.hasChildren(target) && !overwrite {
+				return fmt.Errorf("cannot overwrite directory %q with non-directory", target)
+			}
+			content, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			c.files[target] = string(content)
+			written += int64(len(content))
+			if progress != nil {
+				progress(written, total)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
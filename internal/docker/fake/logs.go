@@ -0,0 +1,174 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// followPoll is how often a followed log stream checks for new lines
+const followPoll = 100 * time.Millisecond
+
+func (c *container) appendLog(at time.Time, line string) {
+	c.logs = append(c.logs, logEntry{time: at, text: line})
+	if len(c.logs) > maxLogLines {
+		c.logs = c.logs[len(c.logs)-maxLogLines:]
+	}
+}
+
+// emitLogs writes the lines a running container produced up to now
+func (c *container) emitLogs(now time.Time) {
+	if c.status != StatusRunning || c.spec.LogEvery <= 0 || len(c.spec.LogLines) == 0 {
+		c.lastLog = now
+		return
+	}
+	for at := c.lastLog.Add(c.spec.LogEvery); !at.After(now); at = at.Add(c.spec.LogEvery) {
+		line := c.spec.LogLines[c.nextLine%len(c.spec.LogLines)]
+		c.nextLine++
+		line = strings.ReplaceAll(line, "{n}", strconv.Itoa(c.nextLine))
+		c.appendLog(at, line)
+		c.lastLog = at
+	}
+}
+
+// tail returns the last n entries, or all of them when n is 0
+func (c *container) tail(n int) []logEntry {
+	if n <= 0 || n >= len(c.logs) {
+		return c.logs
+	}
+	return c.logs[len(c.logs)-n:]
+}
+
+func formatLogs(entries []logEntry, timestamps bool) string {
+	var b strings.Builder
+	for _, e := range entries {
+		if timestamps {
+			b.WriteString(e.time.UTC().Format(time.RFC3339Nano))
+			b.WriteByte(' ')
+		}
+		b.WriteString(e.text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// GetContainerLogs returns the last tail lines of a container's logs, or
+// all of them when tail is 0
+func (r *Runtime) GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("GetContainerLogs"); err != nil {
+		return "", err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return "", err
+	}
+	return formatLogs(c.tail(tail), false), nil
+}
+
+// StreamContainerLogs copies a container's logs to stdout. When following
+// it keeps polling for new lines until the container stops running or ctx
+// is cancelled.
+func (r *Runtime) StreamContainerLogs(ctx context.Context, containerID string, opts docker.LogOptions, stdout, stderr io.Writer) error {
+	r.mu.Lock()
+	if err := r.guard("StreamContainerLogs"); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	tail := 0
+	if opts.Tail != "" && opts.Tail != "all" {
+		if _, err := fmt.Sscan(opts.Tail, &tail); err != nil {
+			r.mu.Unlock()
+			return fmt.Errorf("invalid tail %q", opts.Tail)
+		}
+	}
+	entries := c.tail(tail)
+	var last time.Time
+	if len(entries) > 0 {
+		last = entries[len(entries)-1].time
+	}
+	r.mu.Unlock()
+
+	if _, err := io.WriteString(stdout, formatLogs(entries, opts.Timestamps)); err != nil {
+		return err
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(followPoll):
+		}
+		r.mu.Lock()
+		var fresh []logEntry
+		for _, e := range c.logs {
+			if e.time.After(last) {
+				fresh = append(fresh, e)
+			}
+		}
+		running := c.status == StatusRunning
+		r.mu.Unlock()
+
+		if len(fresh) > 0 {
+			last = fresh[len(fresh)-1].time
+			if _, err := io.WriteString(stdout, formatLogs(fresh, opts.Timestamps)); err != nil {
+				return err
+			}
+		}
+		if !running {
+			return nil
+		}
+	}
+}
+
+// GetProjectLogs returns the logs of every service in a compose project,
+// merged in time order and prefixed with the service name
+func (r *Runtime) GetProjectLogs(ctx context.Context, project string, tail int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("GetProjectLogs"); err != nil {
+		return "", err
+	}
+
+	type logLine struct {
+		time time.Time
+		text string
+	}
+	var lines []logLine
+	containers := r.projectContainers(project)
+	width := 0
+	for _, c := range containers {
+		width = max(width, len(c.spec.Labels[docker.ComposeServiceLabel]))
+	}
+	for _, c := range containers {
+		prefix := fmt.Sprintf("%-*s | ", width, c.spec.Labels[docker.ComposeServiceLabel])
+		for _, e := range c.tail(tail) {
+			lines = append(lines, logLine{time: e.time, text: prefix + e.text})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].time.Before(lines[j].time)
+	})
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,251 @@
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+type endpoint struct {
+	ip      string
+	mac     string
+	aliases []string
+}
+
+type network struct {
+	id        string
+	opts      docker.NetworkOptions
+	labels    map[string]string
+	prefix    string // first three octets of the subnet
+	nextHost  int
+	endpoints map[string]*endpoint
+	container map[string]*container
+}
+
+// addNetwork creates a network, giving bridge networks a /24 subnet in
+// 172.x.0.0 when none is set
+func (r *Runtime) addNetwork(opts docker.NetworkOptions, labels map[string]string) *network {
+	sum := sha256.Sum256([]byte("network/" + opts.Name))
+	if opts.Driver == "" {
+		opts.Driver = "bridge"
+	}
+	n := &network{
+		id:        hex.EncodeToString(sum[:]),
+		opts:      opts,
+		labels:    labels,
+		nextHost:  2,
+		endpoints: map[string]*endpoint{},
+		container: map[string]*container{},
+	}
+	if opts.Driver == "bridge" {
+		n.prefix = fmt.Sprintf("172.%d.0", 17+len(r.networks))
+		if n.opts.Subnet == "" {
+			n.opts.Subnet = n.prefix + ".0/24"
+		}
+		if n.opts.Gateway == "" {
+			n.opts.Gateway = n.prefix + ".1"
+		}
+	}
+	r.networks = append(r.networks, n)
+	return n
+}
+
+// attach connects a container, handing out the next free address
+func (n *network) attach(c *container, aliases []string) {
+	ep := &endpoint{aliases: aliases}
+	if n.prefix != "" {
+		ep.ip = fmt.Sprintf("%s.%d", n.prefix, n.nextHost)
+		ep.mac = fmt.Sprintf("02:42:ac:11:00:%02x", n.nextHost)
+		n.nextHost++
+	}
+	n.endpoints[c.id] = ep
+	n.container[c.id] = c
+}
+
+func (n *network) toNetwork() docker.Network {
+	result := docker.Network{
+		ID:       n.id,
+		Name:     n.opts.Name,
+		Driver:   n.opts.Driver,
+		Scope:    "local",
+		Subnet:   n.opts.Subnet,
+		Gateway:  n.opts.Gateway,
+		Internal: n.opts.Internal,
+	}
+	for id, ep := range n.endpoints {
+		e := docker.NetworkEndpoint{
+			ContainerID: id,
+			Name:        n.container[id].spec.Name,
+			MacAddress:  ep.mac,
+		}
+		if ep.ip != "" {
+			e.IPv4Address = ep.ip + "/24"
+		}
+		result.Containers = append(result.Containers, e)
+	}
+	sort.Slice(result.Containers, func(i, j int) bool {
+		return result.Containers[i].Name < result.Containers[j].Name
+	})
+	return result
+}
+
+// findNetwork looks a network up by ID or name
+func (r *Runtime) findNetwork(ref string) *network {
+	for _, n := range r.networks {
+		if n.id == ref || n.opts.Name == ref {
+			return n
+		}
+	}
+	return nil
+}
+
+func (r *Runtime) containerNetworks(c *container) []docker.ContainerNetwork {
+	var result []docker.ContainerNetwork
+	for _, n := range r.networks {
+		ep, ok := n.endpoints[c.id]
+		if !ok {
+			continue
+		}
+		result = append(result, docker.ContainerNetwork{
+			NetworkID:  n.id,
+			Name:       n.opts.Name,
+			IPAddress:  ep.ip,
+			Gateway:    n.opts.Gateway,
+			MacAddress: ep.mac,
+			Aliases:    ep.aliases,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ListNetworks returns all networks with their attached containers
+func (r *Runtime) ListNetworks(ctx context.Context) ([]docker.Network, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ListNetworks"); err != nil {
+		return nil, err
+	}
+	result := make([]docker.Network, 0, len(r.networks))
+	for _, n := range r.networks {
+		result = append(result, n.toNetwork())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// InspectNetwork returns a single network by ID or name
+func (r *Runtime) InspectNetwork(ctx context.Context, networkID string) (docker.Network, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("InspectNetwork"); err != nil {
+		return docker.Network{}, err
+	}
+	n := r.findNetwork(networkID)
+	if n == nil {
+		return docker.Network{}, fmt.Errorf("Error: No such network: %s", networkID)
+	}
+	return n.toNetwork(), nil
+}
+
+// CreateNetwork creates a network and returns its ID
+func (r *Runtime) CreateNetwork(ctx context.Context, opts docker.NetworkOptions) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("CreateNetwork"); err != nil {
+		return "", err
+	}
+	if opts.Driver == "overlay" {
+		if err := r.caps.Supports(docker.FeatureOverlayNetworks); err != nil {
+			return "", err
+		}
+	}
+	if r.findNetwork(opts.Name) != nil {
+		return "", fmt.Errorf("network with name %s already exists", opts.Name)
+	}
+	return r.addNetwork(opts, nil).id, nil
+}
+
+// RemoveNetwork removes a network that no container is attached to
+func (r *Runtime) RemoveNetwork(ctx context.Context, networkID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("RemoveNetwork"); err != nil {
+		return err
+	}
+	n := r.findNetwork(networkID)
+	if n == nil {
+		return fmt.Errorf("Error: No such network: %s", networkID)
+	}
+	switch n.opts.Name {
+	case "bridge", "host", "none":
+		return fmt.Errorf("%s is a pre-defined network and cannot be removed", n.opts.Name)
+	}
+	if len(n.endpoints) > 0 {
+		return fmt.Errorf("error while removing network: network %s has active endpoints", n.opts.Name)
+	}
+	r.removeNetwork(n)
+	return nil
+}
+
+func (r *Runtime) removeNetwork(n *network) {
+	for i, other := range r.networks {
+		if other == n {
+			r.networks = append(r.networks[:i], r.networks[i+1:]...)
+			return
+		}
+	}
+}
+
+// ConnectNetwork attaches a container to a network with optional aliases
+func (r *Runtime) ConnectNetwork(ctx context.Context, networkID, containerID string, aliases []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("ConnectNetwork"); err != nil {
+		return err
+	}
+	n := r.findNetwork(networkID)
+	if n == nil {
+		return fmt.Errorf("Error: No such network: %s", networkID)
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	if _, ok := n.endpoints[c.id]; ok {
+		return fmt.Errorf("endpoint with name %s already exists in network %s", c.spec.Name, n.opts.Name)
+	}
+	n.attach(c, aliases)
+	return nil
+}
+
+// DisconnectNetwork detaches a container from a network
+func (r *Runtime) DisconnectNetwork(ctx context.Context, networkID, containerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("DisconnectNetwork"); err != nil {
+		return err
+	}
+	n := r.findNetwork(networkID)
+	if n == nil {
+		return fmt.Errorf("Error: No such network: %s", networkID)
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	if _, ok := n.endpoints[c.id]; !ok {
+		return fmt.Errorf("container %s is not connected to network %s", c.spec.Name, n.opts.Name)
+	}
+	delete(n.endpoints, c.id)
+	delete(n.container, c.id)
+	return nil
+}
@@ -0,0 +1,68 @@
+package fake
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// resources combines the limits kept on the container with the memory
+// limit and restart policy the simulation reads from its spec
+func (c *container) resources() docker.Resources {
+	res := c.limits
+	res.Memory = int64(c.spec.MemoryLimit)
+	res.Restart = cmp.Or(c.spec.Restart, "no")
+	return res
+}
+
+// ContainerResources returns the limits of a container
+func (r *Runtime) ContainerResources(ctx context.Context, containerID string) (docker.Resources, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("ContainerResources"); err != nil {
+		return docker.Resources{}, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return docker.Resources{}, err
+	}
+	return c.resources(), nil
+}
+
+// UpdateResources changes the limits of a container. Like the daemon, it
+// leaves the limits given as zero unchanged.
+func (r *Runtime) UpdateResources(ctx context.Context, containerID string, res docker.Resources) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkWritable("UpdateResources"); err != nil {
+		return err
+	}
+	if err := r.caps.Supports(docker.FeatureResourceLimits); err != nil {
+		return err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return err
+	}
+	if err := res.ValidateUpdate(c.resources()); err != nil {
+		return err
+	}
+
+	if res.CPUShares != 0 {
+		c.limits.CPUShares = res.CPUShares
+	}
+	if res.CPUs != 0 {
+		c.limits.CPUs, c.limits.CPUPeriod = res.CPUs, res.CPUPeriod
+	}
+	if res.Memory != 0 {
+		c.spec.MemoryLimit = uint64(res.Memory)
+	}
+	if res.MemorySwap != 0 {
+		c.limits.MemorySwap = res.MemorySwap
+	}
+	c.limits.PidsLimit = res.PidsLimit
+	c.spec.Restart = res.Restart
+	r.event(c, "update")
+	return nil
+}
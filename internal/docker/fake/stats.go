@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// GetContainerStats returns stats that follow a smooth curve around the
+// container's configured averages. Containers that are not running report
+// zeros, like the daemon.
+func (r *Runtime) GetContainerStats(ctx context.Context, containerID string) (*docker.ContainerStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.guard("GetContainerStats"); err != nil {
+		return nil, err
+	}
+	if err := r.caps.Supports(docker.FeatureStats); err != nil {
+		return nil, err
+	}
+	c, err := r.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if c.status != StatusRunning {
+		return &docker.ContainerStats{MemoryLimit: c.memoryLimit()}, nil
+	}
+
+	uptime := r.now.Sub(c.started).Seconds()
+	phase := c.phase()
+	// Two waves of different periods so the curve does not look periodic
+	wave := func(period float64) float64 {
+		return math.Sin(2*math.Pi*uptime/period + phase)
+	}
+
+	cpu := c.spec.CPU * (1 + 0.35*wave(90) + 0.15*wave(17))
+	if c.limits.CPUs > 0 {
+		cpu = math.Min(cpu, c.limits.CPUs*100)
+	}
+	// Leaking containers grow steadily and are killed once they hit the limit
+	base := float64(c.spec.Memory) + float64(c.spec.MemoryGrowth)*uptime
+	memory := base * (1 + 0.08*wave(300) + 0.02*wave(23))
+	memory = math.Min(memory, float64(c.memoryLimit()))
+
+	stats := &docker.ContainerStats{
+		CPUPercentage: math.Max(cpu, 0),
+		MemoryUsage:   uint64(memory),
+		MemoryLimit:   c.memoryLimit(),
+		PIDs:          c.spec.PIDs,
+	}
+	stats.MemoryPercentage = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	// Traffic and disk IO grow with uptime at a rate tied to CPU usage
+	load := 1 + c.spec.CPU
+	stats.NetworkRx = uint64(uptime * load * 4096)
+	stats.NetworkTx = uint64(uptime * load * 1024)
+	stats.BlockRead = uint64(uptime * load * 512)
+	stats.BlockWrite = uint64(uptime * load * 2048)
+	if stats.PIDs == 0 {
+		stats.PIDs = 1
+	}
+	return stats, nil
+}
+
+// phase spreads containers across their curves so they do not move in step
+func (c *container) phase() float64 {
+	n, _ := strconv.ParseUint(c.id[:8], 16, 64)
+	return float64(n%360) * math.Pi / 180
+}
@@ -0,0 +1,470 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// maxDirEntries caps how many tar headers are scanned when listing a directory
+const maxDirEntries = 20000
+
+// FileEntry represents a file or directory inside a container
+type FileEntry struct {
+	Name       string
+	Path       string
+	Size       int64
+	Mode       os.FileMode
+	ModTime    time.Time
+	LinkTarget string
+}
+
+// IsDir reports whether the entry is a directory
+func (e FileEntry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// StatPath returns information about a path inside a container
+func (c *Client) StatPath(ctx context.Context, containerID, containerPath string) (_ FileEntry, err error) {
+	ctx, done := limit(ctx, c.timeouts.Files)
+	defer done(&err)
+	stat, err := c.api(ctx).ContainerStatPath(ctx, containerID, containerPath)
+	if err != nil {
+		return FileEntry{}, c.unsupported("reading files", err)
+	}
+
+	return FileEntry{
+		Name:       stat.Name,
+		Path:       containerPath,
+		Size:       stat.Size,
+		Mode:       stat.Mode,
+		ModTime:    stat.Mtime,
+		LinkTarget: stat.LinkTarget,
+	}, nil
+}
+
+// ListDir returns the direct children of a directory inside a container.
+// The second return value is true when the listing was cut short.
+func (c *Client) ListDir(ctx context.Context, containerID, dir string) (_ []FileEntry, _ bool, err error) {
+	ctx, done := limit(ctx, c.timeouts.Files)
+	defer done(&err)
+
+	dir = path.Clean("/" + dir)
+	reader, stat, err := c.api(ctx).CopyFromContainer(ctx, containerID, dir)
+	if err != nil {
+		return nil, false, c.unsupported("reading files", err)
+	}
+	defer reader.Close()
+
+	if !stat.Mode.IsDir() {
+		return nil, false, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	// The archive is rooted at the base name of the requested directory,
+	// except for "/" whose children sit at the top level
+	root := ""
+	if dir != "/" {
+		root = strings.Trim(stat.Name, "/") + "/"
+	}
+	var entries []FileEntry
+	truncated := false
+
+	tr := tar.NewReader(reader)
+	for scanned := 0; ; scanned++ {
+		if scanned >= maxDirEntries {
+			truncated = true
+			break
+		}
+
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Strip the root component and keep only direct children
+		name := strings.TrimPrefix(strings.Trim(hdr.Name, "/"), "./")
+		if !strings.HasPrefix(name, root) {
+			continue
+		}
+		name = strings.TrimPrefix(name, root)
+		if name == "" || name == "." || strings.Contains(name, "/") {
+			continue
+		}
+
+		entries = append(entries, FileEntry{
+			Name:       name,
+			Path:       path.Join(dir, name),
+			Size:       hdr.Size,
+			Mode:       hdr.FileInfo().Mode(),
+			ModTime:    hdr.ModTime,
+			LinkTarget: hdr.Linkname,
+		})
+	}
+
+	sortEntries(entries)
+	return entries, truncated, nil
+}
+
+// sortEntries puts directories first, then sorts by name
+func sortEntries(entries []FileEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// ReadFile returns up to maxBytes of a regular file inside a container
+func (c *Client) ReadFile(ctx context.Context, containerID, filePath string, maxBytes int64) (_ string, err error) {
+	ctx, done := limit(ctx, c.timeouts.Files)
+	defer done(&err)
+	reader, stat, err := c.api(ctx).CopyFromContainer(ctx, containerID, filePath)
+	if err != nil {
+		return "", c.unsupported("reading files", err)
+	}
+	defer reader.Close()
+
+	if stat.Mode.IsDir() {
+		return "", fmt.Errorf("%s is a directory", filePath)
+	}
+
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return "", err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(tr, maxBytes))
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// SaveToHost copies a file or directory from a container to the host.
+// When extract is false the raw tar archive is written to dst, otherwise
+// the file or directory itself is recreated at dst.
+func (c *Client) SaveToHost(ctx context.Context, containerID, srcPath, dst string, extract bool) (err error) {
+	ctx, done := limit(ctx, c.timeouts.Files)
+	defer done(&err)
+	reader, _, err := c.api(ctx).CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return c.unsupported("copying files", err)
+	}
+	defer reader.Close()
+
+	if !extract {
+		file, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, reader); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	return extractTar(reader, dst)
+}
+
+// extractTar unpacks a tar stream so that its root entry becomes dst,
+// refusing entries that would escape it. Links are made after everything
+// else, so nothing in the archive is written through one, and links that
+// point outside dst are left out and reported.
+func extractTar(r io.Reader, dst string) error {
+	root := filepath.Clean(dst)
+	type link struct {
+		target, linkname, entry string
+	}
+	var links, hardlinks []link
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := archiveTarget(root, hdr.Name)
+		if err != nil {
+			return err
+		}
+		// dst may already hold links of its own
+		if err := noLinks(root, target); err != nil {
+			return err
+		}
+
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode.Perm()|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			// The name of a hard link is that of an earlier entry
+			source, err := archiveTarget(root, hdr.Linkname)
+			if err != nil {
+				return err
+			}
+			hardlinks = append(hardlinks, link{target: target, linkname: source, entry: hdr.Name})
+		case tar.TypeSymlink:
+			links = append(links, link{target: target, linkname: hdr.Linkname, entry: hdr.Name})
+		}
+	}
+
+	for _, l := range hardlinks {
+		if err := noLinks(root, l.linkname); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(l.target), 0o755); err != nil {
+			return err
+		}
+		// Like a regular file, a hard link replaces what is there
+		if err := os.Remove(l.target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.Link(l.linkname, l.target); err != nil {
+			return fmt.Errorf("%s: %w", l.entry, err)
+		}
+	}
+
+	var outside, made []string
+	for _, l := range links {
+		if filepath.IsAbs(l.linkname) || !within(root, filepath.Join(filepath.Dir(l.target), l.linkname)) {
+			outside = append(outside, l.entry)
+			continue
+		}
+		if err := noLinks(root, filepath.Dir(l.target)); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(l.target), 0o755); err != nil {
+			return err
+		}
+		if err := os.Symlink(l.linkname, l.target); err != nil {
+			return err
+		}
+		made = append(made, l.target)
+	}
+
+	// Through other links, ".." can still lead out, as from a link to "."
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	for _, target := range made {
+		resolved, err := filepath.EvalSymlinks(target)
+		if err == nil && !within(realRoot, resolved) {
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+			outside = append(outside, target)
+		}
+	}
+	if len(outside) > 0 {
+		return fmt.Errorf("left out links pointing outside %s: %s", dst, strings.Join(outside, ", "))
+	}
+	return nil
+}
+
+// archiveTarget maps the name of an archive entry onto root, dropping the
+// archive's root component
+func archiveTarget(root, name string) (string, error) {
+	rel := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if idx := strings.Index(rel, "/"); idx >= 0 {
+		rel = rel[idx+1:]
+	} else {
+		rel = ""
+	}
+
+	target := filepath.Join(root, filepath.FromSlash(rel))
+	if !within(root, target) {
+		return "", errors.New("archive entry escapes destination: " + name)
+	}
+	return target, nil
+}
+
+// within reports whether p is root or below it
+func within(root, p string) bool {
+	return p == root || strings.HasPrefix(p, root+string(os.PathSeparator))
+}
+
+// noLinks fails when a path between root and target, target included, is
+// a symbolic link
+func noLinks(root, target string) error {
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." {
+		return err
+	}
+	p := root
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		p = filepath.Join(p, part)
+		info, err := os.Lstat(p)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a link, refusing to write through it", p)
+		}
+	}
+	return nil
+}
+
+// UploadProgress is called as file content is streamed into a container
+type UploadProgress func(written, total int64)
+
+// LocalSize returns the total size of the regular files under paths
+func LocalSize(paths []string) (int64, error) {
+	var total int64
+	for _, p := range paths {
+		err := filepath.Walk(p, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				total += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// UploadToContainer copies local files and directories into dstDir inside a
+// container, preserving their permissions. When overwrite is true an
+// existing directory may be replaced by a file of the same name.
+func (c *Client) UploadToContainer(ctx context.Context, containerID string, srcPaths []string, dstDir string, overwrite bool, progress UploadProgress) (err error) {
+	if err := c.checkWritable("upload"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Files)
+	defer done(&err)
+
+	total, err := LocalSize(srcPaths)
+	if err != nil {
+		return err
+	}
+
+	counter := &progressCounter{total: total, fn: progress}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, srcPaths, counter))
+	}()
+	// Unblock the writer if the daemon stops reading early
+	defer pr.Close()
+
+	err = c.api(ctx).CopyToContainer(ctx, containerID, dstDir, pr, types.CopyToContainerOptions{
+		AllowOverwriteDirWithFile: overwrite,
+	})
+	return c.unsupported("uploading files", err)
+}
+
+// progressCounter counts file bytes written into an archive
+type progressCounter struct {
+	written int64
+	total   int64
+	fn      UploadProgress
+}
+
+func (p *progressCounter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if p.fn != nil {
+		p.fn(p.written, p.total)
+	}
+	return len(b), nil
+}
+
+// writeTar archives each source path under its base name
+func writeTar(w io.Writer, srcPaths []string, counter io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	for _, src := range srcPaths {
+		src = filepath.Clean(src)
+		base := filepath.Dir(src)
+
+		err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			link := ""
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(file); err != nil {
+					return err
+				}
+			}
+
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(base, file)
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = io.Copy(io.MultiWriter(tw, counter), f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
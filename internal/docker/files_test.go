@@ -0,0 +1,317 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tarEntry struct {
+	name, body, link, hardlink string
+	dir                        bool
+}
+
+func buildTar(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeDir, 0o755, 0
+		case e.link != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.link, 0
+		case e.hardlink != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeLink, e.hardlink, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "app")
+	archive := buildTar(t,
+		tarEntry{name: "app/", dir: true},
+		tarEntry{name: "app/conf/", dir: true},
+		tarEntry{name: "app/conf/app.yaml", body: "port: 80\n"},
+		tarEntry{name: "app/current", link: "conf/app.yaml"},
+		tarEntry{name: "app/conf/copy.yaml", hardlink: "app/conf/app.yaml"},
+	)
+	if err := extractTar(archive, dst); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"current", "conf/copy.yaml"} {
+		content, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil || string(content) != "port: 80\n" {
+			t.Errorf("%s = %q, %v, want the linked file", name, content, err)
+		}
+	}
+	original, _ := os.Stat(filepath.Join(dst, "conf/app.yaml"))
+	copied, _ := os.Stat(filepath.Join(dst, "conf/copy.yaml"))
+	if !os.SameFile(original, copied) {
+		t.Error("the hard link was extracted as a separate file")
+	}
+}
+
+func TestExtractTarEscapes(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"write through a link", []tarEntry{
+			{name: "app/", dir: true},
+			{name: "app/link", link: "../outside"},
+			{name: "app/link/evil", body: "x"},
+		}},
+		{"absolute link", []tarEntry{
+			{name: "app/", dir: true},
+			{name: "app/passwd", link: "/etc/passwd"},
+		}},
+		{"hard link out of the archive", []tarEntry{
+			{name: "app/", dir: true},
+			{name: "app/passwd", hardlink: "../../etc/passwd"},
+		}},
+		{"dot dot through a link to dot", []tarEntry{
+			{name: "app/", dir: true},
+			{name: "app/here", link: "."},
+			{name: "app/up", link: "here/.."},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dst := filepath.Join(parent, "app")
+			err := extractTar(buildTar(t, tc.entries...), dst)
+			if err == nil {
+				t.Fatal("extracted without an error")
+			}
+			// Nothing lands next to dst, and no link leads there
+			entries, _ := os.ReadDir(parent)
+			for _, e := range entries {
+				if e.Name() != "app" {
+					t.Errorf("%s was written outside the destination (%v)", e.Name(), err)
+				}
+			}
+			filepath.Walk(dst, func(p string, info os.FileInfo, err error) error {
+				if err == nil && info.Mode()&os.ModeSymlink != 0 {
+					resolved, _ := filepath.EvalSymlinks(p)
+					realDst, _ := filepath.EvalSymlinks(dst)
+					if resolved != "" && !strings.HasPrefix(resolved+"/", realDst+"/") {
+						t.Errorf("%s resolves to %s, outside the destination", p, resolved)
+					}
+				}
+				return nil
+			})
+		})
+	}
+}
+
+func TestExtractTarExistingLink(t *testing.T) {
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "app")
+	outside := filepath.Join(parent, "outside")
+	for _, dir := range []string{dst, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(dst, "data")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := buildTar(t, tarEntry{name: "app/data/file", body: "x"})
+	if err := extractTar(archive, dst); err == nil {
+		t.Error("wrote through a link already in the destination")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "file")); err == nil {
+		t.Error("the file was written outside the destination")
+	}
+}
+
+// serveDaemon answers the client's calls with mux on a unix socket
+func serveDaemon(t *testing.T, mux *http.ServeMux, opts Options) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		w.Write([]byte("OK"))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	opts.Host = "unix://" + socket
+	c, err := NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// readTar returns the headers of an archive by name, with file contents
+func readTar(t *testing.T, r io.Reader) (map[string]*tar.Header, map[string]string) {
+	t.Helper()
+	headers, contents := map[string]*tar.Header{}, map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return headers, contents
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(tr)
+		headers[hdr.Name], contents[hdr.Name] = hdr, string(body)
+	}
+}
+
+// uploadSources creates a script, a directory tree and a link to upload
+func uploadSources(t *testing.T) (script, site, link string) {
+	t.Helper()
+	dir := t.TempDir()
+	script = filepath.Join(dir, "run.sh")
+	site = filepath.Join(dir, "site")
+	link = filepath.Join(dir, "latest")
+	for _, f := range []struct {
+		path, body string
+		mode       os.FileMode
+	}{
+		{script, "#!/bin/sh\n", 0o755},
+		{filepath.Join(site, "index.html"), "<h1>hi</h1>", 0o644},
+		{filepath.Join(site, "css", "main.css"), "body{}", 0o600},
+	} {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f.path, []byte(f.body), f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("site/index.html", link); err != nil {
+		t.Fatal(err)
+	}
+	return script, site, link
+}
+
+func TestWriteTar(t *testing.T) {
+	script, site, link := uploadSources(t)
+
+	var archive, counted bytes.Buffer
+	// A trailing separator names the same directory
+	if err := writeTar(&archive, []string{script, site + "/", link}, &counted); err != nil {
+		t.Fatal(err)
+	}
+	headers, contents := readTar(t, &archive)
+
+	for _, tc := range []struct {
+		name     string
+		typeflag byte
+		mode     int64
+		content  string
+		link     string
+	}{
+		// Each source sits at the top under its base name
+		{"run.sh", tar.TypeReg, 0o755, "#!/bin/sh\n", ""},
+		{"site/", tar.TypeDir, 0o755, "", ""},
+		{"site/index.html", tar.TypeReg, 0o644, "<h1>hi</h1>", ""},
+		{"site/css/", tar.TypeDir, 0o755, "", ""},
+		{"site/css/main.css", tar.TypeReg, 0o600, "body{}", ""},
+		// Links are archived as links, not followed
+		{"latest", tar.TypeSymlink, 0o777, "", "site/index.html"},
+	} {
+		hdr, ok := headers[tc.name]
+		if !ok {
+			t.Errorf("%s is missing from the archive", tc.name)
+			continue
+		}
+		if hdr.Typeflag != tc.typeflag || hdr.Mode&0o777 != tc.mode || contents[tc.name] != tc.content || hdr.Linkname != tc.link {
+			t.Errorf("%s: type %c, mode %o, content %q, link %q", tc.name, hdr.Typeflag, hdr.Mode&0o777, contents[tc.name], hdr.Linkname)
+		}
+	}
+	if len(headers) != 6 {
+		t.Errorf("archive holds %d entries, want 6", len(headers))
+	}
+	// Only file content counts towards the progress, in walk order
+	if counted.String() != "#!/bin/sh\nbody{}<h1>hi</h1>" {
+		t.Errorf("counted %q", counted.String())
+	}
+
+	if err := writeTar(io.Discard, []string{filepath.Join(t.TempDir(), "missing")}, io.Discard); err == nil {
+		t.Error("archived a missing path")
+	}
+}
+
+func TestUploadToContainer(t *testing.T) {
+	script, site, _ := uploadSources(t)
+
+	for _, tc := range []struct {
+		name        string
+		overwrite   bool
+		noOverwrite string
+	}{
+		{"keep directories", false, "true"},
+		{"overwrite", true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var query url.Values
+			var headers map[string]*tar.Header
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /v1.41/containers/{id}/archive", func(w http.ResponseWriter, r *http.Request) {
+				if r.PathValue("id") != "web" {
+					http.NotFound(w, r)
+					return
+				}
+				query = r.URL.Query()
+				headers, _ = readTar(t, r.Body)
+			})
+			c := serveDaemon(t, mux, Options{})
+
+			var written, total int64
+			err := c.UploadToContainer(context.Background(), "web", []string{script, site}, "/srv", tc.overwrite, func(w, n int64) { written, total = w, n })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query.Get("path") != "/srv" || query.Get("noOverwriteDirNonDir") != tc.noOverwrite {
+				t.Errorf("query = %v", query)
+			}
+			if _, ok := headers["site/css/main.css"]; !ok || len(headers) != 5 {
+				t.Errorf("uploaded %d entries", len(headers))
+			}
+			if total != 27 || written != total {
+				t.Errorf("progress %d of %d, want 27 of 27", written, total)
+			}
+		})
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		c := serveDaemon(t, http.NewServeMux(), Options{ReadOnly: true})
+		err := c.UploadToContainer(context.Background(), "web", []string{script}, "/srv", false, nil)
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("error = %v, want read-only", err)
+		}
+	})
+}
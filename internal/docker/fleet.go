@@ -0,0 +1,926 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/compose"
+)
+
+// hostSeparator joins a host name to the ID or name of something on it,
+// e.g. build-1/4f2a9c or build-1/shop
+const hostSeparator = "/"
+
+// DefaultFleetTimeout is how long a fleet waits for one host to answer a
+// query before reporting it down
+const DefaultFleetTimeout = 5 * time.Second
+
+// FleetMember is one daemon of a fleet
+type FleetMember struct {
+	Name    string // short name shown in the host column
+	Address string
+	Runtime Runtime
+}
+
+// HostStatus is the connection health of one host of a fleet
+type HostStatus struct {
+	Name          string
+	Address       string
+	Up            bool
+	Err           error     // why the host is down
+	Checked       time.Time // last time the host was asked, zero before
+	LastSeen      time.Time // last time the host answered
+	ServerVersion string
+	Containers    int
+	Running       int
+}
+
+// HostUsage is the summed resource usage of a host's running containers
+type HostUsage struct {
+	Name          string
+	Up            bool
+	Err           error
+	Containers    int
+	Running       int
+	CPUPercentage float64
+	MemoryUsage   uint64
+	MemoryLimit   uint64
+}
+
+type member struct {
+	FleetMember
+
+	mu     sync.Mutex
+	status HostStatus
+	// stuck counts queries that timed out and have not returned yet. The
+	// host is not asked again until they do, so a dead daemon does not
+	// pile up waiting calls.
+	stuck      int
+	stuckSince time.Time
+}
+
+// Fleet is a Runtime spanning several daemons. Containers, compose projects
+// and networks of every host are merged; their IDs and names are prefixed
+// with the host's name so every action is routed back to the daemon it
+// belongs to. A host that fails or does not answer in time is reported in
+// Health and left out of merged results instead of failing the whole call.
+type Fleet struct {
+	members []*member
+	timeout time.Duration
+}
+
+var _ Runtime = (*Fleet)(nil)
+
+// NewFleet combines runtimes into one. Host names must be unique and may not
+// contain a slash. A zero timeout means DefaultFleetTimeout.
+func NewFleet(members []FleetMember, timeout time.Duration) (*Fleet, error) {
+	if len(members) == 0 {
+		return nil, errors.New("a fleet needs at least one host")
+	}
+	if timeout == 0 {
+		timeout = DefaultFleetTimeout
+	}
+
+	f := &Fleet{timeout: timeout}
+	seen := map[string]bool{}
+	for _, fm := range members {
+		switch {
+		case fm.Name == "":
+			return nil, errors.New("every host of a fleet needs a name")
+		case strings.Contains(fm.Name, hostSeparator):
+			return nil, fmt.Errorf("host name %q may not contain %q", fm.Name, hostSeparator)
+		case seen[fm.Name]:
+			return nil, fmt.Errorf("host %q is listed twice", fm.Name)
+		}
+		seen[fm.Name] = true
+		f.members = append(f.members, &member{
+			FleetMember: fm,
+			status:      HostStatus{Name: fm.Name, Address: fm.Address},
+		})
+	}
+	return f, nil
+}
+
+// Hosts returns the names of the fleet's hosts in order
+func (f *Fleet) Hosts() []string {
+	names := make([]string, len(f.members))
+	for i, m := range f.members {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Health returns the state of every host as of its last query
+func (f *Fleet) Health() []HostStatus {
+	result := make([]HostStatus, len(f.members))
+	for i, m := range f.members {
+		m.mu.Lock()
+		result[i] = m.status
+		m.mu.Unlock()
+	}
+	return result
+}
+
+// Close closes the connection to every host
+func (f *Fleet) Close() error {
+	var errs []error
+	for _, m := range f.members {
+		if closer, ok := m.Runtime.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReadOnly reports whether mutating operations are disabled. Every host is
+// opened with the same setting.
+func (f *Fleet) ReadOnly() bool {
+	return f.members[0].Runtime.ReadOnly()
+}
+
+// query runs fn against a host, giving up after the fleet's timeout or when
+// ctx is cancelled, and records the outcome in the host's health
+func (f *Fleet) query(ctx context.Context, m *member, fn func(context.Context, Runtime) error) error {
+	now := time.Now()
+	m.mu.Lock()
+	if m.stuck > 0 {
+		err := fmt.Errorf("%s has not answered for %s", m.Name, now.Sub(m.stuckSince).Round(time.Second))
+		m.setDown(now, err)
+		m.mu.Unlock()
+		return err
+	}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	finished, abandoned := false, false
+	go func() {
+		err := fn(ctx, m.Runtime)
+		m.mu.Lock()
+		finished = true
+		if abandoned {
+			m.stuck--
+		}
+		m.mu.Unlock()
+		done <- err
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			// Nobody is waiting any more, which says nothing about the host
+			return ctx.Err()
+		}
+		m.mu.Lock()
+		if !finished {
+			abandoned = true
+			if m.stuck == 0 {
+				m.stuckSince = now
+			}
+			m.stuck++
+			err = fmt.Errorf("%s did not answer within %s", m.Name, f.timeout)
+			m.setDown(now, err)
+			m.mu.Unlock()
+			return err
+		}
+		m.mu.Unlock()
+		err = <-done
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.setDown(now, err)
+		return err
+	}
+	m.status.Up, m.status.Err = true, nil
+	m.status.Checked, m.status.LastSeen = now, now
+	return nil
+}
+
+// setDown marks the host unreachable. The caller holds m.mu.
+func (m *member) setDown(now time.Time, err error) {
+	m.status.Up, m.status.Err, m.status.Checked = false, err, now
+}
+
+// gather queries every host at once and waits for all of them, so one slow
+// host costs at most the timeout. Results of hosts that failed or timed out
+// are zero, and a late answer is dropped. It fails only when every host did.
+func gather[T any](ctx context.Context, f *Fleet, fn func(ctx context.Context, m *member, rt Runtime) (T, error)) ([]T, error) {
+	results := make([]T, len(f.members))
+	errs := make([]error, len(f.members))
+	var mu sync.Mutex
+	closed := false
+
+	var wg sync.WaitGroup
+	for i, m := range f.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = f.query(ctx, m, func(ctx context.Context, rt Runtime) error {
+				v, err := fn(ctx, m, rt)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if !closed {
+					results[i] = v
+				}
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	closed = true
+	collected := append([]T(nil), results...)
+	mu.Unlock()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed < len(f.members) {
+		return collected, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s: %w", f.members[i].Name, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// qualify prefixes an ID or name with the host it lives on
+func (m *member) qualify(id string) string {
+	return m.Name + hostSeparator + id
+}
+
+// HostOf returns the fleet host of a qualified ID, or "" for an ID of a
+// single daemon
+func HostOf(id string) string {
+	host, _, found := strings.Cut(id, hostSeparator)
+	if !found {
+		return ""
+	}
+	return host
+}
+
+// qualifyContainer makes a container's ID, project and pod unique across
+// hosts
+func (m *member) qualifyContainer(c Container) Container {
+	c.ID = m.qualify(c.ID)
+	c.Host = m.Name
+	if c.Pod != "" {
+		c.Pod = m.qualify(c.Pod)
+	}
+	if project := c.Project(); project != "" {
+		c.Labels = maps.Clone(c.Labels)
+		c.Labels[ComposeProjectLabel] = m.qualify(project)
+	}
+	return c
+}
+
+func (m *member) qualifyContainers(containers []Container) []Container {
+	result := make([]Container, len(containers))
+	for i, c := range containers {
+		result[i] = m.qualifyContainer(c)
+	}
+	return result
+}
+
+func (m *member) qualifyNetwork(n Network) Network {
+	n.ID = m.qualify(n.ID)
+	n.Name = m.qualify(n.Name)
+	n.Host = m.Name
+	n.Containers = append([]NetworkEndpoint(nil), n.Containers...)
+	for i := range n.Containers {
+		n.Containers[i].ContainerID = m.qualify(n.Containers[i].ContainerID)
+	}
+	return n
+}
+
+// host finds a member by name
+func (f *Fleet) host(name string) (*member, bool) {
+	for _, m := range f.members {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// route splits a qualified ID or name into its host and the part the host
+// understands. An unqualified container name is looked up on every host
+// and must exist on exactly one.
+func (f *Fleet) route(ctx context.Context, ref string) (*member, string, error) {
+	if name, rest, found := strings.Cut(ref, hostSeparator); found {
+		m, ok := f.host(name)
+		if !ok {
+			return nil, "", fmt.Errorf("unknown host %q in %q (hosts: %s)", name, ref, strings.Join(f.Hosts(), ", "))
+		}
+		return m, rest, nil
+	}
+	if len(f.members) == 1 {
+		return f.members[0], ref, nil
+	}
+
+	containers, err := f.ListContainers(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	var hosts []string
+	for _, c := range containers {
+		_, id, _ := strings.Cut(c.ID, hostSeparator)
+		if c.Name == ref || strings.HasPrefix(id, ref) {
+			hosts = append(hosts, c.Host)
+		}
+	}
+	switch len(hosts) {
+	case 0:
+		return nil, "", fmt.Errorf("no container %q on any reachable host", ref)
+	case 1:
+		m, _ := f.host(hosts[0])
+		return m, ref, nil
+	}
+	return nil, "", fmt.Errorf("%q exists on %s; use <host>/%s", ref, strings.Join(hosts, " and "), ref)
+}
+
+// routeName splits a qualified compose project or network name. Unlike
+// containers these are never guessed, as the same project or network
+// commonly exists on several hosts.
+func (f *Fleet) routeName(project string) (*member, string, error) {
+	name, rest, found := strings.Cut(project, hostSeparator)
+	if !found {
+		if len(f.members) == 1 {
+			return f.members[0], project, nil
+		}
+		return nil, "", fmt.Errorf("%q does not say which host it is on; use <host>/%s", project, project)
+	}
+	m, ok := f.host(name)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown host %q in %q (hosts: %s)", name, project, strings.Join(f.Hosts(), ", "))
+	}
+	return m, rest, nil
+}
+
+// routeCompose returns the host of a compose project and a copy of the
+// project under the name that host knows it by
+func (f *Fleet) routeCompose(p *compose.Project) (*member, *compose.Project, error) {
+	m, name, err := f.routeName(p.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	local := *p
+	local.Name = name
+	return m, &local, nil
+}
+
+// Ping checks every host. It fails only when no host answers, and returns
+// ErrDegraded naming the hosts that are down when some do.
+func (f *Fleet) Ping(ctx context.Context) error {
+	_, err := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) (struct{}, error) {
+		return struct{}{}, rt.Ping(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	var down []string
+	for _, h := range f.Health() {
+		if !h.Up {
+			down = append(down, h.Name)
+		}
+	}
+	if len(down) > 0 {
+		return fmt.Errorf("%w: %s down", ErrDegraded, strings.Join(down, ", "))
+	}
+	return nil
+}
+
+// HostInfo asks every host for its version. The fleet itself has no
+// address; Members describes each host.
+func (f *Fleet) HostInfo(ctx context.Context) (HostInfo, error) {
+	_, err := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) (struct{}, error) {
+		info, err := rt.HostInfo(ctx)
+		if err != nil {
+			return struct{}{}, err
+		}
+		m.mu.Lock()
+		m.status.ServerVersion = info.ServerVersion
+		m.mu.Unlock()
+		return struct{}{}, nil
+	})
+	return HostInfo{Address: "fleet of " + strings.Join(f.Hosts(), ", "), Members: f.Health()}, err
+}
+
+// Capabilities merges what the hosts that answer can do. A restriction
+// only applies when every host shares it, so a feature stays available
+// when some host has it; the others refuse it themselves.
+func (f *Fleet) Capabilities(ctx context.Context) (Capabilities, error) {
+	results, err := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) (*Capabilities, error) {
+		caps, err := rt.Capabilities(ctx)
+		return &caps, err
+	})
+	var all []Capabilities
+	for _, c := range results {
+		// Hosts that did not answer have nothing to add
+		if c != nil {
+			all = append(all, *c)
+		}
+	}
+	if len(all) == 0 {
+		return DefaultCapabilities(), err
+	}
+
+	caps := all[0]
+	for _, c := range all[1:] {
+		caps.APIVersion = same(caps.APIVersion, c.APIVersion)
+		caps.OSType = same(caps.OSType, c.OSType)
+		caps.CgroupVersion = same(caps.CgroupVersion, c.CgroupVersion)
+		caps.StorageDriver = same(caps.StorageDriver, c.StorageDriver)
+		if c.Swarm == "active" {
+			caps.Swarm = c.Swarm
+		}
+		caps.Rootless = caps.Rootless && c.Rootless
+		caps.Experimental = caps.Experimental || c.Experimental
+		caps.Podman = caps.Podman && c.Podman
+	}
+	return caps, err
+}
+
+// same is a value every host agrees on, or empty
+func same(a, b string) string {
+	if a != b {
+		return ""
+	}
+	return a
+}
+
+// ListContainers merges the containers of every host that answers
+func (f *Fleet) ListContainers(ctx context.Context) ([]Container, error) {
+	lists, err := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) ([]Container, error) {
+		containers, err := rt.ListContainers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		running := 0
+		for _, c := range containers {
+			if c.Status == "running" {
+				running++
+			}
+		}
+		m.mu.Lock()
+		m.status.Containers, m.status.Running = len(containers), running
+		m.mu.Unlock()
+		return m.qualifyContainers(containers), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Container
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	return result, nil
+}
+
+// Usage sums the CPU and memory of the running containers of every host.
+// Containers that stop while their stats are read are left out.
+func (f *Fleet) Usage(ctx context.Context) []HostUsage {
+	usage, _ := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) (HostUsage, error) {
+		containers, err := rt.ListContainers(ctx)
+		if err != nil {
+			return HostUsage{}, err
+		}
+		u := HostUsage{Name: m.Name, Up: true, Containers: len(containers)}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, c := range containers {
+			if c.Status != "running" {
+				continue
+			}
+			u.Running++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stats, err := rt.GetContainerStats(ctx, c.ID)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				u.CPUPercentage += stats.CPUPercentage
+				u.MemoryUsage += stats.MemoryUsage
+				u.MemoryLimit = max(u.MemoryLimit, stats.MemoryLimit)
+			}()
+		}
+		wg.Wait()
+		return u, nil
+	})
+
+	health := f.Health()
+	result := make([]HostUsage, len(health))
+	for i, h := range health {
+		result[i] = HostUsage{Name: h.Name, Err: h.Err}
+		if h.Up && usage != nil && usage[i].Up {
+			result[i] = usage[i]
+		}
+	}
+	return result
+}
+
+// InspectContainer returns detailed information about a container
+func (f *Fleet) InspectContainer(ctx context.Context, containerID string) (*ContainerDetails, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	details, err := m.Runtime.InspectContainer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	qualified := *details
+	qualified.ID = m.qualify(details.ID)
+	qualified.Networks = append([]ContainerNetwork(nil), details.Networks...)
+	for i := range qualified.Networks {
+		qualified.Networks[i].NetworkID = m.qualify(qualified.Networks[i].NetworkID)
+		qualified.Networks[i].Name = m.qualify(qualified.Networks[i].Name)
+	}
+	return &qualified, nil
+}
+
+// StartContainer starts a container
+func (f *Fleet) StartContainer(ctx context.Context, containerID string) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.StartContainer(ctx, id)
+}
+
+// StopContainer stops a container
+func (f *Fleet) StopContainer(ctx context.Context, containerID string) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.StopContainer(ctx, id)
+}
+
+// RestartContainer restarts a container
+func (f *Fleet) RestartContainer(ctx context.Context, containerID string) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.RestartContainer(ctx, id)
+}
+
+// RunContainer runs a container on the host opts names and returns its
+// qualified ID. A network may be given by its qualified name.
+func (f *Fleet) RunContainer(ctx context.Context, opts RunOptions) (string, error) {
+	m, ok := f.host(opts.Host)
+	switch {
+	case opts.Host == "" && len(f.members) == 1:
+		m = f.members[0]
+	case opts.Host == "":
+		return "", fmt.Errorf("the fleet has several hosts, say which to run on (hosts: %s)", strings.Join(f.Hosts(), ", "))
+	case !ok:
+		return "", fmt.Errorf("unknown host %q (hosts: %s)", opts.Host, strings.Join(f.Hosts(), ", "))
+	}
+	if host, name, found := strings.Cut(opts.Network, hostSeparator); found {
+		if host != m.Name {
+			return "", fmt.Errorf("network %s is not on host %s", opts.Network, m.Name)
+		}
+		opts.Network = name
+	}
+	opts.Host = ""
+	id, err := m.Runtime.RunContainer(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	return m.qualify(id), nil
+}
+
+// RunOptionsOf rebuilds the options of a container on the host it is on
+func (f *Fleet) RunOptionsOf(ctx context.Context, containerID string) (RunOptions, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return RunOptions{}, err
+	}
+	opts, err := m.Runtime.RunOptionsOf(ctx, id)
+	if err != nil {
+		return RunOptions{}, err
+	}
+	opts.Host = m.Name
+	return opts, nil
+}
+
+// ContainerResources returns the limits of a container on any host
+func (f *Fleet) ContainerResources(ctx context.Context, containerID string) (Resources, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return Resources{}, err
+	}
+	return m.Runtime.ContainerResources(ctx, id)
+}
+
+// UpdateResources changes the limits of a container on any host
+func (f *Fleet) UpdateResources(ctx context.Context, containerID string, r Resources) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.UpdateResources(ctx, id, r)
+}
+
+// GetContainerStats returns stats for a specific container
+func (f *Fleet) GetContainerStats(ctx context.Context, containerID string) (*ContainerStats, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return m.Runtime.GetContainerStats(ctx, id)
+}
+
+// GetContainerLogs returns the last tail lines of a container's logs
+func (f *Fleet) GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	return m.Runtime.GetContainerLogs(ctx, id, tail)
+}
+
+// StreamContainerLogs copies a container's logs to stdout and stderr
+func (f *Fleet) StreamContainerLogs(ctx context.Context, containerID string, opts LogOptions, stdout, stderr io.Writer) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.StreamContainerLogs(ctx, id, opts, stdout, stderr)
+}
+
+// ListProjectContainers returns every container of a compose project
+func (f *Fleet) ListProjectContainers(ctx context.Context, project string) ([]Container, error) {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := m.Runtime.ListProjectContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return m.qualifyContainers(containers), nil
+}
+
+// StartProject starts every container of a compose project
+func (f *Fleet) StartProject(ctx context.Context, project string) error {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.StartProject(ctx, name)
+}
+
+// StopProject stops every container of a compose project
+func (f *Fleet) StopProject(ctx context.Context, project string) error {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.StopProject(ctx, name)
+}
+
+// RestartProject restarts every container of a compose project
+func (f *Fleet) RestartProject(ctx context.Context, project string) error {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.RestartProject(ctx, name)
+}
+
+// GetProjectLogs returns the interleaved logs of a compose project
+func (f *Fleet) GetProjectLogs(ctx context.Context, project string, tail int) (string, error) {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return "", err
+	}
+	return m.Runtime.GetProjectLogs(ctx, name, tail)
+}
+
+// ServiceContainers maps each service of a project to its containers
+func (f *Fleet) ServiceContainers(ctx context.Context, project string) (map[string][]Container, error) {
+	m, name, err := f.routeName(project)
+	if err != nil {
+		return nil, err
+	}
+	services, err := m.Runtime.ServiceContainers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]Container, len(services))
+	for service, containers := range services {
+		result[service] = m.qualifyContainers(containers)
+	}
+	return result, nil
+}
+
+// ComposeUp brings a project up on the host its name is qualified with
+func (f *Fleet) ComposeUp(ctx context.Context, p *compose.Project) error {
+	m, local, err := f.routeCompose(p)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.ComposeUp(ctx, local)
+}
+
+// ComposeDown takes a project down on the host its name is qualified with
+func (f *Fleet) ComposeDown(ctx context.Context, p *compose.Project) error {
+	m, local, err := f.routeCompose(p)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.ComposeDown(ctx, local)
+}
+
+// RecreateService replaces a service's containers
+func (f *Fleet) RecreateService(ctx context.Context, p *compose.Project, service string) error {
+	m, local, err := f.routeCompose(p)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.RecreateService(ctx, local, service)
+}
+
+// DiffService compares a service's declaration with its container
+func (f *Fleet) DiffService(ctx context.Context, p *compose.Project, service string) ([]ConfigDiff, error) {
+	m, local, err := f.routeCompose(p)
+	if err != nil {
+		return nil, err
+	}
+	return m.Runtime.DiffService(ctx, local, service)
+}
+
+// StatPath describes a path inside a container
+func (f *Fleet) StatPath(ctx context.Context, containerID, containerPath string) (FileEntry, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return FileEntry{}, err
+	}
+	return m.Runtime.StatPath(ctx, id, containerPath)
+}
+
+// ListDir lists a directory inside a container
+func (f *Fleet) ListDir(ctx context.Context, containerID, dir string) ([]FileEntry, bool, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.Runtime.ListDir(ctx, id, dir)
+}
+
+// ReadFile reads up to limit bytes of a file inside a container
+func (f *Fleet) ReadFile(ctx context.Context, containerID, filePath string, limit int64) (string, error) {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	return m.Runtime.ReadFile(ctx, id, filePath, limit)
+}
+
+// SaveToHost copies a path out of a container
+func (f *Fleet) SaveToHost(ctx context.Context, containerID, srcPath, dst string, extract bool) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.SaveToHost(ctx, id, srcPath, dst, extract)
+}
+
+// UploadToContainer copies local files into a container
+func (f *Fleet) UploadToContainer(ctx context.Context, containerID string, srcPaths []string, dstDir string, overwrite bool, progress UploadProgress) error {
+	m, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.UploadToContainer(ctx, id, srcPaths, dstDir, overwrite, progress)
+}
+
+// ListNetworks merges the networks of every host that answers
+func (f *Fleet) ListNetworks(ctx context.Context) ([]Network, error) {
+	lists, err := gather(ctx, f, func(ctx context.Context, m *member, rt Runtime) ([]Network, error) {
+		networks, err := rt.ListNetworks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		qualified := make([]Network, len(networks))
+		for i, n := range networks {
+			qualified[i] = m.qualifyNetwork(n)
+		}
+		return qualified, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Network
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// InspectNetwork returns a single network by ID or name
+func (f *Fleet) InspectNetwork(ctx context.Context, networkID string) (Network, error) {
+	m, id, err := f.routeName(networkID)
+	if err != nil {
+		return Network{}, err
+	}
+	n, err := m.Runtime.InspectNetwork(ctx, id)
+	if err != nil {
+		return Network{}, err
+	}
+	return m.qualifyNetwork(n), nil
+}
+
+// CreateNetwork creates a network on the host its name is qualified with,
+// e.g. build-1/backend, and returns its qualified ID
+func (f *Fleet) CreateNetwork(ctx context.Context, opts NetworkOptions) (string, error) {
+	m, name, err := f.routeName(opts.Name)
+	if err != nil {
+		return "", fmt.Errorf("network %w", err)
+	}
+	opts.Name = name
+	id, err := m.Runtime.CreateNetwork(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	return m.qualify(id), nil
+}
+
+// RemoveNetwork removes a network
+func (f *Fleet) RemoveNetwork(ctx context.Context, networkID string) error {
+	m, id, err := f.routeName(networkID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.RemoveNetwork(ctx, id)
+}
+
+// ConnectNetwork attaches a container to a network on the same host
+func (f *Fleet) ConnectNetwork(ctx context.Context, networkID, containerID string, aliases []string) error {
+	m, network, id, err := f.routePair(ctx, networkID, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.ConnectNetwork(ctx, network, id, aliases)
+}
+
+// DisconnectNetwork detaches a container from a network
+func (f *Fleet) DisconnectNetwork(ctx context.Context, networkID, containerID string) error {
+	m, network, id, err := f.routePair(ctx, networkID, containerID)
+	if err != nil {
+		return err
+	}
+	return m.Runtime.DisconnectNetwork(ctx, network, id)
+}
+
+// routePair routes a network and a container, which must share a host
+func (f *Fleet) routePair(ctx context.Context, networkID, containerID string) (*member, string, string, error) {
+	m, network, err := f.routeName(networkID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	other, id, err := f.route(ctx, containerID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if m != other {
+		return nil, "", "", fmt.Errorf("network %s is on %s but the container is on %s", networkID, m.Name, other.Name)
+	}
+	return m, network, id, nil
+}
@@ -0,0 +1,293 @@
+package docker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/docker/fake"
+)
+
+// hanging is a host whose container list ignores cancellation and never
+// answers until released
+type hanging struct {
+	*fake.Runtime
+	release chan struct{}
+}
+
+func (h hanging) ListContainers(ctx context.Context) ([]docker.Container, error) {
+	<-h.release
+	return h.Runtime.ListContainers(ctx)
+}
+
+func newHost(names ...string) *fake.Runtime {
+	r := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	for _, name := range names {
+		r.AddContainer(fake.ContainerSpec{Name: name, CPU: 10, Memory: 64 << 20})
+	}
+	return r
+}
+
+func newTestFleet(t *testing.T, timeout time.Duration, runtimes ...docker.Runtime) *docker.Fleet {
+	t.Helper()
+	var members []docker.FleetMember
+	for i, rt := range runtimes {
+		members = append(members, docker.FleetMember{Name: "build-" + string(rune('1'+i)), Runtime: rt})
+	}
+	f, err := docker.NewFleet(members, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func statusOf(t *testing.T, r *fake.Runtime, name string) string {
+	t.Helper()
+	containers, _ := r.ListContainers(context.Background())
+	for _, c := range containers {
+		if c.Name == name {
+			return c.Status
+		}
+	}
+	t.Fatalf("no container %s", name)
+	return ""
+}
+
+func TestFleetRouting(t *testing.T) {
+	one, two := newHost("web", "cache"), newHost("web", "worker")
+	f := newTestFleet(t, 0, one, two)
+
+	containers, err := f.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 4 {
+		t.Fatalf("got %d containers, want 4", len(containers))
+	}
+	for _, c := range containers {
+		if !strings.HasPrefix(c.ID, c.Host+"/") {
+			t.Fatalf("container %s on %s has ID %s", c.Name, c.Host, c.ID)
+		}
+	}
+
+	// A qualified ID reaches only its own host
+	for _, c := range containers {
+		if c.Host == "build-2" && c.Name == "web" {
+			if err := f.StopContainer(context.Background(), c.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if statusOf(t, one, "web") != fake.StatusRunning || statusOf(t, two, "web") != fake.StatusExited {
+		t.Fatal("stopping build-2/web touched the wrong host")
+	}
+
+	// Names are looked up, but must be unique across the fleet
+	if err := f.StopContainer(context.Background(), "worker"); err != nil {
+		t.Fatalf("stop worker: %v", err)
+	}
+	if statusOf(t, two, "worker") != fake.StatusExited {
+		t.Fatal("worker was not stopped")
+	}
+	if err := f.StartContainer(context.Background(), "web"); err == nil || !strings.Contains(err.Error(), "build-1 and build-2") {
+		t.Fatalf("start web error = %v, want it to be ambiguous", err)
+	}
+	if err := f.StartContainer(context.Background(), "build-2/web"); err != nil {
+		t.Fatalf("start build-2/web: %v", err)
+	}
+	if err := f.StartContainer(context.Background(), "build-9/web"); err == nil || !strings.Contains(err.Error(), "unknown host") {
+		t.Fatalf("start build-9/web error = %v, want an unknown host", err)
+	}
+}
+
+func TestFleetProjects(t *testing.T) {
+	one, two := newHost(), newHost()
+	for _, r := range []*fake.Runtime{one, two} {
+		r.AddContainer(fake.ContainerSpec{Name: "shop-web-1", Labels: map[string]string{
+			docker.ComposeProjectLabel: "shop",
+			docker.ComposeServiceLabel: "web",
+		}})
+	}
+	f := newTestFleet(t, 0, one, two)
+
+	containers, err := f.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects := map[string]bool{}
+	for _, c := range containers {
+		projects[c.Project()] = true
+	}
+	if !projects["build-1/shop"] || !projects["build-2/shop"] {
+		t.Fatalf("projects = %v, want shop once per host", projects)
+	}
+
+	if err := f.StopProject(context.Background(), "build-1/shop"); err != nil {
+		t.Fatal(err)
+	}
+	if statusOf(t, one, "shop-web-1") != fake.StatusExited || statusOf(t, two, "shop-web-1") != fake.StatusRunning {
+		t.Fatal("stopping build-1/shop touched the wrong host")
+	}
+	if err := f.StopProject(context.Background(), "shop"); err == nil {
+		t.Fatal("an unqualified project was guessed")
+	}
+
+	// The daemon's own labels are left alone
+	own, _ := one.ListContainers(context.Background())
+	if own[0].Project() != "shop" {
+		t.Fatalf("host label changed to %q", own[0].Project())
+	}
+}
+
+func TestFleetDeadHost(t *testing.T) {
+	one, two := newHost("web"), newHost("api")
+	two.FailOn("ListContainers", errors.New("connection refused"))
+	f := newTestFleet(t, 0, one, two)
+
+	containers, err := f.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("one dead host failed the list: %v", err)
+	}
+	if len(containers) != 1 || containers[0].Host != "build-1" {
+		t.Fatalf("containers = %+v, want build-1's only", containers)
+	}
+	health := f.Health()
+	if !health[0].Up || health[0].Running != 1 {
+		t.Fatalf("build-1 = %+v, want up with one running", health[0])
+	}
+	if health[1].Up || health[1].Err == nil || !strings.Contains(health[1].Err.Error(), "connection refused") {
+		t.Fatalf("build-2 = %+v, want down with the error", health[1])
+	}
+
+	usage := f.Usage(context.Background())
+	if !usage[0].Up || usage[0].Running != 1 || usage[0].MemoryUsage == 0 {
+		t.Fatalf("build-1 usage = %+v", usage[0])
+	}
+	if usage[1].Up {
+		t.Fatalf("build-2 usage = %+v, want down", usage[1])
+	}
+
+	// The list only fails when no host answers
+	one.FailOn("ListContainers", errors.New("connection refused"))
+	if _, err := f.ListContainers(context.Background()); err == nil {
+		t.Fatal("a fleet without any host answering listed containers")
+	}
+
+	// A host that comes back is up again
+	two.FailOn("ListContainers", nil)
+	if _, err := f.ListContainers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Health()[1].Up {
+		t.Fatal("build-2 did not recover")
+	}
+
+	// A ping with some hosts down is degraded, with none answering it fails
+	two.FailOn("Ping", errors.New("connection refused"))
+	if err := f.Ping(context.Background()); !errors.Is(err, docker.ErrDegraded) || !strings.Contains(err.Error(), "build-2") {
+		t.Fatalf("ping = %v, want degraded by build-2", err)
+	}
+	one.FailOn("Ping", errors.New("connection refused"))
+	if err := f.Ping(context.Background()); err == nil || errors.Is(err, docker.ErrDegraded) {
+		t.Fatalf("ping = %v, want it failed", err)
+	}
+}
+
+func TestFleetHangingHost(t *testing.T) {
+	stuck := hanging{Runtime: newHost("api"), release: make(chan struct{})}
+	f := newTestFleet(t, 50*time.Millisecond, newHost("web"), stuck)
+
+	start := time.Now()
+	containers, err := f.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("containers = %+v, want build-1's only", containers)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("list took %s with a hanging host", elapsed)
+	}
+
+	// While the first call hangs, the host is not asked again
+	start = time.Now()
+	if _, err := f.ListContainers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Fatalf("second list waited %s for the hanging host", elapsed)
+	}
+	if h := f.Health()[1]; h.Up || !strings.Contains(h.Err.Error(), "has not answered") {
+		t.Fatalf("build-2 = %+v, want it reported as not answering", h)
+	}
+
+	close(stuck.release)
+	deadline := time.Now().Add(time.Second)
+	for !f.Health()[1].Up {
+		if time.Now().After(deadline) {
+			t.Fatal("build-2 did not recover after answering")
+		}
+		f.ListContainers(context.Background())
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFleetNames(t *testing.T) {
+	r := newHost()
+	for _, members := range [][]docker.FleetMember{
+		nil,
+		{{Name: "", Runtime: r}},
+		{{Name: "a/b", Runtime: r}},
+		{{Name: "a", Runtime: r}, {Name: "a", Runtime: r}},
+	} {
+		if _, err := docker.NewFleet(members, 0); err == nil {
+			t.Errorf("NewFleet(%+v) accepted invalid hosts", members)
+		}
+	}
+}
+
+func TestFleetCapabilities(t *testing.T) {
+	one, two, dead := newHost("web"), newHost(), newHost()
+	one.SetCapabilities(docker.Capabilities{OSType: "linux", CgroupVersion: "1", Rootless: true, Swarm: "inactive"})
+	two.SetCapabilities(docker.Capabilities{OSType: "linux", CgroupVersion: "2", Swarm: "active"})
+	dead.FailOn("Capabilities", errors.New("connection refused"))
+	f := newTestFleet(t, 0, one, two, dead)
+
+	caps, err := f.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A feature one host has stays available, the others refuse it themselves
+	if caps.OSType != "linux" || caps.CgroupVersion != "" || caps.Rootless {
+		t.Fatalf("capabilities = %+v", caps)
+	}
+	if caps.Supports(docker.FeatureStats) != nil || caps.Supports(docker.FeatureOverlayNetworks) != nil {
+		t.Fatalf("capabilities = %+v, want stats and overlay networks available", caps)
+	}
+	if _, err := one.GetContainerStats(context.Background(), "web"); !errors.Is(err, docker.ErrUnsupported) {
+		t.Fatalf("stats on a rootless cgroup v1 host = %v, want unsupported", err)
+	}
+}
+
+func TestFleetRun(t *testing.T) {
+	one, two := newHost(), newHost()
+	f := newTestFleet(t, 0, one, two)
+	ctx := context.Background()
+
+	if _, err := f.RunContainer(ctx, docker.RunOptions{Image: "nginx", Name: "web"}); err == nil {
+		t.Fatal("a fleet with several hosts ran a container without being told where")
+	}
+	id, err := f.RunContainer(ctx, docker.RunOptions{Host: "build-2", Image: "nginx", Name: "web", Network: "build-2/bridge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id, "build-2/") || statusOf(t, two, "web") != fake.StatusRunning {
+		t.Fatalf("ran %s, want web running on build-2", id)
+	}
+	if _, err := f.RunContainer(ctx, docker.RunOptions{Host: "build-1", Image: "nginx", Network: "build-2/bridge"}); err == nil {
+		t.Fatal("a container was attached to a network on another host")
+	}
+}
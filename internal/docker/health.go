@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConnState is how the daemon answered its latest health check
+type ConnState int
+
+const (
+	// Connected is a daemon that answers promptly
+	Connected ConnState = iota
+	// Degraded is a daemon that answers slowly, or a fleet with hosts down
+	Degraded
+	// Disconnected is a daemon that does not answer
+	Disconnected
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case Connected:
+		return "connected"
+	case Degraded:
+		return "degraded"
+	case Disconnected:
+		return "disconnected"
+	}
+	return fmt.Sprintf("state %d", int(s))
+}
+
+// ErrDegraded is returned by a ping that got an answer, but not a full one
+var ErrDegraded = errors.New("degraded")
+
+const (
+	// SlowPing is the latency above which a daemon counts as degraded
+	SlowPing = 2 * time.Second
+
+	reconnectMin = time.Second
+	reconnectMax = 30 * time.Second
+)
+
+// Health follows the connection to the daemon from periodic pings. The
+// zero value is a connected daemon that was not checked yet.
+type Health struct {
+	State    ConnState
+	Err      error         // why the daemon is degraded or disconnected
+	Latency  time.Duration // of the last answered ping
+	Failures int           // pings failed in a row
+	Since    time.Time     // when State last changed
+}
+
+// Record updates the health with a ping that took latency and failed with
+// err, or nil. It reports whether the daemon came back after it was
+// disconnected.
+func (h *Health) Record(now time.Time, latency time.Duration, err error) (recovered bool) {
+	prev := h.State
+	switch {
+	case err == nil && latency > SlowPing:
+		h.State, h.Err = Degraded, fmt.Errorf("the daemon took %s to answer", latency.Round(100*time.Millisecond))
+		h.Latency, h.Failures = latency, 0
+	case err == nil:
+		h.State, h.Err = Connected, nil
+		h.Latency, h.Failures = latency, 0
+	case errors.Is(err, ErrDegraded):
+		h.State, h.Err = Degraded, err
+		h.Latency, h.Failures = latency, 0
+	default:
+		h.State, h.Err = Disconnected, err
+		h.Failures++
+	}
+	if h.State != prev || h.Since.IsZero() {
+		h.Since = now
+	}
+	return prev == Disconnected && h.State != Disconnected
+}
+
+// Backoff is how long to wait before pinging a disconnected daemon again:
+// a second after the first failure, doubling up to half a minute
+func (h Health) Backoff() time.Duration {
+	if h.Failures == 0 {
+		return 0
+	}
+	d := reconnectMin
+	for i := 1; i < h.Failures && d < reconnectMax; i++ {
+		d *= 2
+	}
+	return min(d, reconnectMax)
+}
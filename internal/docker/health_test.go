@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var h Health
+
+	if h.Record(now, 10*time.Millisecond, nil) || h.State != Connected {
+		t.Fatalf("health = %+v, want connected", h)
+	}
+	h.Record(now, 3*time.Second, nil)
+	if h.State != Degraded || h.Err == nil {
+		t.Fatalf("health = %+v, want degraded by a slow answer", h)
+	}
+
+	// Failures back off exponentially up to the maximum
+	down := errors.New("Cannot connect to the Docker daemon")
+	var backoffs []time.Duration
+	for i := 0; i < 7; i++ {
+		h.Record(now.Add(time.Duration(i)*time.Second), 0, down)
+		backoffs = append(backoffs, h.Backoff())
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	if fmt.Sprint(backoffs) != fmt.Sprint(want) {
+		t.Fatalf("backoffs = %v, want %v", backoffs, want)
+	}
+	if h.State != Disconnected || !h.Since.Equal(now) {
+		t.Fatalf("health = %+v, want disconnected since the first failure", h)
+	}
+
+	// A partial answer is a recovery, but not a full one
+	if !h.Record(now.Add(time.Minute), 0, fmt.Errorf("%w: edge is down", ErrDegraded)) {
+		t.Fatal("a partial answer after a disconnect was not reported as recovered")
+	}
+	if h.State != Degraded || h.Backoff() != 0 {
+		t.Fatalf("health = %+v, want degraded without backoff", h)
+	}
+	if h.Record(now.Add(2*time.Minute), time.Millisecond, nil) || h.State != Connected {
+		t.Fatalf("health = %+v, want connected and no recovery from degraded", h)
+	}
+}
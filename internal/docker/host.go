@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"context"
+)
+
+// HostInfo describes the daemon a runtime is connected to
+type HostInfo struct {
+	Address       string
+	Context       string
+	Product       string // "Podman" when the daemon is not Docker
+	ServerVersion string
+	APIVersion    string
+	// ClientAPIVersion is the API version requests use, negotiated down to
+	// the daemon's when it is older
+	ClientAPIVersion string
+	OS               string
+	Arch             string
+	KernelVersion    string
+
+	// TLS lists the certificate files in use, nil without TLS
+	TLS *TLSFiles
+	// Certificates are the CA, client and daemon certificates
+	Certificates []CertInfo
+	// CertErrors are certificates that could not be read
+	CertErrors []string
+
+	// Members describes each host of a fleet, empty for one daemon
+	Members []HostStatus
+}
+
+// HostInfo returns the daemon version and, for TLS connections, the
+// certificates with their expiry
+func (c *Client) HostInfo(ctx context.Context) (_ HostInfo, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+
+	info := HostInfo{
+		Address: c.host,
+		Context: c.context,
+		TLS:     c.tls,
+	}
+
+	if c.tls != nil {
+		certs, err := c.tls.Certificates()
+		if err != nil {
+			info.CertErrors = append(info.CertErrors, err.Error())
+		}
+		info.Certificates = certs
+
+		if cfg, err := c.tls.config(); err == nil {
+			daemon, err := daemonCertificate(ctx, c.host, cfg)
+			if err != nil {
+				info.CertErrors = append(info.CertErrors, err.Error())
+			} else {
+				info.Certificates = append(info.Certificates, daemon)
+			}
+		}
+	}
+
+	version, err := c.api(ctx).ServerVersion(ctx)
+	if err != nil {
+		// Certificates are worth showing even when the daemon is unreachable
+		return info, explainTLS(c.host, err)
+	}
+	info.ServerVersion = version.Version
+	info.APIVersion = version.APIVersion
+	info.OS = version.Os
+	info.Arch = version.Arch
+	info.KernelVersion = version.KernelVersion
+	info.ClientAPIVersion = c.api(ctx).ClientVersion()
+	for _, component := range version.Components {
+		if component.Name == "Podman Engine" {
+			info.Product = "Podman"
+		}
+	}
+	if info.Product == "" && c.Podman() {
+		info.Product = "Podman"
+	}
+	return info, nil
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// readLogs fetches a container's logs and strips the stream multiplexing
+// headers the daemon adds for containers without a TTY
+func (c *Client) readLogs(ctx context.Context, containerID string, opts types.ContainerLogsOptions) (string, error) {
+	info, err := c.api(ctx).ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := c.api(ctx).ContainerLogs(ctx, containerID, opts)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	if info.Config != nil && info.Config.Tty {
+		logs, err := io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+		return string(logs), nil
+	}
+
+	// Interleave stdout and stderr in arrival order
+	var buf bytes.Buffer
+	if _, err := stdcopy.StdCopy(&buf, &buf, reader); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// tailOption converts a line count to the API's Tail option
+func tailOption(tail int) string {
+	if tail <= 0 {
+		return "all"
+	}
+	return strconv.Itoa(tail)
+}
+
+// LogOptions controls which logs StreamContainerLogs returns
+type LogOptions struct {
+	Follow     bool
+	Tail       string // number of lines from the end, or "all"
+	Timestamps bool
+}
+
+// StreamContainerLogs copies a container's logs to stdout and stderr,
+// blocking until the stream ends or ctx is cancelled. No timeout applies,
+// a followed stream has no end.
+func (c *Client) StreamContainerLogs(ctx context.Context, containerID string, opts LogOptions, stdout, stderr io.Writer) error {
+	info, err := c.api(ctx).ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	reader, err := c.api(ctx).ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
+		Timestamps: opts.Timestamps,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if info.Config != nil && info.Config.Tty {
+		_, err = io.Copy(stdout, reader)
+		return err
+	}
+	_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	return err
+}
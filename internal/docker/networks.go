@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+)
+
+// Network represents a Docker network and the containers attached to it
+type Network struct {
+	ID         string
+	Name       string
+	Driver     string
+	Scope      string
+	Subnet     string
+	Gateway    string
+	Internal   bool
+	Containers []NetworkEndpoint
+	Host       string // fleet host the network is on, empty for one daemon
+}
+
+// NetworkEndpoint is a container's attachment to a network
+type NetworkEndpoint struct {
+	ContainerID string
+	Name        string
+	IPv4Address string
+	IPv6Address string
+	MacAddress  string
+}
+
+// ContainerNetwork is a network membership seen from the container side
+type ContainerNetwork struct {
+	NetworkID  string
+	Name       string
+	IPAddress  string
+	Gateway    string
+	MacAddress string
+	Aliases    []string
+}
+
+// NetworkOptions holds the settings for a new network
+type NetworkOptions struct {
+	Name     string
+	Driver   string
+	Subnet   string
+	Gateway  string
+	Internal bool
+}
+
+// LocalName is the name of the network on its own host, without the fleet
+// host in front
+func (n Network) LocalName() string {
+	if n.Host == "" {
+		return n.Name
+	}
+	return strings.TrimPrefix(n.Name, n.Host+hostSeparator)
+}
+
+// IsUserDefined reports whether the network was created by a user rather
+// than being one of the daemon's predefined networks
+func (n Network) IsUserDefined() bool {
+	switch n.LocalName() {
+	case "bridge", "host", "none", "podman":
+		return false
+	}
+	return true
+}
+
+// ListNetworks returns all networks with their attached containers
+func (c *Client) ListNetworks(ctx context.Context) (_ []Network, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+
+	networks, err := c.api(ctx).NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The list endpoint leaves Containers empty, so inspect each network
+	result := make([]Network, 0, len(networks))
+	for _, n := range networks {
+		inspected, err := c.api(ctx).NetworkInspect(ctx, n.ID, types.NetworkInspectOptions{})
+		if err != nil {
+			// The network may have been removed in the meantime
+			continue
+		}
+		result = append(result, toNetwork(inspected))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// InspectNetwork returns a single network by ID or name
+func (c *Client) InspectNetwork(ctx context.Context, networkID string) (_ Network, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+	n, err := c.api(ctx).NetworkInspect(ctx, networkID, types.NetworkInspectOptions{})
+	if err != nil {
+		return Network{}, err
+	}
+	return toNetwork(n), nil
+}
+
+// CreateNetwork creates a network and returns its ID
+func (c *Client) CreateNetwork(ctx context.Context, opts NetworkOptions) (_ string, err error) {
+	if err := c.checkWritable("create network"); err != nil {
+		return "", err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+
+	create := types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         opts.Driver,
+		Internal:       opts.Internal,
+	}
+	if opts.Subnet != "" || opts.Gateway != "" {
+		create.IPAM = &network.IPAM{
+			Config: []network.IPAMConfig{{
+				Subnet:  opts.Subnet,
+				Gateway: opts.Gateway,
+			}},
+		}
+	}
+
+	resp, err := c.api(ctx).NetworkCreate(ctx, opts.Name, create)
+	if err != nil {
+		return "", c.unsupported("creating networks", err)
+	}
+	return resp.ID, nil
+}
+
+// RemoveNetwork removes a network
+func (c *Client) RemoveNetwork(ctx context.Context, networkID string) (err error) {
+	if err := c.checkWritable("remove network"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	return c.api(ctx).NetworkRemove(ctx, networkID)
+}
+
+// ConnectNetwork attaches a container to a network with optional aliases
+func (c *Client) ConnectNetwork(ctx context.Context, networkID, containerID string, aliases []string) (err error) {
+	if err := c.checkWritable("connect network"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	err = c.api(ctx).NetworkConnect(ctx, networkID, containerID, &network.EndpointSettings{
+		Aliases: aliases,
+	})
+	return c.unsupported("connecting networks", err)
+}
+
+// DisconnectNetwork detaches a container from a network
+func (c *Client) DisconnectNetwork(ctx context.Context, networkID, containerID string) (err error) {
+	if err := c.checkWritable("disconnect network"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+	err = c.api(ctx).NetworkDisconnect(ctx, networkID, containerID, false)
+	return c.unsupported("disconnecting networks", err)
+}
+
+func toNetwork(n types.NetworkResource) Network {
+	result := Network{
+		ID:       n.ID,
+		Name:     n.Name,
+		Driver:   n.Driver,
+		Scope:    n.Scope,
+		Internal: n.Internal,
+	}
+	if len(n.IPAM.Config) > 0 {
+		result.Subnet = n.IPAM.Config[0].Subnet
+		result.Gateway = n.IPAM.Config[0].Gateway
+	}
+
+	for id, ep := range n.Containers {
+		result.Containers = append(result.Containers, NetworkEndpoint{
+			ContainerID: id,
+			Name:        ep.Name,
+			IPv4Address: ep.IPv4Address,
+			IPv6Address: ep.IPv6Address,
+			MacAddress:  ep.MacAddress,
+		})
+	}
+	sort.Slice(result.Containers, func(i, j int) bool {
+		return result.Containers[i].Name < result.Containers[j].Name
+	})
+
+	return result
+}
+
+func toContainerNetworks(settings map[string]*network.EndpointSettings) []ContainerNetwork {
+	result := make([]ContainerNetwork, 0, len(settings))
+	for name, ep := range settings {
+		if ep == nil {
+			continue
+		}
+		result = append(result, ContainerNetwork{
+			NetworkID:  ep.NetworkID,
+			Name:       name,
+			IPAddress:  ep.IPAddress,
+			Gateway:    ep.Gateway,
+			MacAddress: ep.MacAddress,
+			Aliases:    ep.Aliases,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// networkCall is a connect or disconnect request the daemon received
+type networkCall struct {
+	Action         string
+	Network        string
+	Container      string
+	Force          bool
+	EndpointConfig struct{ Aliases []string }
+}
+
+// networkDaemon records network membership calls. Unknown networks are
+// refused, like the daemon does.
+func networkDaemon(t *testing.T, opts Options) (*Client, func() []networkCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []networkCall
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1.41/networks/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"network missing not found"}`))
+			return
+		}
+		call := networkCall{Action: r.PathValue("action"), Network: r.PathValue("id")}
+		json.NewDecoder(r.Body).Decode(&call)
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+	})
+	c := serveDaemon(t, mux, opts)
+	return c, func() []networkCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(calls)
+	}
+}
+
+func TestNetworkMembership(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name    string
+		do      func(c *Client) error
+		want    networkCall
+		wantErr string
+	}{
+		{
+			name: "connect with aliases",
+			do:   func(c *Client) error { return c.ConnectNetwork(ctx, "back", "web", []string{"api", "api.internal"}) },
+			want: networkCall{Action: "connect", Network: "back", Container: "web", EndpointConfig: struct{ Aliases []string }{[]string{"api", "api.internal"}}},
+		},
+		{
+			name: "connect without aliases",
+			do:   func(c *Client) error { return c.ConnectNetwork(ctx, "back", "web", nil) },
+			want: networkCall{Action: "connect", Network: "back", Container: "web"},
+		},
+		{
+			// A disconnect is never forced, so a running container is not torn off
+			name: "disconnect",
+			do:   func(c *Client) error { return c.DisconnectNetwork(ctx, "back", "web") },
+			want: networkCall{Action: "disconnect", Network: "back", Container: "web"},
+		},
+		{
+			name:    "unknown network",
+			do:      func(c *Client) error { return c.ConnectNetwork(ctx, "missing", "web", nil) },
+			wantErr: "network missing not found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, calls := networkDaemon(t, Options{})
+			err := tc.do(c)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := calls()
+			if len(got) != 1 || got[0].Action != tc.want.Action || got[0].Network != tc.want.Network ||
+				got[0].Container != tc.want.Container || got[0].Force ||
+				!slices.Equal(got[0].EndpointConfig.Aliases, tc.want.EndpointConfig.Aliases) {
+				t.Errorf("calls = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNetworkMembershipReadOnly(t *testing.T) {
+	ctx := context.Background()
+	c, calls := networkDaemon(t, Options{ReadOnly: true})
+	if err := c.ConnectNetwork(ctx, "back", "web", nil); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("connect: %v, want read-only", err)
+	}
+	if err := c.DisconnectNetwork(ctx, "back", "web"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("disconnect: %v, want read-only", err)
+	}
+	if got := calls(); len(got) > 0 {
+		t.Errorf("the daemon was called: %+v", got)
+	}
+}
+
+func TestNetworkNames(t *testing.T) {
+	for _, tc := range []struct {
+		network     Network
+		local       string
+		userDefined bool
+	}{
+		{Network{Name: "bridge"}, "bridge", false},
+		{Network{Name: "back"}, "back", true},
+		// Fleet networks carry their host in front
+		{Network{Name: "prod/none", Host: "prod"}, "none", false},
+		{Network{Name: "prod/back", Host: "prod"}, "back", true},
+	} {
+		if got := tc.network.LocalName(); got != tc.local {
+			t.Errorf("%s: local name %q, want %q", tc.network.Name, got, tc.local)
+		}
+		if got := tc.network.IsUserDefined(); got != tc.userDefined {
+			t.Errorf("%s: user defined %v, want %v", tc.network.Name, got, tc.userDefined)
+		}
+	}
+
+	for id, host := range map[string]string{"prod/abc123": "prod", "abc123": ""} {
+		if got := HostOf(id); got != host {
+			t.Errorf("HostOf(%q) = %q, want %q", id, got, host)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// Podman serves a Docker compatible API next to its own libpod API. The
+// compatible API covers what containix needs, with a few differences
+// handled here: the socket lives elsewhere, some endpoints are missing and
+// pods are only reported by the libpod API.
+
+// ErrUnsupported is returned for operations the connected daemon cannot do
+var ErrUnsupported = errors.New("not supported by the daemon")
+
+// dockerHost is the local Docker daemon, a variable so tests can point it
+// at a socket that does not exist
+var dockerHost = client.DefaultDockerHost
+
+// podmanSockets are where rootless and rootful Podman listen
+func podmanSockets() []string {
+	var paths []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "podman", "podman.sock"))
+	}
+	return append(paths, "/run/podman/podman.sock")
+}
+
+// localHost is the address of the local daemon: Docker's socket, or a
+// Podman socket when only that exists
+func localHost() string {
+	path, ok := strings.CutPrefix(dockerHost, "unix://")
+	if !ok {
+		return dockerHost
+	}
+	if _, err := os.Stat(path); err == nil {
+		return dockerHost
+	}
+	for _, socket := range podmanSockets() {
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+	return dockerHost
+}
+
+// api returns the Docker API client. The first call that reaches the
+// daemon negotiates the API version and finds out whether it is Podman;
+// until then requests use the newest version this client speaks. Calls
+// made while that ping is in flight wait for it, or for their ctx.
+func (c *Client) api(ctx context.Context) *client.Client {
+	c.mu.Lock()
+	if c.detected {
+		c.mu.Unlock()
+		return c.client
+	}
+	wait := c.detecting
+	if wait == nil {
+		wait = make(chan struct{})
+		c.detecting = wait
+		c.mu.Unlock()
+		c.detect(ctx, wait)
+		return c.client
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-wait:
+	case <-ctx.Done():
+	}
+	return c.client
+}
+
+// detect pings the daemon without holding c.mu, then closes done
+func (c *Client) detect(ctx context.Context, done chan struct{}) {
+	resp, err := c.rawGet(ctx, "/_ping")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(done)
+	c.detecting = nil
+	if err != nil {
+		// Tried again on the next call, the daemon may come up later
+		return
+	}
+	resp.Body.Close()
+
+	// Without DOCKER_API_VERSION, older daemons get their own version
+	c.client.NegotiateAPIVersionPing(types.Ping{APIVersion: resp.Header.Get("Api-Version")})
+	c.libpod = resp.Header.Get("Libpod-Api-Version")
+	c.detected = true
+}
+
+// Podman reports whether the daemon is Podman's Docker compatible service.
+// It is false until a call has reached the daemon.
+func (c *Client) Podman() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.libpod != ""
+}
+
+// rawGet sends a request outside the Docker API client, over the same
+// connection, for endpoints the client does not know
+func (c *Client) rawGet(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	u, err := client.ParseHostURL(c.client.DaemonHost())
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.Scheme, req.URL.Host = "http", u.Host
+	if c.tls != nil {
+		req.URL.Scheme = "https"
+	}
+	if u.Scheme == "unix" || u.Scheme == "npipe" {
+		// Local sockets ignore the host, it only has to be valid
+		req.URL.Host, req.Host = "docker", "docker"
+	}
+	return c.client.HTTPClient().Do(req)
+}
+
+// pods maps container IDs to the name of their Podman pod. Only the libpod
+// API knows about pods; when it cannot be read no container has one.
+func (c *Client) pods(ctx context.Context) map[string]string {
+	c.mu.Lock()
+	version := c.libpod
+	c.mu.Unlock()
+	if version == "" {
+		return nil
+	}
+
+	resp, err := c.rawGet(ctx, "/v"+version+"/libpod/containers/json?all=true")
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var containers []struct {
+		ID      string `json:"Id"`
+		PodName string `json:"PodName"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil
+	}
+	pods := map[string]string{}
+	for _, ctr := range containers {
+		if ctr.PodName != "" {
+			pods[ctr.ID] = ctr.PodName
+		}
+	}
+	return pods
+}
+
+// unsupported turns the "not implemented" and "no such endpoint" answers
+// of a daemon that lacks a feature into ErrUnsupported, so views can say
+// so instead of showing an HTTP error
+func (c *Client) unsupported(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	engine := "the daemon"
+	if c.Podman() {
+		engine = "Podman"
+	}
+	if errdefs.IsNotImplemented(err) || strings.Contains(err.Error(), "page not found") {
+		return fmt.Errorf("%s is not supported by %s: %w", op, engine, ErrUnsupported)
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startPodman serves the parts of Podman's API containix uses on a unix
+// socket. It speaks API 1.40, so requests only succeed after negotiation.
+func startPodman(t *testing.T, socket string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json := func(w http.ResponseWriter, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.40")
+		w.Header().Set("Libpod-Api-Version", "4.9.3")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.40/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		json(w, `[{"Id":"aaa","Names":["/web"],"State":"running"},
+			{"Id":"bbb","Names":["/shop-infra"],"State":"running"},
+			{"Id":"ccc","Names":["/shop-api"],"State":"running"}]`)
+	})
+	mux.HandleFunc("/v4.9.3/libpod/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		json(w, `[{"Id":"aaa","PodName":""},
+			{"Id":"bbb","Pod":"p1","PodName":"shop","IsInfra":true},
+			{"Id":"ccc","Pod":"p1","PodName":"shop"}]`)
+	})
+	// cgroup v2: no per-CPU usage and inactive_file instead of cache
+	mux.HandleFunc("/v1.40/containers/aaa/stats", func(w http.ResponseWriter, r *http.Request) {
+		json(w, `{"cpu_stats":{"cpu_usage":{"total_usage":3000},"system_cpu_usage":20000,"online_cpus":4},
+			"precpu_stats":{"cpu_usage":{"total_usage":1000},"system_cpu_usage":10000},
+			"memory_stats":{"usage":1000,"limit":4000,"stats":{"inactive_file":200}}}`)
+	})
+	mux.HandleFunc("/v1.40/networks/create", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte(`{"message":"macvlan is not implemented"}`))
+	})
+	mux.HandleFunc("/v1.40/info", func(w http.ResponseWriter, r *http.Request) {
+		json(w, `{"OSType":"linux","CgroupVersion":"2","Driver":"overlay",
+			"Swarm":{"LocalNodeState":"inactive"},"SecurityOptions":["name=seccomp","name=rootless"]}`)
+	})
+	mux.HandleFunc("/v1.40/version", func(w http.ResponseWriter, r *http.Request) {
+		json(w, `{"Version":"4.9.3","ApiVersion":"1.41","Os":"linux","Arch":"amd64",
+			"Components":[{"Name":"Podman Engine","Version":"4.9.3"}]}`)
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+}
+
+func TestPodmanClient(t *testing.T) {
+	dir, err := os.MkdirTemp("", "podman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	startPodman(t, filepath.Join(dir, "podman", "podman.sock"))
+
+	// Without Docker's socket the rootless Podman socket is used
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_API_VERSION", "")
+	t.Setenv("HOME", dir)
+	old := dockerHost
+	dockerHost = "unix://" + filepath.Join(dir, "docker.sock")
+	t.Cleanup(func() { dockerHost = old })
+
+	c, err := NewClient(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if want := "unix://" + filepath.Join(dir, "podman", "podman.sock"); c.host != want {
+		t.Fatalf("host = %s, want %s", c.host, want)
+	}
+
+	containers, err := c.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Podman() {
+		t.Fatal("Podman was not detected")
+	}
+	pods := map[string]string{}
+	for _, ctr := range containers {
+		pods[ctr.Name] = ctr.Pod
+	}
+	if pods["web"] != "" || pods["shop-infra"] != "shop" || pods["shop-api"] != "shop" {
+		t.Fatalf("pods = %v", pods)
+	}
+
+	stats, err := c.GetContainerStats(context.Background(), "aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(stats.CPUPercentage-80) > 0.001 {
+		t.Fatalf("CPU = %.2f%%, want 80%% from online_cpus", stats.CPUPercentage)
+	}
+	if stats.MemoryUsage != 800 {
+		t.Fatalf("memory = %d, want 800 without inactive_file", stats.MemoryUsage)
+	}
+
+	// Endpoints Podman lacks are reported as unsupported
+	_, err = c.CreateNetwork(context.Background(), NetworkOptions{Name: "lan", Driver: "macvlan"})
+	if !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), "Podman") {
+		t.Fatalf("create network error = %v, want it unsupported by Podman", err)
+	}
+
+	info, err := c.HostInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Product != "Podman" || info.ClientAPIVersion != "1.40" {
+		t.Fatalf("info = %+v, want Podman on API 1.40", info)
+	}
+
+	caps, err := c.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Capabilities{APIVersion: "1.40", OSType: "linux", CgroupVersion: "2", StorageDriver: "overlay", Swarm: "inactive", Rootless: true, Podman: true}
+	if caps != want {
+		t.Fatalf("capabilities = %+v, want %+v", caps, want)
+	}
+	if caps.Supports(FeatureStats) != nil || !errors.Is(caps.Supports(FeatureOverlayNetworks), ErrUnsupported) {
+		t.Fatal("a rootless cgroup v2 daemon has stats but no overlay networks")
+	}
+}
+
+func TestAPIDetectionDoesNotBlock(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Api-Version", "1.40")
+		w.Write([]byte("OK"))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	c, err := NewClient(Options{Host: "unix://" + socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	first := make(chan struct{})
+	go func() {
+		c.api(context.Background())
+		close(first)
+	}()
+	for {
+		c.mu.Lock()
+		started := c.detecting != nil
+		c.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The lock is free while the ping is out, and waiting ends with ctx
+	start := time.Now()
+	c.Podman()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c.api(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("blocked for %s behind the ping in flight", elapsed)
+	}
+
+	close(release)
+	<-first
+	if version := c.api(context.Background()).ClientVersion(); version != "1.40" {
+		t.Errorf("API version = %s, want the negotiated 1.40", version)
+	}
+}
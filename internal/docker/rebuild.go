@@ -0,0 +1,264 @@
+package docker
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"gopkg.in/yaml.v3"
+)
+
+// anonymousVolume matches the generated names of anonymous volumes
+var anonymousVolume = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// RunOptionsOf rebuilds the options a container was created with from its
+// configuration. What it got from its image is left out, as is what
+// compose added, so the result reads like what was typed.
+func (c *Client) RunOptionsOf(ctx context.Context, containerID string) (_ RunOptions, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+
+	info, err := c.api(ctx).ContainerInspect(ctx, containerID)
+	if err != nil {
+		return RunOptions{}, err
+	}
+	// Without the image everything is kept, which is only more verbose
+	var image *container.Config
+	if img, _, err := c.api(ctx).ImageInspectWithRaw(ctx, info.Image); err == nil {
+		image = img.Config
+	}
+	return runOptionsOf(info, image), nil
+}
+
+// runOptionsOf turns an inspected container into run options, dropping
+// the defaults of its image
+func runOptionsOf(info types.ContainerJSON, image *container.Config) RunOptions {
+	if image == nil {
+		image = &container.Config{}
+	}
+	opts := RunOptions{Name: strings.TrimPrefix(info.Name, "/")}
+
+	if cfg := info.Config; cfg != nil {
+		opts.Image = cfg.Image
+		if !slices.Equal(cfg.Entrypoint, image.Entrypoint) {
+			opts.Entrypoint = cfg.Entrypoint
+		}
+		// A new entrypoint drops the image's command, so it is kept
+		if len(opts.Entrypoint) > 0 || !slices.Equal(cfg.Cmd, image.Cmd) {
+			opts.Command = cfg.Cmd
+		}
+		if cfg.User != image.User {
+			opts.User = cfg.User
+		}
+		if cfg.WorkingDir != image.WorkingDir {
+			opts.WorkingDir = cfg.WorkingDir
+		}
+		for _, kv := range cfg.Env {
+			if !slices.Contains(image.Env, kv) {
+				opts.Env = append(opts.Env, kv)
+			}
+		}
+		for k, v := range cfg.Labels {
+			if value, ok := image.Labels[k]; (ok && value == v) || strings.HasPrefix(k, "com.docker.compose.") {
+				continue
+			}
+			if opts.Labels == nil {
+				opts.Labels = map[string]string{}
+			}
+			opts.Labels[k] = v
+		}
+	}
+
+	primary := "bridge"
+	if hc := info.HostConfig; hc != nil {
+		for port, bindings := range hc.PortBindings {
+			for _, b := range bindings {
+				opts.Ports = append(opts.Ports, portSpec(port, b))
+			}
+		}
+		// The daemon binds IPv4 and IPv6 apart for a single -p
+		sort.Strings(opts.Ports)
+		opts.Ports = slices.Compact(opts.Ports)
+		if restart := formatRestartPolicy(hc.RestartPolicy); restart != "no" {
+			opts.Restart = restart
+		}
+		switch mode := string(hc.NetworkMode); mode {
+		case "", "default", "bridge":
+		default:
+			opts.Network, primary = mode, mode
+		}
+		switch {
+		case hc.NanoCPUs > 0:
+			opts.CPUs = float64(hc.NanoCPUs) / 1e9
+		case hc.CPUQuota > 0 && hc.CPUPeriod > 0:
+			opts.CPUs = float64(hc.CPUQuota) / float64(hc.CPUPeriod)
+		}
+		opts.Memory = hc.Memory
+	}
+
+	for _, m := range info.Mounts {
+		var spec string
+		switch {
+		case m.Type == mount.TypeBind:
+			spec = m.Source + ":" + m.Destination
+		case m.Type == mount.TypeVolume && anonymousVolume.MatchString(m.Name):
+			if _, declared := image.Volumes[m.Destination]; declared {
+				// The image creates it again
+				continue
+			}
+			spec = m.Destination
+		case m.Type == mount.TypeVolume:
+			spec = m.Name + ":" + m.Destination
+		default:
+			continue
+		}
+		if !m.RW {
+			spec += ":ro"
+		}
+		opts.Volumes = append(opts.Volumes, spec)
+	}
+	sort.Strings(opts.Volumes)
+
+	if info.NetworkSettings != nil {
+		for name := range info.NetworkSettings.Networks {
+			if name != primary {
+				opts.Networks = append(opts.Networks, name)
+			}
+		}
+		sort.Strings(opts.Networks)
+	}
+	return opts
+}
+
+// portSpec renders a port binding in -p syntax, leaving out the defaults
+func portSpec(port nat.Port, b nat.PortBinding) string {
+	spec := port.Port()
+	if port.Proto() != "tcp" {
+		spec += "/" + port.Proto()
+	}
+	ip := b.HostIP
+	if ip == "0.0.0.0" || ip == "::" {
+		ip = ""
+	}
+	switch {
+	case ip != "":
+		return ip + ":" + b.HostPort + ":" + spec
+	case b.HostPort != "":
+		return b.HostPort + ":" + spec
+	}
+	return spec
+}
+
+type composeFile struct {
+	Services map[string]composeService  `yaml:"services"`
+	Networks map[string]composeExternal `yaml:"networks,omitempty"`
+	Volumes  map[string]composeExternal `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image         string            `yaml:"image"`
+	ContainerName string            `yaml:"container_name,omitempty"`
+	Entrypoint    []string          `yaml:"entrypoint,omitempty"`
+	Command       []string          `yaml:"command,omitempty"`
+	User          string            `yaml:"user,omitempty"`
+	WorkingDir    string            `yaml:"working_dir,omitempty"`
+	Environment   []string          `yaml:"environment,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Ports         []string          `yaml:"ports,omitempty"`
+	Volumes       []string          `yaml:"volumes,omitempty"`
+	NetworkMode   string            `yaml:"network_mode,omitempty"`
+	Networks      []string          `yaml:"networks,omitempty"`
+	Restart       string            `yaml:"restart,omitempty"`
+	CPUs          float64           `yaml:"cpus,omitempty"`
+	MemLimit      string            `yaml:"mem_limit,omitempty"`
+}
+
+type composeExternal struct {
+	External bool `yaml:"external"`
+}
+
+// ComposeService renders the options as a compose file with a single
+// service. The networks and named volumes it uses are declared external,
+// as they already exist.
+func (o RunOptions) ComposeService() (string, error) {
+	// Compose would interpolate variables, so dollars are escaped
+	escape := func(values []string) []string {
+		if values == nil {
+			return nil
+		}
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = strings.ReplaceAll(v, "$", "$$")
+		}
+		return escaped
+	}
+
+	svc := composeService{
+		Image:         o.Image,
+		ContainerName: o.Name,
+		Entrypoint:    escape(o.Entrypoint),
+		Command:       escape(o.Command),
+		User:          o.User,
+		WorkingDir:    o.WorkingDir,
+		Environment:   escape(o.Env),
+		Ports:         o.Ports,
+		Volumes:       o.Volumes,
+		Restart:       o.Restart,
+		CPUs:          o.CPUs,
+	}
+	if len(o.Labels) > 0 {
+		svc.Labels = map[string]string{}
+		for k, v := range o.Labels {
+			svc.Labels[k] = strings.ReplaceAll(v, "$", "$$")
+		}
+	}
+	if o.Memory > 0 {
+		svc.MemLimit = FormatMemory(o.Memory)
+	}
+
+	file := composeFile{Services: map[string]composeService{}}
+	networks := o.Networks
+	switch mode := container.NetworkMode(o.Network); {
+	case o.Network == "":
+	case mode.IsHost() || mode.IsNone() || mode.IsContainer():
+		svc.NetworkMode = o.Network
+	default:
+		networks = append([]string{o.Network}, networks...)
+	}
+	if len(networks) > 0 {
+		svc.Networks = networks
+		file.Networks = map[string]composeExternal{}
+		for _, n := range networks {
+			file.Networks[n] = composeExternal{External: true}
+		}
+	}
+	for _, v := range o.Volumes {
+		source, _, found := strings.Cut(v, ":")
+		if found && source != "" && !strings.ContainsAny(source[:1], "/.~") {
+			if file.Volumes == nil {
+				file.Volumes = map[string]composeExternal{}
+			}
+			file.Volumes[source] = composeExternal{External: true}
+		}
+	}
+
+	name := o.Name
+	if name == "" {
+		name = "app"
+	}
+	file.Services[name] = svc
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
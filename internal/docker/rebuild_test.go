@@ -0,0 +1,146 @@
+package docker
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+func TestRunOptionsOf(t *testing.T) {
+	image := &container.Config{
+		Entrypoint: []string{"/docker-entrypoint.sh"},
+		Cmd:        []string{"nginx", "-g", "daemon off;"},
+		Env:        []string{"PATH=/usr/bin", "NGINX_VERSION=1.25"},
+		Labels:     map[string]string{"maintainer": "nginx"},
+		Volumes:    map[string]struct{}{"/var/cache/nginx": {}},
+	}
+	info := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Name: "/web",
+			HostConfig: &container.HostConfig{
+				PortBindings: nat.PortMap{
+					"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "::", HostPort: "8080"}},
+					"443/tcp": {{HostIP: "127.0.0.1", HostPort: "8443"}},
+					"53/udp":  {{HostPort: "5353"}},
+				},
+				RestartPolicy: container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3},
+				NetworkMode:   "frontend",
+				Resources:     container.Resources{NanoCPUs: 1.5e9, Memory: 256 << 20},
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeBind, Source: "/srv/www", Destination: "/usr/share/nginx/html"},
+			{Type: mount.TypeVolume, Name: "logs", Destination: "/var/log/nginx", RW: true},
+			{Type: mount.TypeVolume, Name: strings.Repeat("a", 64), Destination: "/var/cache/nginx", RW: true},
+			{Type: mount.TypeVolume, Name: strings.Repeat("b", 64), Destination: "/scratch", RW: true},
+			{Type: mount.TypeTmpfs, Destination: "/run"},
+		},
+		Config: &container.Config{
+			Image:      "nginx:1.25",
+			Entrypoint: image.Entrypoint,
+			Cmd:        image.Cmd,
+			Env:        append([]string{"MODE=prod"}, image.Env...),
+			Labels: map[string]string{
+				"maintainer":                 "nginx",
+				"team":                       "web",
+				"com.docker.compose.project": "shop",
+			},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{"frontend": {}, "backend": {}},
+		},
+	}
+
+	want := RunOptions{
+		Name:     "web",
+		Image:    "nginx:1.25",
+		Env:      []string{"MODE=prod"},
+		Labels:   map[string]string{"team": "web"},
+		Ports:    []string{"127.0.0.1:8443:443", "5353:53/udp", "8080:80"},
+		Volumes:  []string{"/scratch", "/srv/www:/usr/share/nginx/html:ro", "logs:/var/log/nginx"},
+		Network:  "frontend",
+		Networks: []string{"backend"},
+		Restart:  "on-failure:3",
+		CPUs:     1.5,
+		Memory:   256 << 20,
+	}
+	if got := runOptionsOf(info, image); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	// A new entrypoint drops the image's command, so the command is kept
+	info.Config.Entrypoint = []string{"sh", "-c"}
+	got := runOptionsOf(info, image)
+	if !slices.Equal(got.Entrypoint, info.Config.Entrypoint) || !slices.Equal(got.Command, image.Cmd) {
+		t.Errorf("entrypoint %q command %q, want both", got.Entrypoint, got.Command)
+	}
+}
+
+func TestComposeService(t *testing.T) {
+	opts := RunOptions{
+		Name:     "web",
+		Image:    "nginx:1.25",
+		Command:  []string{"sh", "-c", "echo $HOME"},
+		Env:      []string{"MODE=prod"},
+		Labels:   map[string]string{"team": "web"},
+		Ports:    []string{"8080:80"},
+		Volumes:  []string{"/srv/www:/usr/share/nginx/html:ro", "logs:/var/log/nginx"},
+		Network:  "frontend",
+		Networks: []string{"backend"},
+		Restart:  "unless-stopped",
+		CPUs:     0.5,
+		Memory:   512 << 20,
+	}
+
+	want := `services:
+  web:
+    image: nginx:1.25
+    container_name: web
+    command:
+      - sh
+      - -c
+      - echo $$HOME
+    environment:
+      - MODE=prod
+    labels:
+      team: web
+    ports:
+      - 8080:80
+    volumes:
+      - /srv/www:/usr/share/nginx/html:ro
+      - logs:/var/log/nginx
+    networks:
+      - frontend
+      - backend
+    restart: unless-stopped
+    cpus: 0.5
+    mem_limit: 512m
+networks:
+  backend:
+    external: true
+  frontend:
+    external: true
+volumes:
+  logs:
+    external: true
+`
+	got, err := opts.ComposeService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got, _ = RunOptions{Image: "redis", Network: "host"}.ComposeService()
+	if want := "services:\n  app:\n    image: redis\n    network_mode: host\n"; got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
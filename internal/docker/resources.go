@@ -0,0 +1,175 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+)
+
+// Resources are the limits of a container that can change while it runs,
+// like the flags of `docker update`
+type Resources struct {
+	CPUShares  int64   // weight against other containers, 0 for the default of 1024
+	CPUs       float64 // 0 for no limit
+	CPUPeriod  int64   // in microseconds when the CPU limit is a quota, 0 when it was set with --cpus
+	Memory     int64   // in bytes, 0 for no limit
+	MemorySwap int64   // memory plus swap in bytes, -1 for unlimited swap
+	PidsLimit  int64   // 0 for no limit
+	Restart    string  // no, always, unless-stopped or on-failure[:N]
+}
+
+// Bounds the daemon enforces on CPU limits
+const (
+	MinCPUShares = 2
+	MaxCPUShares = 262144
+	MinCPUs      = 0.01
+	minCPUQuota  = 1000 // microseconds
+)
+
+// errRemoved is returned for a limit cleared on a running container: the
+// daemon reads a zero as "unchanged", so only recreating drops it
+var errRemoved = errors.New("a limit can be changed but not removed without recreating the container")
+
+// ValidateUpdate checks that the daemon would accept changing the limits
+// from current to r, returning an *InvalidOptionError for the first problem
+func (r Resources) ValidateUpdate(current Resources) error {
+	invalid := func(option, format string, args ...any) error {
+		return &InvalidOptionError{Option: option, Err: fmt.Errorf(format, args...)}
+	}
+
+	switch {
+	case r.CPUShares < 0 || r.CPUShares > MaxCPUShares || (r.CPUShares > 0 && r.CPUShares < MinCPUShares):
+		return invalid("cpu shares", "must be between %d and %d", MinCPUShares, MaxCPUShares)
+	case r.CPUShares == 0 && current.CPUShares > 0:
+		return &InvalidOptionError{Option: "cpu shares", Err: errRemoved}
+	}
+
+	switch {
+	case r.CPUs < 0 || (r.CPUs > 0 && r.CPUs < MinCPUs):
+		return invalid("cpus", "must be at least %g", MinCPUs)
+	case r.CPUs > 0 && r.CPUPeriod > 0 && int64(r.CPUs*float64(r.CPUPeriod)) < minCPUQuota:
+		return invalid("cpus", "a quota of less than %dµs per %dµs period is too small", minCPUQuota, r.CPUPeriod)
+	case r.CPUs == 0 && current.CPUs > 0:
+		return &InvalidOptionError{Option: "cpus", Err: errRemoved}
+	}
+
+	switch {
+	case r.Memory < 0 || (r.Memory > 0 && r.Memory < MinMemory):
+		return invalid("memory", "must be at least %s", units.BytesSize(MinMemory))
+	case r.Memory == 0 && current.Memory > 0:
+		return &InvalidOptionError{Option: "memory", Err: errRemoved}
+	}
+
+	// A zero leaves the swap limit as it is, which still has to fit
+	swap := r.MemorySwap
+	if swap == 0 {
+		swap = current.MemorySwap
+	}
+	switch {
+	case r.MemorySwap < -1:
+		return invalid("swap", "must be -1 for unlimited, or a size")
+	case r.MemorySwap > 0 && r.Memory == 0:
+		return invalid("swap", "needs a memory limit")
+	case swap > 0 && swap < r.Memory:
+		return invalid("swap", "%s is less than the memory limit it includes; raise it too, or set -1 for unlimited", units.BytesSize(float64(swap)))
+	}
+
+	if r.PidsLimit < 0 {
+		return invalid("pids", "must not be negative")
+	}
+	if _, err := parseRestartPolicy(r.Restart); err != nil {
+		return &InvalidOptionError{Option: "restart", Err: err}
+	}
+	return nil
+}
+
+// resourcesOf reads the limits of an inspected container
+func resourcesOf(hc *container.HostConfig) Resources {
+	r := Resources{
+		CPUShares:  hc.CPUShares,
+		Memory:     hc.Memory,
+		MemorySwap: hc.MemorySwap,
+		Restart:    formatRestartPolicy(hc.RestartPolicy),
+	}
+	switch {
+	case hc.NanoCPUs > 0:
+		r.CPUs = float64(hc.NanoCPUs) / 1e9
+	case hc.CPUQuota > 0:
+		r.CPUPeriod = hc.CPUPeriod
+		if r.CPUPeriod == 0 {
+			r.CPUPeriod = 100000
+		}
+		r.CPUs = float64(hc.CPUQuota) / float64(r.CPUPeriod)
+	}
+	if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+		r.PidsLimit = *hc.PidsLimit
+	}
+	return r
+}
+
+// updateConfig is the body of a ContainerUpdate call setting r
+func (r Resources) updateConfig() container.UpdateConfig {
+	restart, _ := parseRestartPolicy(r.Restart)
+	if restart.Name == "" {
+		// An empty name leaves the policy unchanged
+		restart.Name = "no"
+	}
+	pids := r.PidsLimit
+	update := container.UpdateConfig{
+		Resources: container.Resources{
+			CPUShares:  r.CPUShares,
+			Memory:     r.Memory,
+			MemorySwap: r.MemorySwap,
+			PidsLimit:  &pids,
+		},
+		RestartPolicy: restart,
+	}
+	// The daemon refuses to mix --cpus with a quota, so the limit is set
+	// the way it already was
+	if r.CPUPeriod > 0 {
+		update.CPUPeriod = r.CPUPeriod
+		update.CPUQuota = int64(r.CPUs * float64(r.CPUPeriod))
+	} else {
+		update.NanoCPUs = int64(r.CPUs * 1e9)
+	}
+	return update
+}
+
+// ContainerResources returns the limits a container runs with
+func (c *Client) ContainerResources(ctx context.Context, containerID string) (_ Resources, err error) {
+	ctx, done := limit(ctx, c.timeouts.Query)
+	defer done(&err)
+
+	info, err := c.api(ctx).ContainerInspect(ctx, containerID)
+	if err != nil {
+		return Resources{}, err
+	}
+	if info.ContainerJSONBase == nil || info.HostConfig == nil {
+		return Resources{}, nil
+	}
+	return resourcesOf(info.HostConfig), nil
+}
+
+// UpdateResources changes the limits of a container without restarting it
+func (c *Client) UpdateResources(ctx context.Context, containerID string, r Resources) (err error) {
+	if err := c.checkWritable("update resources"); err != nil {
+		return err
+	}
+	ctx, done := limit(ctx, c.timeouts.Action)
+	defer done(&err)
+
+	current, err := c.ContainerResources(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if err := r.ValidateUpdate(current); err != nil {
+		return err
+	}
+	if _, err := c.api(ctx).ContainerUpdate(ctx, containerID, r.updateConfig()); err != nil {
+		return c.unsupported("updating resources", err)
+	}
+	return nil
+}
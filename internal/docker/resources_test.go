@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestResourcesValidateUpdate(t *testing.T) {
+	current := Resources{CPUShares: 512, CPUs: 1, Memory: 512 << 20, MemorySwap: 1 << 30, Restart: "no"}
+	valid := Resources{CPUShares: 1024, CPUs: 2, Memory: 1 << 30, PidsLimit: 100, Restart: "on-failure:3"}
+	if err := valid.ValidateUpdate(current); err != nil {
+		t.Fatalf("valid update: %v", err)
+	}
+
+	for _, tc := range []struct {
+		option string
+		change func(*Resources)
+	}{
+		{"cpu shares", func(r *Resources) { r.CPUShares = 1 }},
+		{"cpu shares", func(r *Resources) { r.CPUShares = 0 }},
+		{"cpus", func(r *Resources) { r.CPUs = 0.001 }},
+		{"cpus", func(r *Resources) { r.CPUs = 0 }},
+		{"cpus", func(r *Resources) { r.CPUs, r.CPUPeriod = 0.005, 100000 }},
+		{"memory", func(r *Resources) { r.Memory = 1 << 20 }},
+		{"memory", func(r *Resources) { r.Memory = 0 }},
+		// The current swap no longer covers the memory
+		{"swap", func(r *Resources) { r.Memory = 2 << 30 }},
+		{"swap", func(r *Resources) { r.MemorySwap = 256 << 20 }},
+		{"swap", func(r *Resources) { r.MemorySwap = -2 }},
+		{"pids", func(r *Resources) { r.PidsLimit = -1 }},
+		{"restart", func(r *Resources) { r.Restart = "sometimes" }},
+	} {
+		r := valid
+		tc.change(&r)
+		var invalid *InvalidOptionError
+		if err := r.ValidateUpdate(current); !errors.As(err, &invalid) || invalid.Option != tc.option {
+			t.Errorf("%+v: got %v, want an invalid %s", r, err, tc.option)
+		}
+	}
+
+	unlimited := valid
+	unlimited.Memory, unlimited.MemorySwap = 2<<30, -1
+	if err := unlimited.ValidateUpdate(current); err != nil {
+		t.Errorf("unlimited swap: %v", err)
+	}
+}
+
+func TestResourcesUpdateConfig(t *testing.T) {
+	pids := int64(64)
+	for _, hc := range []container.HostConfig{
+		{Resources: container.Resources{NanoCPUs: 1.5e9, Memory: 256 << 20, PidsLimit: &pids}},
+		{Resources: container.Resources{CPUQuota: 50000, CPUPeriod: 200000, CPUShares: 512}},
+	} {
+		hc.RestartPolicy = container.RestartPolicy{Name: "unless-stopped"}
+		r := resourcesOf(&hc)
+		update := r.updateConfig()
+		// The limit is sent back the way it was set, or the daemon refuses it
+		if update.NanoCPUs != hc.NanoCPUs || update.CPUQuota != hc.CPUQuota || update.CPUPeriod != hc.CPUPeriod {
+			t.Errorf("%+v: cpu sent as nano %d, quota %d per %d", r, update.NanoCPUs, update.CPUQuota, update.CPUPeriod)
+		}
+		if update.RestartPolicy.Name != "unless-stopped" || update.CPUShares != hc.CPUShares || update.Memory != hc.Memory {
+			t.Errorf("%+v: sent %+v", r, update)
+		}
+	}
+
+	// Clearing the policy has to be said, an empty one changes nothing
+	if update := (Resources{Restart: "no"}).updateConfig(); update.RestartPolicy.Name != "no" {
+		t.Errorf("restart policy sent as %q, want no", update.RestartPolicy.Name)
+	}
+}
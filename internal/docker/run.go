@@ -0,0 +1,394 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+)
+
+// RunOptions describes a container to create and start, like the flags of
+// `docker run -d`
+type RunOptions struct {
+	Host       string // the fleet host to run on, not needed with a single host
+	Image      string
+	Name       string            // left to the daemon when empty
+	Entrypoint []string          // replaces the image's entrypoint when set
+	Command    []string          // replaces the image's command when set
+	User       string            // the image's user when empty
+	WorkingDir string            // the image's working directory when empty
+	Env        []string          // KEY=value, or KEY to pass the variable through from the caller's environment
+	Labels     map[string]string // added to the image's labels
+	Ports      []string          // [ip:]host:container[/proto] or container[/proto]
+	Volumes    []string          // source:target[:mode] for binds and named volumes, target for anonymous ones
+	Network    string            // the default bridge when empty
+	Networks   []string          // further networks, connected before the container starts
+	Restart    string            // no, always, unless-stopped or on-failure[:N]
+	CPUs       float64           // 0 for no limit
+	Memory     int64             // in bytes, 0 for no limit
+}
+
+// MinMemory is the smallest memory limit the daemon accepts
+const MinMemory = 6 << 20
+
+// containerName is what the daemon accepts as a container name
+var containerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// InvalidOptionError says which option of a RunOptions or Resources is
+// wrong
+type InvalidOptionError struct {
+	Option string // image, name, env, labels, ports, volumes, networks, restart, cpu shares, cpus, memory, swap or pids
+	Err    error
+}
+
+func (e *InvalidOptionError) Error() string {
+	return e.Option + ": " + e.Err.Error()
+}
+
+func (e *InvalidOptionError) Unwrap() error { return e.Err }
+
+// Validate checks the options the way `docker run` would before sending
+// them, returning an *InvalidOptionError for the first problem
+func (o RunOptions) Validate() error {
+	invalid := func(option, format string, args ...any) error {
+		return &InvalidOptionError{Option: option, Err: fmt.Errorf(format, args...)}
+	}
+
+	if o.Image == "" {
+		return invalid("image", "an image is required")
+	}
+	if strings.ContainsAny(o.Image, " \t") {
+		return invalid("image", "%q contains spaces", o.Image)
+	}
+	if o.Name != "" && !containerName.MatchString(o.Name) {
+		return invalid("name", "%q may only contain letters, digits, '_', '.' and '-', and must start with a letter or digit", o.Name)
+	}
+	for _, kv := range o.Env {
+		key, _, _ := strings.Cut(kv, "=")
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return invalid("env", "%q is not KEY=value", kv)
+		}
+	}
+	for key := range o.Labels {
+		if key == "" {
+			return invalid("labels", "a label needs a key")
+		}
+	}
+	if _, _, err := nat.ParsePortSpecs(o.Ports); err != nil {
+		return invalid("ports", "%w", err)
+	}
+	for _, v := range o.Volumes {
+		if err := validateVolume(v); err != nil {
+			return &InvalidOptionError{Option: "volumes", Err: err}
+		}
+	}
+	if len(o.Networks) > 0 && o.Name == "" {
+		return invalid("networks", "joining further networks needs a container name")
+	}
+	if _, err := parseRestartPolicy(o.Restart); err != nil {
+		return &InvalidOptionError{Option: "restart", Err: err}
+	}
+	if o.CPUs < 0 {
+		return invalid("cpus", "must not be negative")
+	}
+	if o.Memory < 0 || (o.Memory > 0 && o.Memory < MinMemory) {
+		return invalid("memory", "must be at least %s", units.BytesSize(MinMemory))
+	}
+	return nil
+}
+
+// validateVolume checks a -v spec: an absolute target, optionally after a
+// source and followed by a mode
+func validateVolume(spec string) error {
+	parts := strings.Split(spec, ":")
+	var target string
+	switch len(parts) {
+	case 1:
+		target = parts[0]
+	case 2, 3:
+		if parts[0] == "" {
+			return fmt.Errorf("%q has an empty source", spec)
+		}
+		target = parts[1]
+		if len(parts) == 3 {
+			for _, mode := range strings.Split(parts[2], ",") {
+				switch mode {
+				case "ro", "rw", "z", "Z", "shared", "rshared", "slave", "rslave", "private", "rprivate", "nocopy":
+				default:
+					return fmt.Errorf("%q has an unknown mode %q", spec, mode)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("%q is not source:target[:mode]", spec)
+	}
+	if !path.IsAbs(target) {
+		return fmt.Errorf("%q: the container path must be absolute", spec)
+	}
+	return nil
+}
+
+// Args returns the arguments of the equivalent `docker run` command,
+// starting with "run". A bare KEY in Env stays `-e KEY`, which takes the
+// value from the environment of whoever runs the command.
+func (o RunOptions) Args() []string {
+	args := []string{"run", "-d"}
+	if o.Name != "" {
+		args = append(args, "--name", o.Name)
+	}
+	if o.User != "" {
+		args = append(args, "-u", o.User)
+	}
+	if o.WorkingDir != "" {
+		args = append(args, "-w", o.WorkingDir)
+	}
+	for _, kv := range o.Env {
+		args = append(args, "-e", kv)
+	}
+	for _, key := range sortedKeys(o.Labels) {
+		args = append(args, "--label", key+"="+o.Labels[key])
+	}
+	for _, p := range o.Ports {
+		args = append(args, "-p", p)
+	}
+	for _, v := range o.Volumes {
+		args = append(args, "-v", v)
+	}
+	if o.Network != "" {
+		args = append(args, "--network", o.Network)
+	}
+	if o.Restart != "" && o.Restart != "no" {
+		args = append(args, "--restart", o.Restart)
+	}
+	if o.CPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(o.CPUs, 'f', -1, 64))
+	}
+	if o.Memory > 0 {
+		args = append(args, "--memory", FormatMemory(o.Memory))
+	}
+	// --entrypoint takes a single word, the rest goes before the command
+	command := o.Command
+	if len(o.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", o.Entrypoint[0])
+		command = append(append([]string(nil), o.Entrypoint[1:]...), command...)
+	}
+	args = append(args, o.Image)
+	return append(args, command...)
+}
+
+// ConnectArgs returns the arguments of the `docker network connect`
+// commands for the networks a single `docker run` cannot join. Validate
+// makes sure there is a name to connect by.
+func (o RunOptions) ConnectArgs() [][]string {
+	var cmds [][]string
+	for _, n := range o.Networks {
+		cmds = append(cmds, []string{"network", "connect", n, o.Name})
+	}
+	return cmds
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatMemory renders a byte count the way the --memory flag takes it,
+// e.g. 512m or 1g, falling back to plain bytes
+func FormatMemory(bytes int64) string {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"g", units.GiB}, {"m", units.MiB}, {"k", units.KiB}} {
+		if bytes >= u.size && bytes%u.size == 0 {
+			return strconv.FormatInt(bytes/u.size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// FormatCommand renders "docker" and args as a shell command line. When
+// width is positive the line is broken between flags to stay within it.
+func FormatCommand(args []string, width int) string {
+	// A flag and its value stay on the same line
+	var chunks []string
+	for i := 0; i < len(args); i++ {
+		chunk := ShellQuote(args[i])
+		if takesValue(args[i]) && i+1 < len(args) {
+			i++
+			chunk += " " + ShellQuote(args[i])
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	const indent = "    "
+	var b strings.Builder
+	b.WriteString("docker")
+	line := len("docker")
+	for _, chunk := range chunks {
+		// Room is left for the trailing " \\"
+		if width > 0 && line > len(indent) && line+1+len(chunk)+2 > width {
+			b.WriteString(" \\\n" + indent)
+			line = len(indent)
+		} else {
+			b.WriteString(" ")
+			line++
+		}
+		b.WriteString(chunk)
+		line += len(chunk)
+	}
+	return b.String()
+}
+
+// takesValue reports whether arg is a `docker run` flag followed by a value
+func takesValue(arg string) bool {
+	if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+		return false
+	}
+	switch arg {
+	case "-d", "--detach", "-i", "--interactive", "-t", "--tty", "--rm", "--init", "--privileged", "--read-only":
+		return false
+	}
+	return true
+}
+
+// ShellQuote quotes an argument for a POSIX shell when it needs it
+func ShellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@%+", r))
+	}) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// RunContainer pulls the image if needed, then creates and starts a
+// container, returning its ID
+func (c *Client) RunContainer(ctx context.Context, opts RunOptions) (_ string, err error) {
+	if err := c.checkWritable("run container"); err != nil {
+		return "", err
+	}
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
+	// Pulling may take as long as it does for compose
+	ctx, done := limit(ctx, c.timeouts.Compose)
+	defer done(&err)
+
+	if err := c.ensureImage(ctx, opts.Image); err != nil {
+		return "", fmt.Errorf("pull %s: %w", opts.Image, err)
+	}
+
+	exposed, bindings, _ := nat.ParsePortSpecs(opts.Ports)
+	restart, _ := parseRestartPolicy(opts.Restart)
+	config := &container.Config{
+		Image:        opts.Image,
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		Env:          opts.ResolvedEnv(),
+		Labels:       opts.Labels,
+		ExposedPorts: exposed,
+		Volumes:      map[string]struct{}{},
+	}
+	if len(opts.Entrypoint) > 0 {
+		config.Entrypoint = strslice.StrSlice(opts.Entrypoint)
+	}
+	if len(opts.Command) > 0 {
+		config.Cmd = strslice.StrSlice(opts.Command)
+	}
+	for _, v := range opts.Volumes {
+		if !strings.Contains(v, ":") {
+			config.Volumes[v] = struct{}{}
+		}
+	}
+	hostConfig := &container.HostConfig{
+		Binds:         bindsOnly(opts.Volumes),
+		PortBindings:  bindings,
+		RestartPolicy: restart,
+		NetworkMode:   container.NetworkMode(opts.Network),
+		Resources: container.Resources{
+			NanoCPUs: int64(opts.CPUs * 1e9),
+			Memory:   opts.Memory,
+		},
+	}
+	var networking *network.NetworkingConfig
+	if opts.Network != "" {
+		networking = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{opts.Network: {}},
+		}
+	}
+
+	created, err := c.api(ctx).ContainerCreate(ctx, config, hostConfig, networking, nil, opts.Name)
+	if err != nil {
+		return "", c.unsupported("creating containers", err)
+	}
+	for _, n := range opts.Networks {
+		if err := c.api(ctx).NetworkConnect(ctx, n, created.ID, &network.EndpointSettings{}); err != nil {
+			return "", c.discard(ctx, created.ID, fmt.Errorf("connect %s to %s: %w", opts.Name, n, err))
+		}
+	}
+	if err := c.api(ctx).ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return "", c.discard(ctx, created.ID, fmt.Errorf("start %s: %w", orID(opts.Name, created.ID), err))
+	}
+	return created.ID, nil
+}
+
+// discard removes a container RunContainer created but could not set up,
+// so a retry is not refused for the name. The removal gets its own time,
+// as err may be that ctx ran out.
+func (c *Client) discard(ctx context.Context, id string, err error) error {
+	ctx, done := limit(context.WithoutCancel(ctx), c.timeouts.Action)
+	rmErr := c.api(ctx).ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+	done(&rmErr)
+	if rmErr != nil {
+		return fmt.Errorf("%w; container %s was created and is left stopped (removing it failed: %v)", err, orID("", id), rmErr)
+	}
+	return err
+}
+
+// ResolvedEnv returns Env with bare KEY entries filled in from the
+// environment, as `docker run -e KEY` does, and those that are not set
+// dropped
+func (o RunOptions) ResolvedEnv() []string {
+	var resolved []string
+	for _, kv := range o.Env {
+		if !strings.Contains(kv, "=") {
+			value, ok := os.LookupEnv(kv)
+			if !ok {
+				continue
+			}
+			kv += "=" + value
+		}
+		resolved = append(resolved, kv)
+	}
+	return resolved
+}
+
+// orID names a container by name, or by its short ID when it has none
+func orID(name, id string) string {
+	if name != "" {
+		return name
+	}
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
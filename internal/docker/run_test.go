@@ -0,0 +1,168 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestRunOptionsValidate(t *testing.T) {
+	valid := RunOptions{
+		Image:   "nginx:1.25",
+		Name:    "web",
+		Env:     []string{"MODE=prod", "HOME"},
+		Ports:   []string{"8080:80", "127.0.0.1:8443:443/tcp", "9000"},
+		Volumes: []string{"/srv/www:/usr/share/nginx/html:ro", "cache:/var/cache/nginx", "/tmp"},
+		Restart: "on-failure:3",
+		CPUs:    0.5,
+		Memory:  256 << 20,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid options: %v", err)
+	}
+
+	for _, tc := range []struct {
+		option string
+		change func(*RunOptions)
+	}{
+		{"image", func(o *RunOptions) { o.Image = "" }},
+		{"name", func(o *RunOptions) { o.Name = "-web" }},
+		{"env", func(o *RunOptions) { o.Env = []string{"=value"} }},
+		{"ports", func(o *RunOptions) { o.Ports = []string{"80:http"} }},
+		{"volumes", func(o *RunOptions) { o.Volumes = []string{"/srv:relative"} }},
+		{"volumes", func(o *RunOptions) { o.Volumes = []string{"/srv:/data:rx"} }},
+		{"networks", func(o *RunOptions) { o.Name, o.Networks = "", []string{"back"} }},
+		{"restart", func(o *RunOptions) { o.Restart = "sometimes" }},
+		{"memory", func(o *RunOptions) { o.Memory = 1 << 20 }},
+	} {
+		opts := valid
+		tc.change(&opts)
+		var invalid *InvalidOptionError
+		if err := opts.Validate(); !errors.As(err, &invalid) || invalid.Option != tc.option {
+			t.Errorf("%+v: got %v, want an invalid %s", opts, err, tc.option)
+		}
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	opts := RunOptions{
+		Image:   "nginx:1.25",
+		Name:    "web",
+		Command: []string{"sh", "-c", "echo it's up"},
+		Env:     []string{"GREETING=hello world"},
+		Ports:   []string{"8080:80"},
+		Restart: "unless-stopped",
+		Memory:  512 << 20,
+	}
+
+	want := `docker run -d --name web -e 'GREETING=hello world' -p 8080:80 --restart unless-stopped --memory 512m nginx:1.25 sh -c 'echo it'\''s up'`
+	if got := FormatCommand(opts.Args(), 0); got != want {
+		t.Errorf("one line:\n got %s\nwant %s", got, want)
+	}
+
+	want = `docker run -d --name web \
+    -e 'GREETING=hello world' \
+    -p 8080:80 \
+    --restart unless-stopped \
+    --memory 512m nginx:1.25 sh \
+    -c 'echo it'\''s up'`
+	if got := FormatCommand(opts.Args(), 40); got != want {
+		t.Errorf("wrapped at 40:\n got %s\nwant %s", got, want)
+	}
+}
+
+// runDaemon records the environment of the container RunContainer created
+// and what it removed
+type runDaemon struct {
+	mu      sync.Mutex
+	env     []string
+	removed []string
+}
+
+// startRunDaemon serves the calls of RunContainer, failing to connect to
+// any network
+func startRunDaemon(t *testing.T) (string, *runDaemon) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &runDaemon{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("GET /v1.41/images/nginx/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Id":"sha256:abc"}`))
+	})
+	mux.HandleFunc("POST /v1.41/containers/create", func(w http.ResponseWriter, r *http.Request) {
+		var body container.Config
+		json.NewDecoder(r.Body).Decode(&body)
+		d.mu.Lock()
+		d.env = body.Env
+		d.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"new1"}`))
+	})
+	mux.HandleFunc("POST /v1.41/networks/{name}/connect", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"network back not found"}`))
+	})
+	mux.HandleFunc("DELETE /v1.41/containers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		d.removed = append(d.removed, r.PathValue("id"))
+		d.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+	return socket, d
+}
+
+func TestRunContainerCleansUp(t *testing.T) {
+	socket, daemon := startRunDaemon(t)
+	c, err := NewClient(Options{Host: "unix://" + socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	t.Setenv("CONTAINIX_TEST_TOKEN", "s3cret")
+	id, err := c.RunContainer(context.Background(), RunOptions{
+		Image:    "nginx",
+		Name:     "web",
+		Env:      []string{"MODE=prod", "CONTAINIX_TEST_TOKEN", "CONTAINIX_TEST_UNSET"},
+		Networks: []string{"back"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "network back not found") {
+		t.Fatalf("run error = %v, want the failed connect", err)
+	}
+	if id != "" {
+		t.Errorf("returned %q for a container that was removed", id)
+	}
+
+	daemon.mu.Lock()
+	defer daemon.mu.Unlock()
+	if !slices.Equal(daemon.removed, []string{"new1"}) {
+		t.Errorf("removed %q, want the created container", daemon.removed)
+	}
+	// Bare keys are passed through from the environment, like docker run -e
+	if want := []string{"MODE=prod", "CONTAINIX_TEST_TOKEN=s3cret"}; !slices.Equal(daemon.env, want) {
+		t.Errorf("env = %q, want %q", daemon.env, want)
+	}
+}
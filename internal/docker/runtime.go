@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/shubhamku044/containix/internal/compose"
+)
+
+// Runtime is every container operation the UI and the subcommands use.
+// Cancelling the context of a call stops it and its requests to the daemon.
+// Client implements it against a Docker daemon; the fake package provides
+// an in-memory implementation for tests and demos.
+type Runtime interface {
+	// ReadOnly reports whether mutating operations are disabled
+	ReadOnly() bool
+	// Ping checks that the daemon answers
+	Ping(ctx context.Context) error
+	// HostInfo describes the daemon and its connection
+	HostInfo(ctx context.Context) (HostInfo, error)
+	// Capabilities describes what the daemon can do
+	Capabilities(ctx context.Context) (Capabilities, error)
+
+	ListContainers(ctx context.Context) ([]Container, error)
+	InspectContainer(ctx context.Context, containerID string) (*ContainerDetails, error)
+	StartContainer(ctx context.Context, containerID string) error
+	StopContainer(ctx context.Context, containerID string) error
+	RestartContainer(ctx context.Context, containerID string) error
+	RunContainer(ctx context.Context, opts RunOptions) (string, error)
+	// RunOptionsOf rebuilds the options an existing container was run with
+	RunOptionsOf(ctx context.Context, containerID string) (RunOptions, error)
+	ContainerResources(ctx context.Context, containerID string) (Resources, error)
+	// UpdateResources changes the limits of a running container in place
+	UpdateResources(ctx context.Context, containerID string, r Resources) error
+	GetContainerStats(ctx context.Context, containerID string) (*ContainerStats, error)
+	GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error)
+	StreamContainerLogs(ctx context.Context, containerID string, opts LogOptions, stdout, stderr io.Writer) error
+
+	ListProjectContainers(ctx context.Context, project string) ([]Container, error)
+	StartProject(ctx context.Context, project string) error
+	StopProject(ctx context.Context, project string) error
+	RestartProject(ctx context.Context, project string) error
+	GetProjectLogs(ctx context.Context, project string, tail int) (string, error)
+	ServiceContainers(ctx context.Context, project string) (map[string][]Container, error)
+	ComposeUp(ctx context.Context, p *compose.Project) error
+	ComposeDown(ctx context.Context, p *compose.Project) error
+	RecreateService(ctx context.Context, p *compose.Project, service string) error
+	DiffService(ctx context.Context, p *compose.Project, service string) ([]ConfigDiff, error)
+
+	StatPath(ctx context.Context, containerID, containerPath string) (FileEntry, error)
+	ListDir(ctx context.Context, containerID, dir string) ([]FileEntry, bool, error)
+	ReadFile(ctx context.Context, containerID, filePath string, maxBytes int64) (string, error)
+	SaveToHost(ctx context.Context, containerID, srcPath, dst string, extract bool) error
+	UploadToContainer(ctx context.Context, containerID string, srcPaths []string, dstDir string, overwrite bool, progress UploadProgress) error
+
+	ListNetworks(ctx context.Context) ([]Network, error)
+	InspectNetwork(ctx context.Context, networkID string) (Network, error)
+	CreateNetwork(ctx context.Context, opts NetworkOptions) (string, error)
+	RemoveNetwork(ctx context.Context, networkID string) error
+	ConnectNetwork(ctx context.Context, networkID, containerID string, aliases []string) error
+	DisconnectNetwork(ctx context.Context, networkID, containerID string) error
+}
+
+var _ Runtime = (*Client)(nil)
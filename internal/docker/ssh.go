@@ -0,0 +1,285 @@
+package docker
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// defaultRemoteSocket is the daemon socket on an ssh:// host without a path
+const defaultRemoteSocket = "/var/run/docker.sock"
+
+// sshTimeout bounds connecting and authenticating to an SSH host
+const sshTimeout = 15 * time.Second
+
+// sshTunnel reaches a remote daemon's unix socket through an SSH connection.
+// The connection is opened on first use and opened again when it drops.
+type sshTunnel struct {
+	addr     string // host:port
+	socket   string // daemon socket on the remote host
+	user     string
+	hostKeys ssh.HostKeyCallback
+	keys     *sshKeys
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// newSSHTunnel prepares a tunnel for an ssh://[user@]host[:port][/socket]
+// address. Keys come from ssh-agent and the default identity files, host
+// keys are checked against ~/.ssh/known_hosts.
+func newSSHTunnel(endpoint string) (*sshTunnel, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh address %q: %w", endpoint, err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh address %q: no host", endpoint)
+	}
+
+	username := u.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("ssh %s: no user given and the current user is unknown", u.Host)
+		}
+		username = current.Username
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	socket := u.Path
+	if socket == "" || socket == "/" {
+		socket = defaultRemoteSocket
+	}
+
+	hostKeys, err := knownHostsCallback()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := loadSSHKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sshTunnel{
+		addr:     net.JoinHostPort(u.Hostname(), port),
+		socket:   socket,
+		user:     username,
+		hostKeys: hostKeys,
+		keys:     keys,
+	}, nil
+}
+
+// sshDir returns the user's ssh configuration directory
+func sshDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ssh")
+}
+
+// knownHostsCallback verifies host keys against ~/.ssh/known_hosts. There
+// is no trust on first use: an unknown host has to be added with ssh first.
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	path := filepath.Join(sshDir(), "known_hosts")
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: reading %s: %w", path, err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key of %s is not in %s; connect once with ssh to add it", hostname, path)
+			}
+			return fmt.Errorf("host key of %s does not match %s; the host may have been reinstalled or the connection intercepted", hostname, path)
+		}
+		return err
+	}, nil
+}
+
+// sshKeys are the keys offered to SSH hosts: those of a running ssh-agent,
+// then unencrypted default identity files
+type sshKeys struct {
+	agentSocket string
+	signers     []ssh.Signer
+}
+
+// loadSSHKeys reads the default identity files. The agent is only dialed
+// when connecting, so one restarted in the meantime is still used.
+func loadSSHKeys() (*sshKeys, error) {
+	keys := &sshKeys{agentSocket: os.Getenv("SSH_AUTH_SOCK")}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		raw, err := os.ReadFile(filepath.Join(sshDir(), name))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(raw)
+		if err != nil {
+			// Passphrase protected keys need the agent
+			slog.Debug("skipping ssh identity", "file", name, "error", err)
+			continue
+		}
+		keys.signers = append(keys.signers, signer)
+	}
+
+	if keys.agentSocket == "" && len(keys.signers) == 0 {
+		return nil, errors.New("ssh: no keys available; start ssh-agent or add an unencrypted key to ~/.ssh")
+	}
+	return keys, nil
+}
+
+// authMethods returns the methods for one connection and a function to
+// call once it is authenticated, which hangs up on the agent
+func (k *sshKeys) authMethods(ctx context.Context) ([]ssh.AuthMethod, func()) {
+	var methods []ssh.AuthMethod
+	done := func() {}
+	if k.agentSocket != "" {
+		var dialer net.Dialer
+		if conn, err := dialer.DialContext(ctx, "unix", k.agentSocket); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			done = func() { conn.Close() }
+		} else {
+			slog.Warn("ssh-agent not reachable", "socket", k.agentSocket, "error", err)
+		}
+	}
+	if len(k.signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(k.signers...))
+	}
+	return methods, done
+}
+
+// connect returns the open SSH connection, opening one if needed. The
+// lock is not held while connecting, so a slow host does not block
+// callers that only want to find out whether a connection is open.
+func (t *sshTunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err := t.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ssh %s: %w", t.addr, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		// Another caller connected first
+		client.Close()
+		return t.client, nil
+	}
+	slog.Info("ssh connected", "host", t.addr, "user", t.user)
+
+	// Forget the connection once it drops so the next dial opens a new one
+	go func() {
+		err := client.Wait()
+		slog.Warn("ssh connection closed", "host", t.addr, "error", err)
+		t.forget(client)
+	}()
+
+	t.client = client
+	return client, nil
+}
+
+// dial opens and authenticates a new SSH connection. The handshake gets the
+// same bounds as the dial: sshTimeout, or ctx when that ends sooner.
+func (t *sshTunnel) dial(ctx context.Context) (*ssh.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, sshTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// A cancelled ctx interrupts the handshake by moving the deadline up
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+
+	auth, hangUp := t.keys.authMethods(ctx)
+	defer hangUp()
+	if len(auth) == 0 {
+		conn.Close()
+		return nil, errors.New("no keys available; start ssh-agent or add an unencrypted key to ~/.ssh")
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.addr, &ssh.ClientConfig{
+		User:            t.user,
+		Auth:            auth,
+		HostKeyCallback: t.hostKeys,
+	})
+	if !stop() || err != nil {
+		conn.Close()
+		return nil, cmp.Or(ctx.Err(), err)
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// forget drops a connection if it is still the current one
+func (t *sshTunnel) forget(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		t.client = nil
+	}
+}
+
+// DialContext opens a connection to the remote daemon socket. It matches
+// http.Transport.DialContext; the network and address of the request are
+// ignored. A connection that dropped since the last dial is reopened once.
+func (t *sshTunnel) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		client, err := t.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := client.Dial("unix", t.socket)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) {
+			// The server answered, so the socket itself is the problem
+			return nil, fmt.Errorf("ssh %s: cannot reach %s: %w", t.addr, t.socket, err)
+		}
+		// The connection is gone, try again on a fresh one
+		client.Close()
+		t.forget(client)
+	}
+	return nil, fmt.Errorf("ssh %s: %w", t.addr, lastErr)
+}
+
+// Close closes the SSH connection
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	return err
+}
@@ -0,0 +1,307 @@
+package docker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshd is a stand-in SSH server that forwards unix socket channels, like
+// OpenSSH does for `ssh -L local:/var/run/docker.sock`
+type sshd struct {
+	addr    string
+	hostKey ssh.Signer
+
+	mu    sync.Mutex
+	conns []net.Conn
+	dials int
+}
+
+// startSSHD serves SSH on a local port and accepts the given user key
+func startSSHD(t *testing.T, userKey ssh.PublicKey) *sshd {
+	t.Helper()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if meta.User() == "tester" && string(key.Marshal()) == string(userKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key for %s", meta.User())
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := &sshd{addr: l.Addr().String(), hostKey: hostKey}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn, config)
+		}
+	}()
+	return s
+}
+
+func (s *sshd) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for ch := range chans {
+		if ch.ChannelType() != "direct-streamlocal@openssh.com" {
+			ch.Reject(ssh.UnknownChannelType, "only unix sockets are forwarded")
+			continue
+		}
+		var target struct {
+			SocketPath string
+			Reserved0  string
+			Reserved1  uint32
+		}
+		if err := ssh.Unmarshal(ch.ExtraData(), &target); err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		local, err := net.Dial("unix", target.SocketPath)
+		if err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		remote, requests, err := ch.Accept()
+		if err != nil {
+			local.Close()
+			continue
+		}
+		s.mu.Lock()
+		s.dials++
+		s.mu.Unlock()
+		go ssh.DiscardRequests(requests)
+		go func() {
+			io.Copy(local, remote)
+			local.Close()
+		}()
+		go func() {
+			io.Copy(remote, local)
+			remote.Close()
+		}()
+	}
+}
+
+// drop closes every open connection, like a network outage would
+func (s *sshd) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+// startDaemon serves a minimal Docker API on a unix socket
+func startDaemon(t *testing.T, dir string) string {
+	t.Helper()
+	socket := filepath.Join(dir, "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.41/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"Id":"abc123","Names":["/web"],"State":"running"}]`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+	return socket
+}
+
+// sshEnv points HOME and SSH_AUTH_SOCK at a temporary ~/.ssh and an agent
+// holding a fresh key, and returns that key
+func sshEnv(t *testing.T, dir string) ssh.PublicKey {
+	t.Helper()
+	t.Setenv("HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, ".ssh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(dir, "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer.PublicKey()
+}
+
+func trustHost(t *testing.T, dir string, s *sshd) {
+	t.Helper()
+	line := knownhosts.Line([]string{knownhosts.Normalize(s.addr)}, s.hostKey.PublicKey())
+	if err := os.WriteFile(filepath.Join(dir, ".ssh", "known_hosts"), []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSSHClient(t *testing.T) {
+	dir := t.TempDir()
+	userKey := sshEnv(t, dir)
+	socket := startDaemon(t, dir)
+	server := startSSHD(t, userKey)
+	trustHost(t, dir, server)
+
+	c, err := NewClient(Options{Host: "ssh://tester@" + server.addr + socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	containers, err := c.ListContainers(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(containers) != 1 || containers[0].Name != "web" {
+		t.Fatalf("containers = %+v, want web", containers)
+	}
+
+	// Requests after the connection dropped reconnect transparently
+	server.drop()
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("ping after drop: %v", err)
+	}
+}
+
+func TestSSHUnknownHost(t *testing.T) {
+	dir := t.TempDir()
+	userKey := sshEnv(t, dir)
+	socket := startDaemon(t, dir)
+	server := startSSHD(t, userKey)
+	if err := os.WriteFile(filepath.Join(dir, ".ssh", "known_hosts"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClient(Options{Host: "ssh://tester@" + server.addr + socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	err = c.Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not in") {
+		t.Fatalf("ping error = %v, want unknown host key", err)
+	}
+}
+
+func TestSSHWrongUser(t *testing.T) {
+	dir := t.TempDir()
+	userKey := sshEnv(t, dir)
+	socket := startDaemon(t, dir)
+	server := startSSHD(t, userKey)
+	trustHost(t, dir, server)
+
+	c, err := NewClient(Options{Host: "ssh://intruder@" + server.addr + socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+		t.Fatalf("ping error = %v, want an authentication failure", err)
+	}
+}
+
+func TestSSHHandshakeHonoursContext(t *testing.T) {
+	dir := t.TempDir()
+	sshEnv(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, ".ssh", "known_hosts"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A host that accepts the connection and never answers
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	tunnel, err := newSSHTunnel("ssh://tester@" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := tunnel.DialContext(ctx, "", ""); err == nil {
+		t.Fatal("connected to a host that never answered")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("gave up after %s, want the context's 200ms", elapsed)
+	}
+}
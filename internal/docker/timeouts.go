@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timeouts bound how long each kind of daemon call may take. Zero means no
+// limit; cancelling the context passed to a call always stops it.
+type Timeouts struct {
+	Query   time.Duration // lists, inspects, stats, logs and host information
+	Action  time.Duration // start, stop and restart, which wait for the stop grace period, and network and limit changes
+	Compose time.Duration // compose up, down and recreate, and running containers, which may pull images
+	Files   time.Duration // reading, downloading and uploading container files
+	Connect time.Duration // reaching a daemon picked while running
+}
+
+// ErrTimeout is returned when the daemon does not answer within the
+// operation's timeout
+var ErrTimeout = errors.New("the daemon did not answer")
+
+// limit bounds ctx by d. The returned func releases the context and turns
+// a missed deadline into ErrTimeout; defer it with the call's error:
+//
+//	ctx, done := limit(ctx, c.timeouts.Query)
+//	defer done(&err)
+func limit(ctx context.Context, d time.Duration) (context.Context, func(*error)) {
+	if d <= 0 {
+		return ctx, func(*error) {}
+	}
+	bounded, cancel := context.WithTimeout(ctx, d)
+	return bounded, func(err *error) {
+		expired := errors.Is(bounded.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+		if *err != nil && expired && errors.Is(*err, context.DeadlineExceeded) {
+			*err = fmt.Errorf("%w within %s", ErrTimeout, d)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startSlowDaemon answers pings at once and the container list only after
+// the request is abandoned
+func startSlowDaemon(t *testing.T) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.41/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+	return socket
+}
+
+func TestClientTimeouts(t *testing.T) {
+	socket := startSlowDaemon(t)
+	c, err := NewClient(Options{Host: "unix://" + socket, Timeouts: Timeouts{Query: 50 * time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	start := time.Now()
+	_, err = c.ListContainers(context.Background())
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("list error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("list took %s with a 50ms timeout", elapsed)
+	}
+
+	// Cancelling is not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = c.ListContainers(ctx)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+		t.Fatalf("list error = %v, want context.Canceled", err)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	c, err := Connect(context.Background(), Options{Host: "unix://" + startSlowDaemon(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	// A daemon that accepts connections and never answers the ping
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	opts := Options{Host: "unix://" + socket, Timeouts: Timeouts{Connect: 50 * time.Millisecond}}
+	start := time.Now()
+	if _, err := Connect(context.Background(), opts); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("connecting took %s with a 50ms timeout", elapsed)
+	}
+
+	// Closing the UI gives up on the daemon, whatever the timeout
+	opts.Timeouts.Connect = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := Connect(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,212 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TLSFiles are the certificates used for a tcp:// daemon with mutual TLS
+type TLSFiles struct {
+	CA     string // CA that signed the daemon's certificate
+	Cert   string // client certificate
+	Key    string // client key
+	Verify bool   // check the daemon's certificate against CA
+}
+
+// IsZero reports whether no files are set
+func (f TLSFiles) IsZero() bool {
+	return f.CA == "" && f.Cert == "" && f.Key == ""
+}
+
+// CertInfo describes one certificate of a connection
+type CertInfo struct {
+	Role      string // CA, client or daemon
+	Path      string // empty for the daemon's certificate
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// ExpiresIn is the time left until the certificate expires, negative once
+// it has
+func (c CertInfo) ExpiresIn(now time.Time) time.Duration {
+	return c.NotAfter.Sub(now)
+}
+
+// filesIn returns the docker CLI's file names in a certificate directory
+func filesIn(dir string, verify bool) TLSFiles {
+	return TLSFiles{
+		CA:     filepath.Join(dir, "ca.pem"),
+		Cert:   filepath.Join(dir, "cert.pem"),
+		Key:    filepath.Join(dir, "key.pem"),
+		Verify: verify,
+	}
+}
+
+// resolveTLS picks the certificates for a tcp:// host: the ones configured
+// for the address, then those stored with the docker context, then
+// DOCKER_CERT_PATH and DOCKER_TLS_VERIFY. It returns nil for plain TCP.
+func resolveTLS(host, contextName string, configured map[string]TLSFiles) *TLSFiles {
+	if !strings.HasPrefix(host, "tcp://") {
+		return nil
+	}
+	if files, ok := configured[host]; ok && !files.IsZero() {
+		return &files
+	}
+
+	if contextName != "" && contextName != DefaultContext {
+		// The CLI keeps a context's certificates next to its metadata
+		sum := sha256.Sum256([]byte(contextName))
+		dir := filepath.Join(configDir(), "contexts", "tls", hex.EncodeToString(sum[:]), "docker")
+		if _, err := os.Stat(dir); err == nil {
+			meta, _ := readContextMeta(filepath.Join(configDir(), "contexts", "meta", hex.EncodeToString(sum[:]), "meta.json"))
+			files := filesIn(dir, !meta.Endpoints["docker"].SkipTLSVerify)
+			return &files
+		}
+	}
+
+	certPath := os.Getenv("DOCKER_CERT_PATH")
+	verify := os.Getenv("DOCKER_TLS_VERIFY") != ""
+	if certPath == "" && verify {
+		certPath = configDir()
+	}
+	if certPath == "" {
+		return nil
+	}
+	files := filesIn(certPath, verify)
+	return &files
+}
+
+// config loads the files into a TLS configuration
+func (f TLSFiles) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: !f.Verify,
+	}
+
+	if f.CA != "" {
+		raw, err := os.ReadFile(f.CA)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, fmt.Errorf("CA certificate %s contains no PEM certificate", f.CA)
+		}
+		cfg.RootCAs = pool
+	} else if f.Verify {
+		return nil, errors.New("verifying the daemon needs a CA certificate")
+	}
+
+	if f.Cert != "" || f.Key != "" {
+		if f.Cert == "" || f.Key == "" {
+			return nil, errors.New("a client certificate needs both a cert and a key file")
+		}
+		pair, err := tls.LoadX509KeyPair(f.Cert, f.Key)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate %s with key %s: %w", f.Cert, f.Key, err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
+
+// tlsHTTPClient returns an HTTP client that talks TLS to the daemon
+func tlsHTTPClient(cfg *tls.Config) *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+}
+
+// Certificates describes the CA and client certificates on disk
+func (f TLSFiles) Certificates() ([]CertInfo, error) {
+	var certs []CertInfo
+	for _, file := range []struct{ role, path string }{{"CA", f.CA}, {"client", f.Cert}} {
+		if file.path == "" {
+			continue
+		}
+		raw, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(raw)
+		if block == nil {
+			return nil, fmt.Errorf("%s contains no PEM certificate", file.path)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.path, err)
+		}
+		certs = append(certs, certInfo(file.role, file.path, cert))
+	}
+	return certs, nil
+}
+
+// daemonCertificate connects to the daemon and describes the certificate
+// it presents
+func daemonCertificate(ctx context.Context, host string, cfg *tls.Config) (CertInfo, error) {
+	addr := strings.TrimPrefix(host, "tcp://")
+	dialer := tls.Dialer{NetDialer: &net.Dialer{Timeout: 10 * time.Second}, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return CertInfo{}, explainTLS(host, err)
+	}
+	defer conn.Close()
+
+	peers := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return CertInfo{}, fmt.Errorf("%s presented no certificate", host)
+	}
+	return certInfo("daemon", "", peers[0]), nil
+}
+
+func certInfo(role, path string, cert *x509.Certificate) CertInfo {
+	return CertInfo{
+		Role:      role,
+		Path:      path,
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}
+}
+
+// explainTLS rewrites TLS handshake failures into something actionable.
+// Other errors are returned unchanged.
+func explainTLS(host string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var unknownCA x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var alert tls.AlertError
+	var recordHeader tls.RecordHeaderError
+	switch {
+	case errors.As(err, &unknownCA):
+		return fmt.Errorf("TLS to %s: the daemon's certificate is not signed by the configured CA", host)
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return fmt.Errorf("TLS to %s: the daemon's certificate is expired or not yet valid (%s)", host, invalid.Detail)
+	case errors.As(err, &hostname):
+		return fmt.Errorf("TLS to %s: the daemon's certificate is not valid for this address: %w", host, hostname)
+	case errors.As(err, &alert) || strings.Contains(err.Error(), "remote error: tls:"):
+		return fmt.Errorf("TLS to %s: the daemon rejected the client certificate (%w); check the cert and key files", host, err)
+	case errors.As(err, &recordHeader) || strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		return fmt.Errorf("TLS to %s: the daemon does not speak TLS on this port", host)
+	case strings.Contains(strings.ToLower(err.Error()), "client sent an http request to an https server"):
+		return fmt.Errorf("%s requires TLS; set the CA, cert and key for it or DOCKER_CERT_PATH", host)
+	}
+	return err
+}
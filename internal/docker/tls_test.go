@@ -0,0 +1,247 @@
+package docker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pki is a CA with a daemon and a client certificate written as the docker
+// CLI expects them: ca.pem, cert.pem and key.pem
+type pki struct {
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	daemon tls.Certificate
+	dir    string
+}
+
+func newPKI(t *testing.T, name string) *pki {
+	t.Helper()
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name + " CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+
+	p := &pki{ca: ca, caKey: caKey, dir: t.TempDir()}
+	writePEM(t, filepath.Join(p.dir, "ca.pem"), "CERTIFICATE", der)
+
+	daemonDER, daemonKey := p.issue(t, "daemon", x509.ExtKeyUsageServerAuth, 90*24*time.Hour)
+	p.daemon = tls.Certificate{Certificate: [][]byte{daemonDER}, PrivateKey: daemonKey}
+
+	clientDER, clientKey := p.issue(t, "client", x509.ExtKeyUsageClientAuth, 10*24*time.Hour)
+	writePEM(t, filepath.Join(p.dir, "cert.pem"), "CERTIFICATE", clientDER)
+	keyDER, _ := x509.MarshalECPrivateKey(clientKey)
+	writePEM(t, filepath.Join(p.dir, "key.pem"), "EC PRIVATE KEY", keyDER)
+	return p
+}
+
+func (p *pki) issue(t *testing.T, name string, usage x509.ExtKeyUsage, valid time.Duration) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(valid),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, key
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSDaemon serves a minimal Docker API that requires a client
+// certificate signed by the pki's CA
+func startTLSDaemon(t *testing.T, p *pki) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.43")
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.41/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Version":"24.0.7","ApiVersion":"1.43","Os":"linux","Arch":"arm64"}`))
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(p.ca)
+	server := httptest.NewUnstartedServer(mux)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{p.daemon},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return "tcp://" + server.Listener.Addr().String()
+}
+
+func TestTLSClient(t *testing.T) {
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	p := newPKI(t, "build")
+	host := startTLSDaemon(t, p)
+
+	c, err := NewClient(Options{Host: host, TLS: map[string]TLSFiles{host: filesIn(p.dir, true)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	info, err := c.HostInfo(context.Background())
+	if err != nil {
+		t.Fatalf("host info: %v", err)
+	}
+	if info.ServerVersion != "24.0.7" || len(info.CertErrors) > 0 {
+		t.Fatalf("info = %+v", info)
+	}
+	var roles []string
+	for _, cert := range info.Certificates {
+		roles = append(roles, cert.Role)
+	}
+	if got := strings.Join(roles, ","); got != "CA,client,daemon" {
+		t.Fatalf("certificates = %s, want CA,client,daemon", got)
+	}
+	if left := info.Certificates[1].ExpiresIn(time.Now()); left > 10*24*time.Hour || left < 9*24*time.Hour {
+		t.Fatalf("client certificate expires in %s, want about 10 days", left)
+	}
+}
+
+func TestTLSFromEnvironment(t *testing.T) {
+	p := newPKI(t, "build")
+	host := startTLSDaemon(t, p)
+	t.Setenv("DOCKER_CERT_PATH", p.dir)
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+
+	c, err := NewClient(Options{Host: host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+}
+
+func TestTLSHandshakeErrors(t *testing.T) {
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	p := newPKI(t, "build")
+	host := startTLSDaemon(t, p)
+	other := newPKI(t, "other")
+
+	tests := []struct {
+		name  string
+		files TLSFiles
+		want  string
+	}{
+		{"unknown CA", filesIn(other.dir, true), "not signed by the configured CA"},
+		{"no client certificate", TLSFiles{CA: filepath.Join(p.dir, "ca.pem"), Verify: true}, "rejected the client certificate"},
+		{"client certificate of another CA", TLSFiles{
+			CA:     filepath.Join(p.dir, "ca.pem"),
+			Cert:   filepath.Join(other.dir, "cert.pem"),
+			Key:    filepath.Join(other.dir, "key.pem"),
+			Verify: true,
+		}, "rejected the client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(Options{Host: host, TLS: map[string]TLSFiles{host: tt.files}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ping error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// A TLS port without TLS settings
+	c, err := NewClient(Options{Host: host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "requires TLS") {
+		t.Fatalf("ping error = %v, want a hint to configure TLS", err)
+	}
+}
+
+func TestTLSMismatchedKey(t *testing.T) {
+	p := newPKI(t, "build")
+	other := newPKI(t, "other")
+	_, err := NewClient(Options{Host: "tcp://127.0.0.1:2376", TLS: map[string]TLSFiles{"tcp://127.0.0.1:2376": {
+		CA:     filepath.Join(p.dir, "ca.pem"),
+		Cert:   filepath.Join(p.dir, "cert.pem"),
+		Key:    filepath.Join(other.dir, "key.pem"),
+		Verify: true,
+	}}})
+	if err == nil || !strings.Contains(err.Error(), "loading client certificate") {
+		t.Fatalf("error = %v, want the key pair to be rejected", err)
+	}
+}
+
+func TestDaemonCertificateHonoursContext(t *testing.T) {
+	// A daemon that accepts the connection and never starts the handshake
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := daemonCertificate(ctx, "tcp://"+l.Addr().String(), &tls.Config{}); err == nil {
+		t.Fatal("read a certificate from a daemon that never answered")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %s, want the context's 50ms", elapsed)
+	}
+}
@@ -0,0 +1,374 @@
+// Package keymap is the registry of every remappable key binding. Views look
+// bindings up by scope and action name instead of matching key strings.
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Scope groups the bindings that are active on one screen or pane
+type Scope string
+
+const (
+	Global     Scope = "global"     // everywhere
+	Main       Scope = "main"       // the main screen, whichever pane has focus
+	Containers Scope = "containers" // the container list
+	Logs       Scope = "logs"       // the log pane
+	Files      Scope = "files"      // the file browser
+	Viewer     Scope = "viewer"     // a file opened in the file browser
+	Upload     Scope = "upload"     // picking local files to upload
+	Networks   Scope = "networks"   // the networks screen
+	Compose    Scope = "compose"    // the compose screen
+	Detail     Scope = "detail"     // the container detail screen
+	Topology   Scope = "topology"   // the topology diagram
+	Contexts   Scope = "contexts"   // the docker context picker
+	Host       Scope = "host"       // the host details screen
+	Run        Scope = "run"        // the run container wizard
+	Export     Scope = "export"     // a container exported as a command or compose service
+	Form       Scope = "form"       // text inputs and pickers
+	Confirm    Scope = "confirm"    // yes/no prompts
+)
+
+// scopes lists every scope in display order with a title for the help overlay
+var scopes = []struct {
+	scope Scope
+	title string
+}{
+	{Global, "Global"},
+	{Main, "Main screen"},
+	{Containers, "Containers"},
+	{Logs, "Logs"},
+	{Files, "Files"},
+	{Viewer, "File viewer"},
+	{Upload, "Upload"},
+	{Networks, "Networks"},
+	{Compose, "Compose"},
+	{Detail, "Container detail"},
+	{Topology, "Topology"},
+	{Contexts, "Docker contexts"},
+	{Host, "Host details"},
+	{Run, "Run container"},
+	{Export, "Export container"},
+	{Form, "Forms"},
+	{Confirm, "Confirmations"},
+}
+
+// Title returns a human readable name for the scope
+func (s Scope) Title() string {
+	for _, sc := range scopes {
+		if sc.scope == s {
+			return sc.title
+		}
+	}
+	return string(s)
+}
+
+// sharesKeysWith lists the scopes that are active at the same time as a
+// scope, so a key may not be bound in both
+func (s Scope) sharesKeysWith() []Scope {
+	switch s {
+	case Global:
+		return nil
+	case Containers, Logs:
+		return []Scope{Global, Main}
+	}
+	return []Scope{Global}
+}
+
+type action struct {
+	scope Scope
+	name  string
+	keys  []string
+	help  string
+}
+
+// actions are the defaults, in the order the help overlay lists them
+var actions = []action{
+	{Global, "help", []string{"?"}, "toggle help"},
+
+	{Main, "focus", []string{"tab"}, "switch pane"},
+	{Main, "contexts", []string{"H"}, "switch context"},
+	{Main, "host", []string{"D"}, "host details"},
+	{Main, "quit", []string{"q"}, "quit"},
+
+	{Containers, "select", []string{"enter", " "}, "select / expand"},
+	{Containers, "stop", []string{"s"}, "stop"},
+	{Containers, "start", []string{"t"}, "start"},
+	{Containers, "restart", []string{"x"}, "restart"},
+	{Containers, "logs", []string{"l"}, "logs"},
+	{Containers, "collapse", []string{"c"}, "collapse"},
+	{Containers, "collapse_all", []string{"C"}, "collapse all"},
+	{Containers, "compose", []string{"p"}, "compose"},
+	{Containers, "open_compose", []string{"O"}, "open compose file"},
+	{Containers, "files", []string{"f"}, "files"},
+	{Containers, "upload", []string{"u"}, "upload"},
+	{Containers, "inspect", []string{"i"}, "inspect"},
+	{Containers, "networks", []string{"n"}, "networks"},
+	{Containers, "topology", []string{"T"}, "topology"},
+	{Containers, "run", []string{"a"}, "run container"},
+	{Containers, "export", []string{"e"}, "export as run / compose"},
+	{Containers, "refresh", []string{"r"}, "refresh"},
+	{Containers, "hosts", []string{"h"}, "filter by host"},
+
+	{Logs, "up", []string{"k", "up"}, "scroll up"},
+	{Logs, "down", []string{"j", "down"}, "scroll down"},
+	{Logs, "page_up", []string{"b", "pgup"}, "page up"},
+	{Logs, "page_down", []string{"f", "pgdown", " "}, "page down"},
+	{Logs, "half_up", []string{"u", "ctrl+u"}, "half page up"},
+	{Logs, "half_down", []string{"d", "ctrl+d"}, "half page down"},
+
+	{Files, "back", []string{"q", "esc"}, "close"},
+	{Files, "open", []string{"enter", "l", "right"}, "open"},
+	{Files, "parent", []string{"backspace", "h", "left"}, "parent directory"},
+	{Files, "download", []string{"d"}, "download"},
+	{Files, "upload", []string{"u"}, "upload here"},
+	{Files, "toggle_format", []string{"tab"}, "tar / extracted"},
+
+	{Viewer, "back", []string{"q", "esc", "backspace", "h", "left"}, "close file"},
+	{Viewer, "down", []string{"j", "down"}, "scroll down"},
+	{Viewer, "up", []string{"k", "up"}, "scroll up"},
+	{Viewer, "top", []string{"g", "home"}, "top"},
+	{Viewer, "bottom", []string{"G", "end"}, "bottom"},
+	{Viewer, "page_down", []string{"f", "pgdown", " "}, "page down"},
+	{Viewer, "page_up", []string{"b", "pgup"}, "page up"},
+	{Viewer, "next_section", []string{"n"}, "next section"},
+	{Viewer, "prev_section", []string{"N"}, "previous section"},
+	{Viewer, "download", []string{"d"}, "download"},
+
+	{Upload, "back", []string{"q", "esc"}, "cancel"},
+	{Upload, "mark", []string{" "}, "mark"},
+	{Upload, "open", []string{"enter", "l", "right"}, "open directory"},
+	{Upload, "parent", []string{"backspace", "h", "left"}, "parent directory"},
+	{Upload, "continue", []string{"c", "tab"}, "continue"},
+
+	{Networks, "back", []string{"q", "esc"}, "close"},
+	{Networks, "refresh", []string{"r"}, "refresh"},
+	{Networks, "create", []string{"c"}, "create"},
+	{Networks, "remove", []string{"x", "delete"}, "remove"},
+
+	{Compose, "back", []string{"q", "esc"}, "close"},
+	{Compose, "down", []string{"j", "down"}, "next service"},
+	{Compose, "up", []string{"k", "up"}, "previous service"},
+	{Compose, "scroll_down", []string{"J", "pgdown"}, "scroll details"},
+	{Compose, "scroll_up", []string{"K", "pgup"}, "scroll details up"},
+	{Compose, "diff", []string{"d"}, "diff"},
+	{Compose, "project_up", []string{"U"}, "up"},
+	{Compose, "project_down", []string{"D"}, "down"},
+	{Compose, "recreate", []string{"R"}, "recreate"},
+	{Compose, "open", []string{"o"}, "open file"},
+	{Compose, "refresh", []string{"r"}, "reload"},
+
+	{Detail, "back", []string{"q", "esc"}, "close"},
+	{Detail, "next_tab", []string{"tab", "right"}, "next tab"},
+	{Detail, "prev_tab", []string{"shift+tab", "left"}, "previous tab"},
+	{Detail, "down", []string{"j", "down"}, "down"},
+	{Detail, "up", []string{"k", "up"}, "up"},
+	{Detail, "connect", []string{"c"}, "connect network"},
+	{Detail, "disconnect", []string{"d", "x"}, "disconnect network"},
+	{Detail, "edit", []string{"e"}, "edit limits"},
+	{Detail, "refresh", []string{"r"}, "refresh"},
+
+	{Topology, "back", []string{"q", "esc"}, "close"},
+	{Topology, "down", []string{"j", "down"}, "down"},
+	{Topology, "up", []string{"k", "up"}, "up"},
+	{Topology, "top", []string{"g", "home"}, "top"},
+	{Topology, "bottom", []string{"G", "end"}, "bottom"},
+	{Topology, "mark", []string{"a"}, "check reachability"},
+	{Topology, "focus", []string{"enter"}, "select container"},
+	{Topology, "refresh", []string{"r"}, "refresh"},
+
+	{Contexts, "back", []string{"q", "esc"}, "close"},
+	{Contexts, "switch", []string{"enter"}, "switch"},
+	{Contexts, "host", []string{"a"}, "enter host"},
+	{Contexts, "refresh", []string{"r"}, "refresh"},
+
+	{Host, "back", []string{"q", "esc"}, "close"},
+	{Host, "refresh", []string{"r"}, "refresh"},
+
+	{Run, "create", []string{"enter"}, "create and start"},
+	{Run, "save", []string{"s"}, "save as preset"},
+	{Run, "edit", []string{"esc", "e"}, "edit"},
+	{Run, "back", []string{"q"}, "close"},
+
+	{Export, "back", []string{"q", "esc"}, "close"},
+	{Export, "next_tab", []string{"tab", "right"}, "docker run / compose"},
+	{Export, "prev_tab", []string{"shift+tab", "left"}, "previous tab"},
+	{Export, "copy", []string{"y", "c"}, "copy to clipboard"},
+	{Export, "down", []string{"j", "down"}, "scroll down"},
+	{Export, "up", []string{"k", "up"}, "scroll up"},
+
+	{Form, "submit", []string{"enter"}, "submit"},
+	{Form, "cancel", []string{"esc"}, "cancel"},
+	{Form, "next", []string{"tab", "down", "ctrl+n"}, "next"},
+	{Form, "prev", []string{"shift+tab", "up", "ctrl+p"}, "previous"},
+
+	{Confirm, "yes", []string{"y", "Y"}, "confirm"},
+	{Confirm, "no", []string{"n", "N", "esc"}, "cancel"},
+}
+
+// KeyMap holds the active bindings
+type KeyMap struct {
+	bindings map[Scope]map[string]key.Binding
+}
+
+// Default returns the built-in bindings
+func Default() KeyMap {
+	k, _ := New(nil)
+	return k
+}
+
+// New applies user overrides, keyed by scope and action name, on top of the
+// defaults. Unknown names and keys bound twice in scopes that are active
+// together are reported.
+func New(overrides map[string]map[string][]string) (KeyMap, error) {
+	var problems []string
+	km := KeyMap{bindings: map[Scope]map[string]key.Binding{}}
+	for _, a := range actions {
+		if km.bindings[a.scope] == nil {
+			km.bindings[a.scope] = map[string]key.Binding{}
+		}
+		keys := a.keys
+		if custom, ok := overrides[string(a.scope)][a.name]; ok {
+			keys = custom
+		}
+		km.bindings[a.scope][a.name] = newBinding(keys, a.help)
+	}
+
+	for _, scope := range sortedKeys(overrides) {
+		if _, ok := km.bindings[Scope(scope)]; !ok {
+			problems = append(problems, fmt.Sprintf("keymap.%s: unknown scope (want one of %s)", scope, strings.Join(scopeNames(), ", ")))
+			continue
+		}
+		for _, name := range sortedKeys(overrides[scope]) {
+			if _, ok := km.bindings[Scope(scope)][name]; !ok {
+				problems = append(problems, fmt.Sprintf("keymap.%s.%s: unknown action (want one of %s)", scope, name, strings.Join(km.actionNames(Scope(scope)), ", ")))
+				continue
+			}
+			if len(overrides[scope][name]) == 0 {
+				problems = append(problems, fmt.Sprintf("keymap.%s.%s: at least one key is required", scope, name))
+			}
+		}
+	}
+
+	problems = append(problems, km.conflicts()...)
+	if len(problems) > 0 {
+		return Default(), errors.New(strings.Join(problems, "; "))
+	}
+	return km, nil
+}
+
+func newBinding(keys []string, help string) key.Binding {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = k
+		if k == " " {
+			labels[i] = "space"
+		}
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(labels, "/"), help))
+}
+
+// conflicts finds keys bound to two actions that can fire on the same screen
+func (k KeyMap) conflicts() []string {
+	var problems []string
+	seen := map[string]bool{}
+	for _, a := range actions {
+		for _, other := range append([]Scope{a.scope}, a.scope.sharesKeysWith()...) {
+			for _, b := range actions {
+				if b.scope != other || (b.scope == a.scope && b.name == a.name) {
+					continue
+				}
+				for _, key := range k.bindings[a.scope][a.name].Keys() {
+					for _, otherKey := range k.bindings[b.scope][b.name].Keys() {
+						if key != otherKey {
+							continue
+						}
+						// Report each pair once
+						id := fmt.Sprintf("%s.%s|%s.%s|%s", a.scope, a.name, b.scope, b.name, key)
+						reverse := fmt.Sprintf("%s.%s|%s.%s|%s", b.scope, b.name, a.scope, a.name, key)
+						if seen[id] || seen[reverse] {
+							continue
+						}
+						seen[id] = true
+						problems = append(problems, fmt.Sprintf("keymap.%s.%s: %q is already bound to %s.%s", a.scope, a.name, key, b.scope, b.name))
+					}
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// Get returns the binding of an action
+func (k KeyMap) Get(scope Scope, name string) key.Binding {
+	return k.bindings[scope][name]
+}
+
+// Matches reports whether msg triggers the action
+func (k KeyMap) Matches(msg tea.KeyMsg, scope Scope, name string) bool {
+	return key.Matches(msg, k.Get(scope, name))
+}
+
+// Action returns the name of the action in scope that msg triggers, or ""
+func (k KeyMap) Action(msg tea.KeyMsg, scope Scope) string {
+	for _, a := range actions {
+		if a.scope == scope && key.Matches(msg, k.Get(scope, a.name)) {
+			return a.name
+		}
+	}
+	return ""
+}
+
+// Bindings returns every binding of a scope in display order
+func (k KeyMap) Bindings(scope Scope) []key.Binding {
+	var result []key.Binding
+	for _, a := range actions {
+		if a.scope == scope {
+			result = append(result, k.Get(scope, a.name))
+		}
+	}
+	return result
+}
+
+// Short formats the given actions of a scope for a one-line help text
+func (k KeyMap) Short(scope Scope, names ...string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		h := k.Get(scope, name).Help()
+		parts[i] = h.Key + ": " + h.Desc
+	}
+	return strings.Join(parts, " • ")
+}
+
+func (k KeyMap) actionNames(scope Scope) []string {
+	var names []string
+	for _, a := range actions {
+		if a.scope == scope {
+			names = append(names, a.name)
+		}
+	}
+	return names
+}
+
+func scopeNames() []string {
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = string(s.scope)
+	}
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package keymap
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestDefaultsHaveNoConflicts(t *testing.T) {
+	if _, err := New(nil); err != nil {
+		t.Fatalf("the defaults conflict: %v", err)
+	}
+	for _, a := range actions {
+		if a.scope.Title() == string(a.scope) {
+			t.Errorf("scope %s of %s has no title", a.scope, a.name)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	km, err := New(map[string]map[string][]string{
+		"containers": {"stop": {"S", "ctrl+s"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := km.Action(runes("S"), Containers); got != "stop" {
+		t.Errorf("S triggers %q, want stop", got)
+	}
+	// The default key is replaced, not added to
+	if got := km.Action(runes("s"), Containers); got != "" {
+		t.Errorf("s still triggers %q", got)
+	}
+	if h := km.Get(Containers, "stop").Help(); h.Key != "S/ctrl+s" || h.Desc != "stop" {
+		t.Errorf("help = %+v", h)
+	}
+	// Other scopes keep their defaults
+	if got := km.Action(runes("d"), Files); got != "download" {
+		t.Errorf("d in files triggers %q", got)
+	}
+}
+
+func TestSharesKeysWith(t *testing.T) {
+	for _, tc := range []struct {
+		scope Scope
+		want  []Scope
+	}{
+		{Global, nil},
+		{Containers, []Scope{Global, Main}},
+		{Logs, []Scope{Global, Main}},
+		{Files, []Scope{Global}},
+		{Confirm, []Scope{Global}},
+	} {
+		if got := tc.scope.sharesKeysWith(); !slices.Equal(got, tc.want) {
+			t.Errorf("%s shares keys with %v, want %v", tc.scope, got, tc.want)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		overrides map[string]map[string][]string
+		want      []string // every conflict, none when the keymap is valid
+	}{
+		{"same scope", map[string]map[string][]string{"containers": {"stop": {"x"}}}, []string{`keymap.containers.stop: "x" is already bound to containers.restart`}},
+		{"main screen under the list", map[string]map[string][]string{"containers": {"stop": {"q"}}}, []string{`"q" is already bound to main.quit`}},
+		{"global", map[string]map[string][]string{"files": {"download": {"?"}}}, []string{`"?" is already bound to global.help`}},
+		// A global key collides with every screen that uses it
+		{"global override", map[string]map[string][]string{"global": {"help": {"s"}}}, []string{
+			`keymap.containers.stop: "s" is already bound to global.help`,
+			`keymap.run.save: "s" is already bound to global.help`,
+		}},
+		// The file browser replaces the main screen, so it may reuse the list's keys
+		{"separate screens", map[string]map[string][]string{"files": {"download": {"t"}}}, nil},
+		{"swapped keys", map[string]map[string][]string{"containers": {"stop": {"x"}, "restart": {"s"}}}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			km, err := New(tc.overrides)
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected conflict: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("no conflict reported")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want %q", err, want)
+				}
+			}
+			// Each pair is reported once
+			if n := strings.Count(err.Error(), "already bound"); n != len(tc.want) {
+				t.Errorf("%d conflicts reported: %v", n, err)
+			}
+			// A rejected keymap falls back to the defaults
+			if got := km.Action(runes("s"), Containers); got != "stop" {
+				t.Errorf("s triggers %q after a rejected override", got)
+			}
+		})
+	}
+}
+
+func TestUnknownNames(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		overrides map[string]map[string][]string
+		want      []string
+	}{
+		{"scope", map[string]map[string][]string{"container": {"stop": {"S"}}}, []string{"keymap.container: unknown scope", "containers"}},
+		{"action", map[string]map[string][]string{"containers": {"kill": {"K"}}}, []string{"keymap.containers.kill: unknown action", "stop", "restart"}},
+		{"action of another scope", map[string]map[string][]string{"logs": {"stop": {"S"}}}, []string{"keymap.logs.stop: unknown action"}},
+		{"no keys", map[string]map[string][]string{"containers": {"stop": {}}}, []string{"keymap.containers.stop: at least one key is required"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.overrides)
+			if err == nil {
+				t.Fatal("accepted")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package shellwords
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  sleep   infinity ", []string{"sleep", "infinity"}},
+		{"a\tb\nc", []string{"a", "b", "c"}},
+		{`sh -c "echo $HOME"`, []string{"sh", "-c", "echo $HOME"}},
+		{`echo 'a "b"' c`, []string{"echo", `a "b"`, "c"}},
+		{`'GREETING=hello world' DEBUG=1`, []string{"GREETING=hello world", "DEBUG=1"}},
+		{`--name=""`, []string{"--name="}},
+		{`a\ b "c\"d" 'e\f'`, []string{"a b", `c"d`, `e\f`}},
+		{`x"y"'z'`, []string{"xyz"}},
+	} {
+		got, err := Split(tc.in)
+		if err != nil || !slices.Equal(got, tc.want) {
+			t.Errorf("Split(%q) = %q, %v, want %q", tc.in, got, err, tc.want)
+		}
+	}
+
+	for _, in := range []string{`echo "unterminated`, `echo 'unterminated`, `trailing\`} {
+		if words, err := Split(in); err == nil {
+			t.Errorf("Split(%q) = %q, want an error", in, words)
+		}
+	}
+}
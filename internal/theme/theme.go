@@ -0,0 +1,251 @@
+// Package theme holds the color palettes the UI draws from. Views never name
+// colors directly; they ask the current theme for a role such as Accent or
+// Error so the whole UI can switch between dark, light and colorless
+// terminals.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Built-in theme names
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	NoColor      = "no-color"
+)
+
+// Palette names a theme's colors. Values are ANSI 256 color codes ("62"),
+// hex colors ("#5f5fd7") or "none" for the terminal default. Empty fields
+// are taken from the base theme.
+type Palette struct {
+	Base    string `yaml:"base"`    // built-in theme to start from, dark by default
+	Primary string `yaml:"primary"` // form labels and links
+	Border  string `yaml:"border"`  // panel borders and focus
+	Accent  string `yaml:"accent"`  // titles, selections and the cursor
+	Subtle  string `yaml:"subtle"`  // help lines, line numbers and dimmed text
+	Muted   string `yaml:"muted"`   // comments
+	Info    string `yaml:"info"`    // labels and keys
+	Success string `yaml:"success"` // values and status messages
+	Error   string `yaml:"error"`   // errors
+	Text    string `yaml:"text"`    // body text on overlays
+	Faint   string `yaml:"faint"`   // backdrop behind modals
+}
+
+// Theme is a resolved palette ready for lipgloss
+type Theme struct {
+	Name    string
+	Primary lipgloss.TerminalColor
+	Border  lipgloss.TerminalColor
+	Accent  lipgloss.TerminalColor
+	Subtle  lipgloss.TerminalColor
+	Muted   lipgloss.TerminalColor
+	Info    lipgloss.TerminalColor
+	Success lipgloss.TerminalColor
+	Error   lipgloss.TerminalColor
+	Text    lipgloss.TerminalColor
+	Faint   lipgloss.TerminalColor
+}
+
+var builtins = map[string]Palette{
+	Dark: {
+		Primary: "39",
+		Border:  "62",
+		Accent:  "226",
+		Subtle:  "240",
+		Muted:   "244",
+		Info:    "111",
+		Success: "86",
+		Error:   "196",
+		Text:    "252",
+		Faint:   "235",
+	},
+	Light: {
+		Primary: "25",
+		Border:  "61",
+		Accent:  "130",
+		Subtle:  "244",
+		Muted:   "242",
+		Info:    "26",
+		Success: "29",
+		Error:   "160",
+		Text:    "236",
+		Faint:   "254",
+	},
+	HighContrast: {
+		Primary: "14",
+		Border:  "15",
+		Accent:  "11",
+		Subtle:  "250",
+		Muted:   "250",
+		Info:    "14",
+		Success: "10",
+		Error:   "9",
+		Text:    "15",
+		Faint:   "0",
+	},
+	NoColor: {
+		Primary: "none",
+		Border:  "none",
+		Accent:  "none",
+		Subtle:  "none",
+		Muted:   "none",
+		Info:    "none",
+		Success: "none",
+		Error:   "none",
+		Text:    "none",
+		Faint:   "none",
+	},
+}
+
+// Names returns the built-in theme names
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the dark theme
+func Default() Theme {
+	t, _ := Resolve(Dark, nil)
+	return t
+}
+
+// Resolve looks a theme up among the user's themes and then the built-ins
+func Resolve(name string, custom map[string]Palette) (Theme, error) {
+	if p, ok := custom[name]; ok {
+		base := p.Base
+		if base == "" {
+			base = Dark
+		}
+		b, ok := builtins[base]
+		if !ok {
+			return Theme{}, fmt.Errorf("themes.%s.base: unknown theme %q (want one of %s)", name, base, strings.Join(Names(), ", "))
+		}
+		return build(name, p.over(b))
+	}
+	if p, ok := builtins[name]; ok {
+		return build(name, p)
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q (want one of %s, or one defined under themes)", name, strings.Join(Names(), ", "))
+}
+
+// Select resolves the theme to run with. An explicit choice wins; otherwise
+// NO_COLOR turns colors off before the configured theme is considered.
+func Select(explicit, configured string, custom map[string]Palette) (Theme, error) {
+	if explicit != "" {
+		return Resolve(explicit, custom)
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return Resolve(NoColor, nil)
+	}
+	if configured == "" {
+		configured = Dark
+	}
+	return Resolve(configured, custom)
+}
+
+// Validate checks user-defined themes without resolving a particular one
+func Validate(custom map[string]Palette) error {
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if _, ok := builtins[name]; ok {
+			problems = append(problems, fmt.Sprintf("themes.%s: cannot redefine a built-in theme", name))
+			continue
+		}
+		if _, err := Resolve(name, custom); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// over fills the palette's empty fields from base
+func (p Palette) over(base Palette) Palette {
+	pick := func(v, fallback string) string {
+		if v == "" {
+			return fallback
+		}
+		return v
+	}
+	return Palette{
+		Primary: pick(p.Primary, base.Primary),
+		Border:  pick(p.Border, base.Border),
+		Accent:  pick(p.Accent, base.Accent),
+		Subtle:  pick(p.Subtle, base.Subtle),
+		Muted:   pick(p.Muted, base.Muted),
+		Info:    pick(p.Info, base.Info),
+		Success: pick(p.Success, base.Success),
+		Error:   pick(p.Error, base.Error),
+		Text:    pick(p.Text, base.Text),
+		Faint:   pick(p.Faint, base.Faint),
+	}
+}
+
+func build(name string, p Palette) (Theme, error) {
+	t := Theme{Name: name}
+	var problems []string
+	for _, f := range []struct {
+		role  string
+		value string
+		dst   *lipgloss.TerminalColor
+	}{
+		{"primary", p.Primary, &t.Primary},
+		{"border", p.Border, &t.Border},
+		{"accent", p.Accent, &t.Accent},
+		{"subtle", p.Subtle, &t.Subtle},
+		{"muted", p.Muted, &t.Muted},
+		{"info", p.Info, &t.Info},
+		{"success", p.Success, &t.Success},
+		{"error", p.Error, &t.Error},
+		{"text", p.Text, &t.Text},
+		{"faint", p.Faint, &t.Faint},
+	} {
+		c, err := parseColor(f.value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("themes.%s.%s: %v", name, f.role, err))
+			continue
+		}
+		*f.dst = c
+	}
+	if len(problems) > 0 {
+		return Theme{}, errors.New(strings.Join(problems, "; "))
+	}
+	return t, nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func parseColor(value string) (lipgloss.TerminalColor, error) {
+	if value == "none" {
+		return lipgloss.NoColor{}, nil
+	}
+	if hexColor.MatchString(value) {
+		return lipgloss.Color(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	return nil, fmt.Errorf("invalid color %q (want 0-255, #rrggbb or none)", value)
+}
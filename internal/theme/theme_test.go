@@ -0,0 +1,119 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResolveBuiltins(t *testing.T) {
+	for _, name := range Names() {
+		th, err := Resolve(name, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if th.Name != name || th.Accent == nil || th.Faint == nil {
+			t.Errorf("%s resolved to %+v", name, th)
+		}
+	}
+
+	light, _ := Resolve(Light, nil)
+	if light.Accent != lipgloss.Color("130") {
+		t.Errorf("light accent = %v", light.Accent)
+	}
+	none, _ := Resolve(NoColor, nil)
+	if none.Error != (lipgloss.NoColor{}) {
+		t.Errorf("no-color error = %v, want no color", none.Error)
+	}
+	if Default().Name != Dark {
+		t.Errorf("default theme is %s", Default().Name)
+	}
+}
+
+func TestResolveCustom(t *testing.T) {
+	custom := map[string]Palette{
+		"solar": {Base: Light, Accent: "#ff8800"},
+		"mine":  {Error: "9"},
+	}
+
+	solar, err := Resolve("solar", custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Set colors win, the rest comes from the base
+	if solar.Accent != lipgloss.Color("#ff8800") || solar.Border != lipgloss.Color("61") {
+		t.Errorf("solar = %+v", solar)
+	}
+	mine, err := Resolve("mine", custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mine.Error != lipgloss.Color("9") || mine.Accent != lipgloss.Color("226") {
+		t.Errorf("mine = %+v, want dark under it", mine)
+	}
+}
+
+func TestResolveUnknown(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		theme  string
+		custom map[string]Palette
+		want   string
+	}{
+		{"unknown name", "neon", nil, `unknown theme "neon" (want one of dark, high-contrast, light, no-color`},
+		{"unknown base", "neon", map[string]Palette{"neon": {Base: "sepia"}}, `themes.neon.base: unknown theme "sepia"`},
+		{"bad color", "neon", map[string]Palette{"neon": {Accent: "pink", Text: "300"}}, `themes.neon.accent: invalid color "pink"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Resolve(tc.theme, tc.custom); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want %q", err, tc.want)
+			}
+			// The configured theme fails the same way, so the caller keeps its current one
+			if _, err := Select("", tc.theme, tc.custom); err == nil {
+				t.Error("selected an unknown theme")
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	custom := map[string]Palette{"solar": {Base: Light}}
+	for _, tc := range []struct {
+		name       string
+		noColor    string
+		explicit   string
+		configured string
+		want       string
+	}{
+		{"nothing set", "", "", "", Dark},
+		{"configured", "", "", "solar", "solar"},
+		{"explicit over configured", "", Light, "solar", Light},
+		{"NO_COLOR over configured", "1", "", "solar", NoColor},
+		{"explicit over NO_COLOR", "1", HighContrast, "solar", HighContrast},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			th, err := Select(tc.explicit, tc.configured, custom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if th.Name != tc.want {
+				t.Errorf("selected %s, want %s", th.Name, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(map[string]Palette{"solar": {Base: Light, Accent: "#f80"}}); err != nil {
+		t.Errorf("valid theme: %v", err)
+	}
+	err := Validate(map[string]Palette{
+		Dark:   {Accent: "1"},
+		"neon": {Accent: "pink"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "themes.dark: cannot redefine a built-in theme") || !strings.Contains(err.Error(), "themes.neon.accent") {
+		t.Errorf("error = %v, want both problems", err)
+	}
+}
@@ -0,0 +1,121 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+// FormModel is a vertical stack of labelled text inputs
+type FormModel struct {
+	labels []string
+	inputs []textinput.Model
+	focus  int
+	keys   keymap.KeyMap
+}
+
+// NewForm creates a form with one input per label
+func NewForm(labels ...string) FormModel {
+	inputs := make([]textinput.Model, len(labels))
+	for i := range labels {
+		inputs[i] = textinput.New()
+		inputs[i].Prompt = ""
+	}
+	return FormModel{
+		labels: labels,
+		inputs: inputs,
+		keys:   keymap.Default(),
+	}
+}
+
+// SetKeys replaces the bindings used to move between inputs
+func (f *FormModel) SetKeys(keys keymap.KeyMap) {
+	f.keys = keys
+}
+
+// SetValue sets the value of the input at index i
+func (f *FormModel) SetValue(i int, value string) {
+	f.inputs[i].SetValue(value)
+}
+
+// SetPlaceholder sets the placeholder of the input at index i
+func (f *FormModel) SetPlaceholder(i int, placeholder string) {
+	f.inputs[i].Placeholder = placeholder
+}
+
+// Value returns the trimmed value of the input at index i
+func (f FormModel) Value(i int) string {
+	return strings.TrimSpace(f.inputs[i].Value())
+}
+
+// Focused returns the index of the focused input
+func (f FormModel) Focused() int {
+	return f.focus
+}
+
+// Len returns the number of inputs
+func (f FormModel) Len() int {
+	return len(f.inputs)
+}
+
+// Focus focuses the input at index i and blurs the others
+func (f *FormModel) Focus(i int) tea.Cmd {
+	if len(f.inputs) == 0 {
+		return nil
+	}
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	for j := range f.inputs {
+		f.inputs[j].Blur()
+	}
+	return f.inputs[f.focus].Focus()
+}
+
+// Blur removes focus from every input
+func (f *FormModel) Blur() {
+	for j := range f.inputs {
+		f.inputs[j].Blur()
+	}
+}
+
+// Update moves focus with the form's next and previous bindings and
+// forwards other messages to the focused input
+func (f FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch f.keys.Action(key, keymap.Form) {
+		case "next":
+			return f, f.Focus(f.focus + 1)
+		case "prev":
+			return f, f.Focus(f.focus - 1)
+		}
+	}
+
+	if len(f.inputs) == 0 {
+		return f, nil
+	}
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return f, cmd
+}
+
+// View renders each label above its input
+func (f FormModel) View() string {
+	width := 0
+	for _, l := range f.labels {
+		if len(l) > width {
+			width = len(l)
+		}
+	}
+
+	rows := make([]string, len(f.inputs))
+	for i, input := range f.inputs {
+		style := formLabelStyle
+		if i == f.focus && input.Focused() {
+			style = formFocusedLabelStyle
+		}
+		rows[i] = style.Width(width+2).Render(f.labels[i]+":") + input.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
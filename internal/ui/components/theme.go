@@ -0,0 +1,51 @@
+package components
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/theme"
+)
+
+var (
+	// Colors used across the components, set by SetTheme
+	primaryColor   lipgloss.TerminalColor
+	secondaryColor lipgloss.TerminalColor
+	accentColor    lipgloss.TerminalColor
+	subtleColor    lipgloss.TerminalColor
+	faintColor     lipgloss.TerminalColor
+
+	modalTitleStyle  lipgloss.Style
+	modalBorderStyle = lipgloss.RoundedBorder()
+
+	// Help style for showing keyboard shortcuts
+	helpStyle lipgloss.Style
+
+	formLabelStyle        lipgloss.Style
+	formFocusedLabelStyle lipgloss.Style
+)
+
+func init() {
+	SetTheme(theme.Default())
+}
+
+// SetTheme restyles the components
+func SetTheme(t theme.Theme) {
+	primaryColor = t.Primary
+	secondaryColor = t.Border
+	accentColor = t.Accent
+	subtleColor = t.Subtle
+	faintColor = t.Faint
+
+	modalTitleStyle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true).
+		Padding(0, 1)
+	helpStyle = lipgloss.NewStyle().
+		Foreground(subtleColor).
+		Italic(true)
+	formLabelStyle = lipgloss.NewStyle().
+		Foreground(primaryColor).
+		Bold(true)
+	formFocusedLabelStyle = lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true)
+}
@@ -1,106 +1,472 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
 	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/theme"
 	"github.com/shubhamku044/containix/internal/ui/views"
 )
 
+// paneBorder is the room a pane's border takes on each axis
+const paneBorder = 2
+
+// Layout selects how the main screen is arranged
+type Layout string
+
+const (
+	// LayoutDashboard shows the container list next to stats and logs
+	LayoutDashboard Layout = "dashboard"
+	// LayoutClassic is the original two-pane layout: containers and logs
+	LayoutClassic Layout = "classic"
+)
+
+// ParseLayout validates a layout name
+func ParseLayout(name string) (Layout, error) {
+	switch Layout(name) {
+	case LayoutDashboard, LayoutClassic:
+		return Layout(name), nil
+	}
+	return "", fmt.Errorf("unknown layout %q (want %s or %s)", name, LayoutDashboard, LayoutClassic)
+}
+
+// Options configures the main model
+type Options struct {
+	Config     config.Config
+	ConfigPath string // watched for changes when set
+	Layout     Layout // overrides the configured layout when set
+	Theme      string // overrides the configured theme and NO_COLOR when set
+
+	// Target names the daemon for the status bar, which is hidden when empty
+	Target string
+	// Connect opens a runtime for a docker context or a host address,
+	// giving up when ctx ends. The context picker is disabled when it is nil.
+	Connect func(ctx context.Context, dockerContext, host string) (docker.Runtime, error)
+	// Context bounds every daemon call; the caller cancels it on exit.
+	// context.Background is used when it is nil.
+	Context context.Context
+}
+
+// contextSwitchedMsg carries the runtime connected for a context switch
+type contextSwitchedMsg struct {
+	target  string
+	runtime docker.Runtime
+	err     error
+}
+
+// capabilitiesMsg carries what the daemon can do. A runtime that fails to
+// answer keeps the defaults; the container list shows the error.
+type capabilitiesMsg struct {
+	runtime docker.Runtime
+	caps    docker.Capabilities
+}
+
 // MainModel is the main model for the application
 type MainModel struct {
-	containerList views.ContainerListModel
-	logView       views.LogViewModel
-	statsView     views.StatsViewModel
-	focusLeft     bool
-	width         int
-	height        int
+	containerList  views.ContainerListModel
+	logView        views.LogViewModel
+	statsView      views.StatsViewModel
+	dockerClient   docker.Runtime
+	root           context.Context
+	ctx            context.Context    // the current runtime's calls
+	cancel         context.CancelFunc // stops them on a switch or quit
+	caps           docker.Capabilities
+	health         docker.Health
+	pinging        bool // a health check is waiting for the daemon
+	cfg            config.Config
+	configPath     string
+	configStamp    config.Stamp
+	layoutOverride Layout
+	themeOverride  string
+	layout         Layout
+	target         string
+	switching      string // target being connected to
+	connect        func(ctx context.Context, dockerContext, host string) (docker.Runtime, error)
+	tickers        [tickKinds]ticker
+	showHelp       bool
+	focusLeft      bool
+	width          int
+	height         int
 }
 
 // NewMainModel creates a new main model
-func NewMainModel() tea.Model {
-	// Create docker client
-	dockerClient, err := docker.NewClient()
-	if err != nil {
-		panic(err)
+func NewMainModel(dockerClient docker.Runtime, opts Options) tea.Model {
+	m := MainModel{
+		cfg:            opts.Config,
+		configPath:     opts.ConfigPath,
+		layoutOverride: opts.Layout,
+		themeOverride:  opts.Theme,
+		target:         opts.Target,
+		connect:        opts.Connect,
+		root:           opts.Context,
+	}
+	if m.root == nil {
+		m.root = context.Background()
+	}
+	if opts.ConfigPath != "" {
+		m.configStamp = config.StatStamp(opts.ConfigPath)
+	}
+	m.setRuntime(dockerClient)
+	m.applyLayout()
+	m.applyTheme()
+	return m
+}
+
+// setRuntime points the main screen at a daemon. The panes are created
+// anew, so nothing from a previous daemon is left on screen, and calls
+// still waiting on the previous daemon are cancelled.
+func (m *MainModel) setRuntime(dockerClient docker.Runtime) {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.ctx, m.cancel = context.WithCancel(m.root)
+	m.dockerClient = dockerClient
+	m.containerList = views.NewContainerListModel(m.ctx, dockerClient, m.cfg)
+	m.logView = views.NewLogViewModel()
+	m.logView.SetKeys(m.cfg.Keys)
+	m.statsView = views.NewStatsView(m.ctx, dockerClient)
+	m.caps = docker.DefaultCapabilities()
+	m.health, m.pinging = docker.Health{}, false
+	m.focusLeft = true
+}
+
+// fetchCapabilities asks the daemon what it can do, which also settles the
+// API version before the screens start using it
+func (m MainModel) fetchCapabilities() tea.Cmd {
+	ctx, runtime := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		caps, err := runtime.Capabilities(ctx)
+		if err != nil {
+			slog.Warn("daemon capabilities unknown", "error", err)
+		}
+		return capabilitiesMsg{runtime: runtime, caps: caps}
+	}
+}
+
+// switchContext connects to another daemon in the background
+func (m MainModel) switchContext(msg views.SwitchContextMsg) tea.Cmd {
+	ctx, connect := m.root, m.connect
+	return func() tea.Msg {
+		runtime, err := connect(ctx, msg.Context, msg.Host)
+		return contextSwitchedMsg{target: msg.Target(), runtime: runtime, err: err}
 	}
+}
 
-	// Fix: NewContainerListModel doesn't take arguments
-	containerList, err := views.NewContainerListModel()
+// applyLayout picks the command line layout over the configured one
+func (m *MainModel) applyLayout() {
+	m.layout = m.layoutOverride
+	if m.layout == "" {
+		m.layout = Layout(m.cfg.Layout.Mode)
+	}
+	if m.layout == "" {
+		m.layout = LayoutDashboard
+	}
+}
+
+// applyTheme restyles the views with the selected theme. The configuration
+// was validated when it was loaded, so a failure here leaves the current
+// theme in place.
+func (m *MainModel) applyTheme() {
+	t, err := theme.Select(m.themeOverride, m.cfg.Theme, m.cfg.Themes)
 	if err != nil {
-		panic(err)
+		return
+	}
+	views.SetTheme(t)
+}
+
+// leftWidth is the width of the container list pane, border included
+func (m MainModel) leftWidth() int {
+	split := m.cfg.Layout.Split
+	if split == 0 {
+		split = 50
+	}
+	return m.width * split / 100
+}
+
+// statusBarHeight is the height of the status bar, zero when it is hidden
+func (m MainModel) statusBarHeight() int {
+	if m.target == "" {
+		return 0
+	}
+	return 1
+}
+
+// paneHeight is the height inside the border both panes are drawn with
+func (m MainModel) paneHeight() int {
+	return max(m.height-paneBorder-m.statusBarHeight(), 0)
+}
+
+// statsHeight is the height of the stats pane, zero when it is hidden
+func (m MainModel) statsHeight() int {
+	if m.layout == LayoutClassic {
+		return 0
 	}
+	percent := m.cfg.Layout.StatsHeight
+	if percent == 0 {
+		percent = 50
+	}
+	return m.paneHeight() * percent / 100
+}
+
+// resize hands every pane its share of the terminal
+func (m *MainModel) resize() tea.Cmd {
+	var cmds []tea.Cmd
+	leftWidth := max(m.leftWidth()-paneBorder, 0)
+	rightWidth := max(m.width-m.leftWidth()-paneBorder, 0)
+	statsHeight := m.statsHeight()
+
+	containerListModel, cmd := m.containerList.Update(tea.WindowSizeMsg{
+		Width:  leftWidth,
+		Height: m.paneHeight(),
+	})
+	m.containerList = containerListModel.(views.ContainerListModel)
+	cmds = append(cmds, cmd)
 
-	return MainModel{
-		containerList: containerList,
-		logView:       views.NewLogViewModel(),
-		statsView:     views.NewStatsView(dockerClient),
-		focusLeft:     true,
+	// Logs fill the right side below the stats
+	m.logView, cmd = m.logView.Update(tea.WindowSizeMsg{
+		Width:  rightWidth,
+		Height: m.paneHeight() - statsHeight,
+	})
+	cmds = append(cmds, cmd)
+
+	m.statsView, cmd = m.statsView.Update(tea.WindowSizeMsg{
+		Width:  rightWidth,
+		Height: statsHeight,
+	})
+	cmds = append(cmds, cmd)
+
+	return tea.Batch(cmds...)
+}
+
+// applyConfig switches to a reloaded configuration
+func (m *MainModel) applyConfig(cfg config.Config) tea.Cmd {
+	m.cfg = cfg
+	m.containerList.SetConfig(cfg)
+	m.logView.SetKeys(cfg.Keys)
+	m.applyLayout()
+	m.applyTheme()
+
+	// Intervals may have changed, so restart every ticker
+	cmds := []tea.Cmd{m.resize()}
+	for kind := range m.tickers {
+		cmds = append(cmds, m.arm(tickKind(kind)))
 	}
+	return tea.Batch(cmds...)
 }
 
 // Init initializes the model
 func (m MainModel) Init() tea.Cmd {
-	return m.containerList.Init()
+	return tea.Batch(m.fetchCapabilities(), m.checkHealth(), m.containerList.Init(), m.statsView.Refresh())
 }
 
 // Update updates the model
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+	cmds := m.rearmStale()
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		// Store full terminal dimensions
 		m.width = msg.Width
 		m.height = msg.Height
+		// Panes get their share from resize, not the whole terminal
+		cmds = append(cmds, m.resize())
+		return m, tea.Batch(cmds...)
+
+	case tickMsg:
+		if msg.gen != m.tickers[msg.kind].gen {
+			// A ticker that was replaced after a config reload
+			return m, tea.Batch(cmds...)
+		}
+		cmds = append(cmds, m.tick(msg.kind))
+		if msg.kind != healthTick {
+			cmds = append(cmds, m.arm(msg.kind))
+		}
+		return m, tea.Batch(cmds...)
 
-		// Use full height but divide it proportionally
-		containerListHeight := m.height * 6 / 10
-		statsHeight := m.height - containerListHeight
+	case healthMsg:
+		if msg.runtime != m.dockerClient || errors.Is(msg.err, context.Canceled) {
+			// A daemon switched away from, or a check cut short on exit
+			return m, tea.Batch(cmds...)
+		}
+		m.pinging = false
+		prev := m.health.State
+		recovered := m.health.Record(time.Now(), msg.latency, msg.err)
+		if m.health.State != prev {
+			slog.Warn("daemon connection changed", "state", m.health.State, "error", m.health.Err)
+		}
+		if recovered {
+			// Everything shown may be stale, so fetch it again
+			cmds = append(cmds, m.fetchCapabilities(), m.containerList.Resync(), m.statsView.Refresh())
+		}
+		cmds = append(cmds, m.arm(healthTick))
+		return m, tea.Batch(cmds...)
 
-		// Pass full width/2 to each side component
-		containerListMsg := tea.WindowSizeMsg{
-			Width:  msg.Width / 2,
-			Height: containerListHeight,
+	case configLoadedMsg:
+		m.configStamp = msg.stamp
+		if msg.err != nil {
+			// Keep the previous configuration until the file is fixed
+			return m, func() tea.Msg {
+				return views.ErrMsg{Err: fmt.Errorf("config not reloaded: %w", msg.err)}
+			}
 		}
-		containerListModel, cmd := m.containerList.Update(containerListMsg)
-		m.containerList = containerListModel.(views.ContainerListModel)
-		cmds = append(cmds, cmd)
+		return m, m.applyConfig(msg.cfg)
+
+	case views.SwitchContextMsg:
+		m.switching = msg.Target()
+		slog.Info("switching docker context", "context", msg.Context, "host", msg.Host)
+		return m, m.switchContext(msg)
 
-		// Log view gets full height on right side
-		logMsg := tea.WindowSizeMsg{
-			Width:  m.width / 2,
-			Height: m.height,
+	case contextSwitchedMsg:
+		m.switching = ""
+		if msg.err != nil {
+			// Stay on the current daemon
+			return m, func() tea.Msg {
+				return views.ErrMsg{Err: fmt.Errorf("switching to %s: %w", msg.target, msg.err)}
+			}
 		}
-		m.logView, cmd = m.logView.Update(logMsg)
-		cmds = append(cmds, cmd)
+		if closer, ok := m.dockerClient.(io.Closer); ok {
+			closer.Close()
+		}
+		m.target = msg.target
+		m.setRuntime(msg.runtime)
+		return m, tea.Batch(m.resize(), m.fetchCapabilities(), m.checkHealth(), m.containerList.Init(), m.statsView.Refresh())
 
-		// Stats view gets remaining height on left side
-		statsMsg := tea.WindowSizeMsg{
-			Width:  m.width / 2,
-			Height: statsHeight,
+	case capabilitiesMsg:
+		if msg.runtime != m.dockerClient {
+			// The answer of a daemon switched away from
+			return m, tea.Batch(cmds...)
 		}
-		m.statsView, cmd = m.statsView.Update(statsMsg)
-		cmds = append(cmds, cmd)
+		m.caps = msg.caps
+		m.statsView.SetCapabilities(msg.caps)
+		return m, tea.Batch(cmds...)
 
 	case views.SelectedContainerMsg:
-		// When a container is selected, update the stats view
-		cmd := m.statsView.SetContainerID(msg.ID)
+		// When a container is selected, update the stats view. The classic
+		// layout has none, so there is nothing to poll.
+		if m.layout == LayoutDashboard {
+			cmds = append(cmds, m.statsView.SetContainerID(msg.ID))
+		}
+
+	case views.ActivityMsg, spinner.TickMsg:
+		// The spinner of the container list's actions runs whatever has focus
+		containerListModel, cmd := m.containerList.Update(msg)
+		m.containerList = containerListModel.(views.ContainerListModel)
 		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+
+	case views.ContainerStatsMsg, views.FleetUsageMsg:
+		var cmd tea.Cmd
+		m.statsView, cmd = m.statsView.Update(msg)
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+
+	case views.LogsMsg:
+		m.logView.SetContent(msg.Logs)
+		return m, tea.Batch(cmds...)
+
+	case views.OpenFileBrowserMsg:
+		if err := m.caps.Supports(docker.FeatureFiles); err != nil {
+			return m, func() tea.Msg { return views.ErrMsg{Err: err} }
+		}
+		// The browser takes over the screen and hands control back on close
+		browser := views.NewFileBrowser(m.ctx, m.dockerClient, msg.ID, msg.Name, m.cfg, m.width, m.height, m)
+		return browser, browser.Init()
+
+	case views.OpenUploadMsg:
+		if err := m.caps.Supports(docker.FeatureFiles); err != nil {
+			return m, func() tea.Msg { return views.ErrMsg{Err: err} }
+		}
+		upload := views.NewUpload(m.ctx, m.dockerClient, msg.ID, msg.Name, msg.Dest, m.cfg, m.width, m.height, m)
+		return upload, upload.Init()
+
+	case views.OpenContainerDetailMsg:
+		detail := views.NewContainerDetail(m.ctx, m.dockerClient, m.caps, msg.ID, msg.Name, m.cfg, m.width, m.height, m)
+		return detail, detail.Init()
+
+	case views.OpenExportMsg:
+		export := views.NewExport(m.ctx, m.dockerClient, msg.ID, msg.Name, m.cfg, m.width, m.height, m)
+		return export, export.Init()
+
+	case views.OpenNetworksMsg:
+		networks := views.NewNetworkView(m.ctx, m.dockerClient, m.caps, m.cfg, m.width, m.height, m)
+		return networks, networks.Init()
+
+	case views.OpenComposeMsg:
+		composeView := views.NewComposeView(m.ctx, m.dockerClient, msg.Project, msg.Files, m.cfg, m.width, m.height, m)
+		return composeView, composeView.Init()
+
+	case views.OpenTopologyMsg:
+		topology := views.NewTopology(m.ctx, m.dockerClient, m.cfg, m.width, m.height, m)
+		return topology, topology.Init()
+
+	case views.OpenRunMsg:
+		if m.dockerClient.ReadOnly() {
+			return m, func() tea.Msg { return views.ErrMsg{Err: docker.ErrReadOnly} }
+		}
+		wizard := views.NewRunWizard(m.ctx, m.dockerClient, m.caps, m.cfg, config.PresetsPath(m.configPath), m.width, m.height, m)
+		return wizard, wizard.Init()
+
+	case views.ResourcesUpdatedMsg:
+		// The stats show the limits, so they are read again
+		return m, m.statsView.Refresh()
+
+	case views.ContainerCreatedMsg:
+		// The container list lists and selects it
+		m.focusLeft = true
+
+	case views.FocusContainerMsg:
+		// Selection always happens in the container list
+		m.focusLeft = true
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "tab":
+		if m.showHelp {
+			// Any key closes the help overlay
+			m.showHelp = false
+			return m, tea.Batch(cmds...)
+		}
+		if m.focusLeft && m.containerList.Filtering() {
+			// Keys belong to the filter while the user is typing one
+			break
+		}
+
+		switch m.cfg.Keys.Action(msg, keymap.Main) {
+		case "contexts":
+			if m.connect == nil {
+				return m, func() tea.Msg {
+					return views.ErrMsg{Err: errors.New("switching the docker context is not available in demo mode")}
+				}
+			}
+			picker := views.NewContextPicker(m.target, m.cfg, m.width, m.height, m)
+			return picker, picker.Init()
+		case "host":
+			host := views.NewHostView(m.ctx, m.dockerClient, m.target, m.cfg, m.width, m.height, m)
+			return host, host.Init()
+		case "focus":
 			m.focusLeft = !m.focusLeft
-			return m, nil
-		case "q":
+			return m, tea.Batch(cmds...)
+		case "quit":
 			if !m.focusLeft {
 				// If focus is on logs view, just return focus to container list
 				m.focusLeft = true
-				return m, nil
+				return m, tea.Batch(cmds...)
 			}
+			m.cancel()
 			return m, tea.Quit
 		}
+		if m.cfg.Keys.Matches(msg, keymap.Global, "help") {
+			m.showHelp = true
+			return m, tea.Batch(cmds...)
+		}
 	}
 
 	// Route messages to the appropriate component based on focus
@@ -122,47 +488,70 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the model
 func (m MainModel) View() string {
-	// Create left side with container list
-	containerListView := m.containerList.View()
-
-	// Right side: Split vertical space between statsView and logView
-	statsHeight := m.height / 2
-	logsHeight := m.height - statsHeight
-
-	// Ensure statsView and logView fit within their allocated heights
-	statsView := lipgloss.NewStyle().
-		Height(statsHeight).
-		Width(m.width / 2).
-		Render(m.statsView.View())
-
-	logsView := lipgloss.NewStyle().
-		Height(logsHeight).
-		Width(m.width / 2).
-		Render(m.logView.View())
+	if m.showHelp {
+		pane := keymap.Containers
+		if !m.focusLeft {
+			pane = keymap.Logs
+		}
+		return views.HelpOverlay(m.cfg.Keys, m.width, m.height, pane, keymap.Main, keymap.Global)
+	}
 
-	// Stack statsView and logView vertically
-	rightSide := lipgloss.JoinVertical(lipgloss.Left, statsView, logsView)
+	leftWidth := max(m.leftWidth()-paneBorder, 0)
+	rightWidth := max(m.width-m.leftWidth()-paneBorder, 0)
+	height := m.paneHeight()
 
-	// Calculate exact widths for left and right sections
-	leftWidth := m.width / 2
-	rightWidth := m.width - leftWidth
+	// Right side: the stats above the logs, or only the logs
+	statsHeight := m.statsHeight()
+	rightSide := fit(m.logView.View(), rightWidth, height-statsHeight)
+	if statsHeight > 0 {
+		rightSide = lipgloss.JoinVertical(lipgloss.Left, fit(m.statsView.View(), rightWidth, statsHeight), rightSide)
+	}
 
-	// Apply styles based on focus
-	leftStyle := lipgloss.NewStyle().Width(leftWidth).Height(m.height)
-	rightStyle := lipgloss.NewStyle().Width(rightWidth).Height(m.height)
+	left := m.pane(m.focusLeft).Render(fit(m.containerList.View(), leftWidth, height))
+	right := m.pane(!m.focusLeft).Render(fit(rightSide, rightWidth, height))
+	screen := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	if m.target == "" {
+		return screen
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, screen, m.statusBar())
+}
 
-	if m.focusLeft {
-		leftStyle = leftStyle.BorderForeground(lipgloss.Color("62")).
-			BorderStyle(lipgloss.RoundedBorder())
-	} else {
-		rightStyle = rightStyle.BorderForeground(lipgloss.Color("62")).
-			BorderStyle(lipgloss.RoundedBorder())
+// statusBar shows the daemon the screen is connected to
+func (m MainModel) statusBar() string {
+	var notes []string
+	if m.switching != "" {
+		notes = append(notes, "connecting to "+m.switching+"...")
+	}
+	if note := views.HealthNote(m.health); note != "" {
+		notes = append(notes, note)
+	}
+	if fleet, ok := m.dockerClient.(*docker.Fleet); ok {
+		notes = append(notes, views.HostsNote(fleet.Health()))
+	}
+	if m.dockerClient.ReadOnly() {
+		notes = append(notes, "read-only")
 	}
+	return views.StatusBar(m.width, m.target, notes...)
+}
 
-	// Render styled views
-	styledLeft := leftStyle.Render(containerListView)
-	styledRight := rightStyle.Render(rightSide)
+// pane styles a side of the screen. Both sides always have a border, hidden
+// on the unfocused one, so switching focus does not move anything.
+func (m MainModel) pane(focused bool) lipgloss.Style {
+	border := lipgloss.HiddenBorder()
+	if focused {
+		border = lipgloss.RoundedBorder()
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(border).
+		BorderForeground(views.BorderColor())
+}
 
-	// Join horizontally with proper dimensions
-	return lipgloss.JoinHorizontal(lipgloss.Top, styledLeft, styledRight)
+// fit pads or cuts a rendered block to exactly width by height cells
+func fit(block string, width, height int) string {
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxWidth(width).
+		MaxHeight(height).
+		Render(block)
 }
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// tickKind names a periodic task of the main model
+type tickKind int
+
+const (
+	containersTick tickKind = iota
+	statsTick
+	configTick
+	healthTick
+	tickKinds
+)
+
+// tickMsg fires a periodic task. gen identifies the ticker that scheduled
+// it, so ticks from a replaced ticker are dropped.
+type tickMsg struct {
+	kind tickKind
+	gen  int
+}
+
+// healthMsg carries the outcome of a ping
+type healthMsg struct {
+	runtime docker.Runtime
+	latency time.Duration
+	err     error
+}
+
+// configLoadedMsg carries the config file after it changed on disk
+type configLoadedMsg struct {
+	cfg   config.Config
+	stamp config.Stamp
+	err   error
+}
+
+// ticker tracks the latest schedule of one periodic task
+type ticker struct {
+	gen  int
+	last time.Time
+}
+
+// interval returns how often a task runs, zero when it is disabled
+func (m MainModel) interval(kind tickKind) time.Duration {
+	switch kind {
+	case containersTick:
+		return time.Duration(m.cfg.Refresh.Containers)
+	case statsTick:
+		if m.layout == LayoutClassic {
+			return 0
+		}
+		return time.Duration(m.cfg.Refresh.Stats)
+	case configTick:
+		if m.configPath == "" {
+			return 0
+		}
+		return time.Duration(m.cfg.Refresh.Config)
+	case healthTick:
+		if m.cfg.Refresh.Health > 0 && m.health.State == docker.Disconnected {
+			return m.health.Backoff()
+		}
+		return time.Duration(m.cfg.Refresh.Health)
+	}
+	return 0
+}
+
+// arm schedules the next run of a task, replacing any earlier schedule
+func (m *MainModel) arm(kind tickKind) tea.Cmd {
+	t := &m.tickers[kind]
+	t.gen++
+	t.last = time.Now()
+
+	interval := m.interval(kind)
+	if interval <= 0 {
+		return nil
+	}
+	gen := t.gen
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return tickMsg{kind: kind, gen: gen}
+	})
+}
+
+// rearmStale restarts tasks whose ticks were lost. Ticks that arrive while
+// another screen is on top never reach the main model, so the schedule is
+// picked up again once the main model sees messages.
+func (m *MainModel) rearmStale() []tea.Cmd {
+	var cmds []tea.Cmd
+	for kind := range m.tickers {
+		if tickKind(kind) == healthTick && m.pinging {
+			// Armed again when the ping answers, which may take a while
+			continue
+		}
+		interval := m.interval(tickKind(kind))
+		if interval > 0 && time.Since(m.tickers[kind].last) > 2*interval {
+			cmds = append(cmds, m.arm(tickKind(kind)))
+		}
+	}
+	return cmds
+}
+
+// tick runs one periodic task. Polling a disconnected daemon would only
+// repeat its error, so only the health check keeps running.
+func (m *MainModel) tick(kind tickKind) tea.Cmd {
+	if m.health.State == docker.Disconnected && (kind == containersTick || kind == statsTick) {
+		return nil
+	}
+	switch kind {
+	case containersTick:
+		return m.containerList.Refresh()
+	case statsTick:
+		return m.statsView.Refresh()
+	case configTick:
+		return checkConfig(m.configPath, m.configStamp)
+	case healthTick:
+		m.pinging = m.cfg.Refresh.Health > 0
+		return m.checkHealth()
+	}
+	return nil
+}
+
+// checkHealth pings the daemon. The next check is armed once it answers,
+// after a delay that depends on the answer.
+func (m MainModel) checkHealth() tea.Cmd {
+	if m.cfg.Refresh.Health <= 0 {
+		return nil
+	}
+	ctx, runtime := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		start := time.Now()
+		err := runtime.Ping(ctx)
+		return healthMsg{runtime: runtime, latency: time.Since(start), err: err}
+	}
+}
+
+// checkConfig reloads the config file when it changed since stamp
+func checkConfig(path string, stamp config.Stamp) tea.Cmd {
+	return func() tea.Msg {
+		current := config.StatStamp(path)
+		if current == stamp {
+			return nil
+		}
+		cfg, err := config.Load(path)
+		return configLoadedMsg{cfg: cfg, stamp: current, err: err}
+	}
+}
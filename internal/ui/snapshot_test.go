@@ -0,0 +1,401 @@
+package ui
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/docker/fake"
+	"github.com/shubhamku044/containix/internal/ui/uitest"
+)
+
+// sizes are the terminal sizes every screen is rendered at
+var sizes = []struct{ width, height int }{
+	{80, 24},
+	{120, 40},
+	{200, 50},
+}
+
+// newFleet returns a small host with a compose project, standalone
+// containers and some history
+func newFleet() *fake.Runtime {
+	r := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	for _, svc := range []string{"web", "api", "db"} {
+		r.AddContainer(fake.ContainerSpec{
+			Name:  "shop-" + svc + "-1",
+			Image: "acme/shop-" + svc + ":1.0",
+			Labels: map[string]string{
+				docker.ComposeProjectLabel: "shop",
+				docker.ComposeServiceLabel: svc,
+			},
+			CPU:      12.5,
+			Memory:   128 << 20,
+			PIDs:     7,
+			LogLines: []string{svc + " handled request {n}"},
+			LogEvery: 5 * time.Second,
+		})
+	}
+	r.AddContainer(fake.ContainerSpec{
+		Name:   "redis",
+		Image:  "redis:7",
+		Ports:  []docker.Port{{IP: "0.0.0.0", PrivatePort: 6379, PublicPort: 6379, Type: "tcp"}},
+		CPU:    0.4,
+		Memory: 8 << 20,
+	})
+	r.AddContainer(fake.ContainerSpec{
+		Name:   "backup",
+		Image:  "acme/backup:2",
+		Status: fake.StatusExited,
+		Logs:   []string{"backup finished"},
+	})
+	r.Advance(time.Minute)
+	return r
+}
+
+// testConfig disables polling so screens only change on scripted input
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Refresh = config.Refresh{}
+	return cfg
+}
+
+func TestMainModelSnapshots(t *testing.T) {
+	scenarios := []struct {
+		name   string
+		layout Layout
+		keys   []string
+	}{
+		{name: "list"},
+		// The first row is the shop project, its containers follow
+		{name: "selected", keys: []string{"down", "enter", "l"}},
+		{name: "logs_focus", keys: []string{"down", "enter", "l", "tab"}},
+		{name: "collapsed", keys: []string{"C"}},
+		{name: "confirm", keys: []string{"down", "x"}},
+		{name: "help", keys: []string{"?"}},
+		{name: "detail", keys: []string{"down", "i"}},
+		{name: "files", keys: []string{"down", "f"}},
+		{name: "networks", keys: []string{"n"}},
+		{name: "topology", keys: []string{"T"}},
+		{name: "compose", keys: []string{"p"}},
+		{name: "host", keys: []string{"D"}},
+		{name: "classic", layout: LayoutClassic, keys: []string{"down", "enter", "l"}},
+	}
+
+	for _, sc := range scenarios {
+		for _, size := range sizes {
+			name := fmt.Sprintf("%s_%dx%d", sc.name, size.width, size.height)
+			t.Run(name, func(t *testing.T) {
+				cfg := testConfig()
+				cfg.Confirm.Restart = true
+				model := NewMainModel(newFleet(), Options{Config: cfg, Layout: sc.layout})
+
+				d := uitest.New(t, model).Resize(size.width, size.height).Keys(sc.keys...)
+				uitest.Golden(t, name, d.View())
+			})
+		}
+	}
+}
+
+func TestMainModelResize(t *testing.T) {
+	// Growing the terminal after data arrived must lay the panes out again
+	model := NewMainModel(newFleet(), Options{Config: testConfig()})
+	d := uitest.New(t, model).Resize(80, 24).Keys("down", "enter", "l").Resize(120, 40)
+	uitest.Golden(t, "resized_120x40", d.View())
+}
+
+func TestMainModelError(t *testing.T) {
+	r := newFleet()
+	r.FailOn("ListContainers", fmt.Errorf("Cannot connect to the Docker daemon at unix:///var/run/docker.sock"))
+	d := uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40)
+	uitest.Golden(t, "error_120x40", d.View())
+}
+
+func TestMainModelContextSwitch(t *testing.T) {
+	// A docker CLI store with one context besides the default
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("DOCKER_HOST", "")
+	sum := sha256.Sum256([]byte("staging"))
+	meta := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(sum[:]))
+	if err := os.MkdirAll(meta, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(filepath.Join(meta, "meta.json"), []byte(`{
+		"Name": "staging",
+		"Metadata": {"Description": "staging cluster"},
+		"Endpoints": {"docker": {"Host": "tcp://staging.internal:2376"}}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staging := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	staging.AddContainer(fake.ContainerSpec{Name: "staging-proxy", Image: "nginx:1.25"})
+	var connected string
+	model := NewMainModel(newFleet(), Options{
+		Config: testConfig(),
+		Target: "default",
+		Connect: func(_ context.Context, dockerContext, host string) (docker.Runtime, error) {
+			connected = dockerContext
+			return staging, nil
+		},
+	})
+
+	d := uitest.New(t, model).Resize(120, 40).Keys("H")
+	uitest.Golden(t, "contexts_120x40", d.View())
+
+	d.Keys("down", "enter")
+	if connected != "staging" {
+		t.Fatalf("connected to %q, want staging", connected)
+	}
+	uitest.Golden(t, "context_switched_120x40", d.View())
+}
+
+func TestMainModelFleet(t *testing.T) {
+	edge := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	edge.AddContainer(fake.ContainerSpec{Name: "edge-proxy", Image: "nginx:1.25", CPU: 2, Memory: 32 << 20})
+	dead := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	dead.FailOn("ListContainers", fmt.Errorf("Cannot connect to the Docker daemon at tcp://build-3:2376"))
+
+	fleet, err := docker.NewFleet([]docker.FleetMember{
+		{Name: "build-1", Address: "tcp://build-1:2376", Runtime: newFleet()},
+		{Name: "edge", Address: "tcp://edge:2376", Runtime: edge},
+		{Name: "build-3", Address: "tcp://build-3:2376", Runtime: dead},
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := NewMainModel(fleet, Options{Config: testConfig(), Target: "fleet (3 hosts)"})
+
+	d := uitest.New(t, model).Resize(120, 40)
+	uitest.Golden(t, "fleet_120x40", d.View())
+
+	// Filter to the second host and stop its only container there
+	d.Keys("h", "h")
+	uitest.Golden(t, "fleet_filtered_120x40", d.View())
+	d.Keys("s")
+	containers, _ := edge.ListContainers(context.Background())
+	if containers[0].Status != fake.StatusExited {
+		t.Fatalf("edge-proxy is %s, want it stopped on its own host", containers[0].Status)
+	}
+}
+
+func TestMainModelPods(t *testing.T) {
+	// Podman lists the containers of a pod together, infra container included
+	r := fake.New(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r.AddContainer(fake.ContainerSpec{Name: "shop-infra", Image: "localhost/podman-pause:4.9.3", Pod: "shop"})
+	r.AddContainer(fake.ContainerSpec{Name: "shop-api", Image: "shop/api:2.1", Pod: "shop", CPU: 4, Memory: 96 << 20})
+	r.AddContainer(fake.ContainerSpec{Name: "web", Image: "nginx:1.25"})
+	model := NewMainModel(r, Options{Config: testConfig(), Target: "unix:///run/user/1000/podman/podman.sock"})
+
+	d := uitest.New(t, model).Resize(120, 40)
+	uitest.Golden(t, "pods_120x40", d.View())
+
+	// Stopping the pod header stops every container in the pod
+	d.Keys("s")
+	containers, _ := r.ListContainers(context.Background())
+	for _, c := range containers {
+		want := fake.StatusExited
+		if c.Pod == "" {
+			want = fake.StatusRunning
+		}
+		if c.Status != want {
+			t.Fatalf("%s is %s, want %s", c.Name, c.Status, want)
+		}
+	}
+}
+
+func TestMainModelCapabilities(t *testing.T) {
+	// A rootless daemon on cgroup v1 has no per-container stats
+	r := newFleet()
+	r.SetCapabilities(docker.Capabilities{
+		APIVersion:    "1.40",
+		OSType:        "linux",
+		CgroupVersion: "1",
+		StorageDriver: "fuse-overlayfs",
+		Swarm:         "inactive",
+		Rootless:      true,
+	})
+	d := uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40).Keys("down", "enter")
+	uitest.Golden(t, "rootless_120x40", d.View())
+
+	// Windows daemons have no file browser
+	r.SetCapabilities(docker.Capabilities{OSType: "windows", Swarm: "inactive"})
+	d = uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40).Keys("down", "f")
+	if !strings.Contains(d.View(), "file browsing not available") {
+		t.Fatalf("the file browser opened on a Windows daemon:\n%s", d.View())
+	}
+}
+
+// recording remembers the context of every container and network list
+type recording struct {
+	*fake.Runtime
+	containers, networks []context.Context
+}
+
+func (r *recording) ListContainers(ctx context.Context) ([]docker.Container, error) {
+	r.containers = append(r.containers, ctx)
+	return r.Runtime.ListContainers(ctx)
+}
+
+func (r *recording) ListNetworks(ctx context.Context) ([]docker.Network, error) {
+	r.networks = append(r.networks, ctx)
+	return r.Runtime.ListNetworks(ctx)
+}
+
+func TestMainModelCancellation(t *testing.T) {
+	r := &recording{Runtime: newFleet()}
+	d := uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40)
+
+	// Closing a screen cancels its requests, the main screen's go on
+	d.Keys("n")
+	if len(r.networks) == 0 {
+		t.Fatal("the networks screen listed no networks")
+	}
+	d.Keys("q")
+	for _, ctx := range r.networks {
+		if ctx.Err() == nil {
+			t.Fatal("closing the networks screen left its requests running")
+		}
+	}
+	for _, ctx := range r.containers {
+		if ctx.Err() != nil {
+			t.Fatal("closing the networks screen cancelled the container list")
+		}
+	}
+
+	// Quitting cancels everything
+	d.Keys("q")
+	if !d.Quit() {
+		t.Fatal("q did not quit")
+	}
+	for _, ctx := range r.containers {
+		if ctx.Err() == nil {
+			t.Fatal("quitting left the container list's requests running")
+		}
+	}
+}
+
+func TestMainModelReconnect(t *testing.T) {
+	r := newFleet()
+	cfg := testConfig()
+	cfg.Refresh.Health = config.Duration(time.Minute)
+	d := uitest.New(t, NewMainModel(r, Options{Config: cfg, Target: "default"})).Resize(120, 40)
+	if !strings.Contains(d.View(), "connected") {
+		t.Fatalf("the status bar does not show the connection:\n%s", d.View())
+	}
+	check := func() {
+		d.Send(tickMsg{kind: healthTick, gen: d.Model().(MainModel).tickers[healthTick].gen})
+	}
+
+	r.FailOn("Ping", fmt.Errorf("Cannot connect to the Docker daemon at unix:///var/run/docker.sock"))
+	check()
+	if !strings.Contains(d.View(), "disconnected, retrying in") {
+		t.Fatalf("a failed ping did not show the daemon disconnected:\n%s", d.View())
+	}
+
+	// A container started while the daemon was away shows once it is back
+	r.FailOn("Ping", nil)
+	r.AddContainer(fake.ContainerSpec{Name: "worker", Image: "acme/worker:3"})
+	check()
+	if view := d.View(); strings.Contains(view, "disconnected") || !strings.Contains(view, "worker") {
+		t.Fatalf("the daemon came back without a resync:\n%s", view)
+	}
+}
+
+func TestMainModelRunWizard(t *testing.T) {
+	r := newFleet()
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	model := NewMainModel(r, Options{Config: testConfig(), ConfigPath: cfgPath})
+	d := uitest.New(t, model).Resize(120, 40)
+
+	d.Keys("a", "nginx:1.25", "tab", "web", "enter")
+	d.Keys("MODE=prod 'GREETING=hello world'", "tab", "80:http", "enter")
+	if !strings.Contains(d.View(), "ports: invalid containerPort: http") {
+		t.Fatalf("an invalid port was not reported:\n%s", d.View())
+	}
+	d.Keys("ctrl+u", "8080:80", "enter")
+	d.Keys("/srv/www:/usr/share/nginx/html:ro", "tab", "tab", "unless-stopped", "enter")
+	d.Keys("tab", "512m", "enter")
+	uitest.Golden(t, "run_review_120x40", d.View())
+
+	// Saving a preset, then creating the container
+	d.Keys("s", "enter")
+	presets, err := config.LoadPresets(config.PresetsPath(cfgPath))
+	if err != nil || presets["web"].Image != "nginx:1.25" {
+		t.Fatalf("presets = %+v, %v, want web saved", presets, err)
+	}
+	d.Keys("enter")
+	containers, _ := r.ListContainers(context.Background())
+	var web docker.Container
+	for _, c := range containers {
+		if c.Name == "web" {
+			web = c
+		}
+	}
+	if web.Status != fake.StatusRunning || len(web.Ports) != 1 || web.Ports[0].PublicPort != 8080 {
+		t.Fatalf("web = %+v, want it running with 8080 published", web)
+	}
+
+	// The new container is selected and exports as a command or a service
+	d.Keys("e")
+	uitest.Golden(t, "export_run_120x40", d.View())
+	d.Keys("tab")
+	uitest.Golden(t, "export_compose_120x40", d.View())
+	d.Keys("q")
+
+	// The next run starts from the presets
+	d.Keys("a")
+	uitest.Golden(t, "run_presets_120x40", d.View())
+	d.Keys("down", "enter")
+	if !strings.Contains(d.View(), "-e 'GREETING=hello world'") {
+		t.Fatalf("the preset was not loaded into the review:\n%s", d.View())
+	}
+}
+
+func TestMainModelResources(t *testing.T) {
+	r := newFleet()
+	d := uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40)
+	d.Keys("down", "enter", "i", "tab", "tab")
+	uitest.Golden(t, "resources_120x40", d.View())
+
+	// Swap below the memory it includes is refused before reaching the daemon
+	d.Keys("e", "tab", "tab", "512m", "tab", "256m", "enter")
+	if !strings.Contains(d.View(), "swap: 256MiB is less than the memory limit") {
+		t.Fatalf("the swap limit was not reported:\n%s", d.View())
+	}
+	d.Keys("ctrl+u", "-1", "enter")
+	containers, _ := r.ListContainers(context.Background())
+	var id string
+	for _, c := range containers {
+		if c.Name == "shop-api-1" {
+			id = c.ID
+		}
+	}
+	res, err := r.ContainerResources(context.Background(), id)
+	if err != nil || res.Memory != 512<<20 || res.MemorySwap != -1 {
+		t.Fatalf("resources = %+v, %v, want 512m and unlimited swap", res, err)
+	}
+	uitest.Golden(t, "resources_updated_120x40", d.View())
+
+	// The main screen's stats show the new limit
+	d.Keys("q")
+	if !strings.Contains(d.View(), "/ 512.0 MiB") {
+		t.Errorf("the stats do not show the new limit:\n%s", d.View())
+	}
+
+	// Daemons that cannot change limits still show them
+	r.SetCapabilities(docker.Capabilities{OSType: "linux", CgroupVersion: "1", Rootless: true, Swarm: "inactive"})
+	d = uitest.New(t, NewMainModel(r, Options{Config: testConfig()})).Resize(120, 40).Keys("down", "i", "tab", "tab", "e")
+	if !strings.Contains(d.View(), "changing resource limits not available") {
+		t.Fatalf("limits could be edited on a rootless cgroup v1 daemon:\n%s", d.View())
+	}
+}
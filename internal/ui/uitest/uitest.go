@@ -0,0 +1,234 @@
+// Package uitest drives bubbletea models without a terminal. A Driver feeds
+// scripted messages to a model and runs the commands it returns in order,
+// so a test can render a screen at a given size and compare it with a
+// golden file.
+package uitest
+
+import (
+	"flag"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// update rewrites golden files instead of comparing against them:
+//
+//	go test ./internal/ui/... -update
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// cmdTimeout bounds how long a command may run. One that takes longer
+// fails the test, as it would hold up every scripted step after it.
+const cmdTimeout = time.Second
+
+// timers are the commands that wait on the clock, such as refresh ticks,
+// spinners, delayed messages and the blinking cursor of a focused input.
+// They are dropped without running, so screens only change on scripted
+// input; a test that needs one to fire sends its message instead.
+var timers = []string{
+	"github.com/charmbracelet/bubbletea.Tick.",
+	"github.com/charmbracelet/bubbletea.Every.",
+	"github.com/charmbracelet/bubbles/cursor.(*Model).BlinkCmd.",
+}
+
+// maxMessages stops a model that keeps producing messages
+const maxMessages = 1000
+
+func init() {
+	// Golden files hold plain text, whatever the terminal running the tests
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// Driver runs a model
+type Driver struct {
+	t     testing.TB
+	model tea.Model
+	quit  bool
+}
+
+// New starts a model by running its Init command
+func New(t testing.TB, model tea.Model) *Driver {
+	t.Helper()
+	d := &Driver{t: t, model: model}
+	d.run(model.Init())
+	return d
+}
+
+// Model returns the current model, which may be a different screen than
+// the one the driver started with
+func (d *Driver) Model() tea.Model {
+	return d.model
+}
+
+// Quit reports whether the model asked the program to exit
+func (d *Driver) Quit() bool {
+	return d.quit
+}
+
+// View renders the current screen with trailing spaces trimmed from every
+// line, so golden files are stable across editors
+func (d *Driver) View() string {
+	lines := strings.Split(d.model.View(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Resize sends a window size, as the terminal does on start and on resize
+func (d *Driver) Resize(width, height int) *Driver {
+	d.t.Helper()
+	return d.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Keys types keys by name, e.g. "j", "enter", "tab" or "shift+tab"
+func (d *Driver) Keys(keys ...string) *Driver {
+	d.t.Helper()
+	for _, k := range keys {
+		d.Send(Key(k))
+	}
+	return d
+}
+
+// Send delivers messages one at a time, running the commands each produces
+// before the next is sent
+func (d *Driver) Send(msgs ...tea.Msg) *Driver {
+	d.t.Helper()
+	for _, msg := range msgs {
+		d.deliver([]tea.Msg{msg})
+	}
+	return d
+}
+
+// run executes a command and delivers what it produces
+func (d *Driver) run(cmd tea.Cmd) {
+	d.t.Helper()
+	d.deliver(d.exec(cmd))
+}
+
+// deliver feeds messages to the model until no command produces more
+func (d *Driver) deliver(queue []tea.Msg) {
+	d.t.Helper()
+	for n := 0; len(queue) > 0; n++ {
+		if n >= maxMessages {
+			d.t.Fatalf("model produced more than %d messages", maxMessages)
+		}
+		msg := queue[0]
+		queue = queue[1:]
+
+		var cmd tea.Cmd
+		d.model, cmd = d.model.Update(msg)
+		queue = append(queue, d.exec(cmd)...)
+	}
+}
+
+// exec runs a command, expanding batches and sequences in order. Timers
+// and messages meant for the bubbletea runtime itself are dropped.
+func (d *Driver) exec(cmd tea.Cmd) []tea.Msg {
+	d.t.Helper()
+	if cmd == nil {
+		return nil
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(cmd).Pointer()).Name()
+	for _, prefix := range timers {
+		if strings.HasPrefix(name, prefix) {
+			return nil
+		}
+	}
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(cmdTimeout):
+		d.t.Fatalf("command %s blocked for more than %s", name, cmdTimeout)
+	}
+	if msg == nil {
+		return nil
+	}
+
+	// tea.Batch and tea.Sequence both produce a slice of commands
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+		var msgs []tea.Msg
+		for i := 0; i < v.Len(); i++ {
+			msgs = append(msgs, d.exec(v.Index(i).Interface().(tea.Cmd))...)
+		}
+		return msgs
+	}
+
+	if _, ok := msg.(tea.QuitMsg); ok {
+		d.quit = true
+		return nil
+	}
+	t := reflect.TypeOf(msg)
+	if t.PkgPath() == reflect.TypeOf(tea.KeyMsg{}).PkgPath() && !token.IsExported(t.Name()) {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+var namedKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"esc":       tea.KeyEscape,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	" ":         tea.KeySpace,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+d":    tea.KeyCtrlD,
+	"ctrl+u":    tea.KeyCtrlU,
+}
+
+// Key builds the message a terminal sends for a key name
+func Key(name string) tea.KeyMsg {
+	if t, ok := namedKeys[name]; ok {
+		msg := tea.KeyMsg{Type: t}
+		if t == tea.KeySpace {
+			msg.Runes = []rune{' '}
+		}
+		return msg
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// Golden compares got with testdata/<name>.golden, or rewrites the file
+// when the tests run with -update
+func Golden(t testing.TB, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the golden file (run with -update to accept)\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
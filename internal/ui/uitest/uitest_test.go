@@ -0,0 +1,76 @@
+package uitest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recorder captures a fatal failure instead of ending the test
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failure = fmt.Sprintf(format, args...)
+	panic(r)
+}
+
+// model counts the messages it receives and returns cmd from Init
+type model struct {
+	cmd  tea.Cmd
+	msgs []tea.Msg
+}
+
+func (m *model) Init() tea.Cmd { return m.cmd }
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.msgs = append(m.msgs, msg)
+	return m, nil
+}
+
+func (m *model) View() string { return "" }
+
+func TestTimersDropped(t *testing.T) {
+	m := &model{cmd: tea.Batch(
+		tea.Tick(time.Hour, func(time.Time) tea.Msg { return "tick" }),
+		tea.Every(time.Hour, func(time.Time) tea.Msg { return "every" }),
+		func() tea.Msg { return "now" },
+	)}
+	start := time.Now()
+	New(t, m)
+	if len(m.msgs) != 1 || m.msgs[0] != "now" {
+		t.Errorf("messages = %v, want [now]", m.msgs)
+	}
+	if elapsed := time.Since(start); elapsed > cmdTimeout {
+		t.Errorf("took %s, timers were waited for", elapsed)
+	}
+}
+
+func TestBlockingCommandFails(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	m := &model{cmd: func() tea.Msg {
+		<-block
+		return nil
+	}}
+
+	r := &recorder{TB: t}
+	func() {
+		defer func() {
+			if p := recover(); p != nil && p != r {
+				panic(p)
+			}
+		}()
+		New(r, m)
+	}()
+	if !strings.Contains(r.failure, "blocked for more than") {
+		t.Errorf("failure = %q, want a blocked command", r.failure)
+	}
+}
@@ -0,0 +1,104 @@
+package views
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// activityDelay is how long an operation runs before its spinner shows, so
+// quick ones do not flicker
+const activityDelay = 300 * time.Millisecond
+
+// activityIDs numbers tracked operations, so the end of an earlier one does
+// not hide the spinner of a later one
+var activityIDs atomic.Int64
+
+type activityState int
+
+const (
+	activityStarted activityState = iota
+	activityShown
+	activityDone
+)
+
+// ActivityMsg reports the progress of an operation started with track
+type ActivityMsg struct {
+	id    int64
+	label string
+	state activityState
+}
+
+// track runs cmds one after another as a single operation, e.g. a stop and
+// the refresh after it, with a spinner labelled label while it takes long
+func track(label string, cmds ...tea.Cmd) tea.Cmd {
+	id := activityIDs.Add(1)
+	// A nested sequence would run on its own, so the cmds are inlined
+	seq := []tea.Cmd{func() tea.Msg { return ActivityMsg{id: id, label: label} }}
+	seq = append(seq, cmds...)
+	seq = append(seq, func() tea.Msg { return ActivityMsg{id: id, state: activityDone} })
+	return tea.Sequence(seq...)
+}
+
+// Activity shows a spinner and the elapsed time of the latest tracked
+// operation, e.g. "⠋ Stopping web... 3s"
+type Activity struct {
+	spinner spinner.Model
+	id      int64
+	label   string
+	started time.Time
+	visible bool
+}
+
+// NewActivity creates an idle activity indicator
+func NewActivity() Activity {
+	return Activity{spinner: spinner.New(spinner.WithSpinner(spinner.MiniDot))}
+}
+
+// Visible reports whether an operation is taking long enough to show
+func (a Activity) Visible() bool {
+	return a.visible
+}
+
+// Update follows ActivityMsg and spinner.TickMsg, ignoring other messages
+func (a Activity) Update(msg tea.Msg) (Activity, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ActivityMsg:
+		switch msg.state {
+		case activityStarted:
+			a.id, a.label, a.started, a.visible = msg.id, msg.label, time.Now(), false
+			return a, tea.Tick(activityDelay, func(time.Time) tea.Msg {
+				return ActivityMsg{id: msg.id, state: activityShown}
+			})
+		case activityShown:
+			if msg.id == a.id {
+				a.visible = true
+				return a, a.spinner.Tick
+			}
+		case activityDone:
+			if msg.id == a.id {
+				a.id, a.visible = 0, false
+			}
+		}
+	case spinner.TickMsg:
+		if a.visible {
+			var cmd tea.Cmd
+			a.spinner, cmd = a.spinner.Update(msg)
+			return a, cmd
+		}
+	}
+	return a, nil
+}
+
+// View renders the spinner line, empty while nothing is shown
+func (a Activity) View() string {
+	if !a.visible {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(palette.Accent).Render(a.spinner.View()) +
+		fmt.Sprintf(" %s... %s", a.label, time.Since(a.started).Round(time.Second))
+}
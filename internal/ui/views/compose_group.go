@@ -0,0 +1,199 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// podGroup prefixes the group of containers in a Podman pod, which cannot
+// clash with compose project names
+const podGroup = "pod:"
+
+// groupOf is the section a container is listed under: its compose project,
+// its Podman pod, or none
+func groupOf(c docker.Container) string {
+	if project := c.Project(); project != "" {
+		return project
+	}
+	if c.Pod != "" {
+		return podGroup + c.Pod
+	}
+	return ""
+}
+
+// ProjectItem is a collapsible header for a compose project or a pod
+type ProjectItem struct {
+	name      string // group of the containers, see groupOf
+	host      string // column with the fleet host, empty for one daemon
+	pod       bool
+	services  int
+	running   int
+	collapsed bool
+}
+
+func (i ProjectItem) Title() string {
+	// In a fleet the name is qualified with the host, which has its column
+	name := strings.TrimPrefix(i.name, podGroup)
+	name = name[strings.Index(name, "/")+1:]
+	if i.collapsed {
+		return i.host + "▸ " + name
+	}
+	return i.host + "▾ " + name
+}
+
+func (i ProjectItem) Description() string {
+	if i.pod {
+		return fmt.Sprintf("pod • %d container(s) • %d running", i.services, i.running)
+	}
+	return fmt.Sprintf("compose • %d service(s) • %d running", i.services, i.running)
+}
+
+// label names the project or pod in questions
+func (i ProjectItem) label() string {
+	if i.pod {
+		return "pod " + strings.TrimPrefix(i.name, podGroup)
+	}
+	return i.name
+}
+
+func (i ProjectItem) FilterValue() string { return i.name }
+
+// hostColumn pads a fleet host name to the width of the longest one, or
+// returns nothing outside a fleet
+func hostColumn(host string, width int) string {
+	if width == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-*s  ", width, host)
+}
+
+// groupContainers orders containers under their compose project and pod
+// headers. Containers that belong to neither are listed last. In a fleet
+// every row starts with the host it is on.
+func groupContainers(containers []docker.Container, collapsed map[string]bool) []list.Item {
+	projects := map[string][]docker.Container{}
+	var names []string
+	var standalone []docker.Container
+
+	width := 0
+	for _, c := range containers {
+		width = max(width, len(c.Host))
+	}
+
+	for _, c := range containers {
+		project := groupOf(c)
+		if project == "" {
+			standalone = append(standalone, c)
+			continue
+		}
+		if _, ok := projects[project]; !ok {
+			names = append(names, project)
+		}
+		projects[project] = append(projects[project], c)
+	}
+	sort.Strings(names)
+
+	var items []list.Item
+	for _, name := range names {
+		members := projects[name]
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Service() != members[j].Service() {
+				return members[i].Service() < members[j].Service()
+			}
+			return members[i].Name < members[j].Name
+		})
+
+		pod := strings.HasPrefix(name, podGroup)
+		header := ProjectItem{name: name, host: hostColumn(members[0].Host, width), pod: pod, services: len(members), collapsed: collapsed[name]}
+		for _, c := range members {
+			if c.Status == "running" {
+				header.running++
+			}
+		}
+		items = append(items, header)
+
+		if collapsed[name] {
+			continue
+		}
+		for i, c := range members {
+			branch := "├─ "
+			if i == len(members)-1 {
+				branch = "└─ "
+			}
+			title := c.Service() + " (" + c.Name + ")"
+			if pod {
+				title = c.Name
+			}
+			items = append(items, ContainerItem{
+				id:      c.ID,
+				title:   hostColumn(c.Host, width) + branch + title,
+				name:    c.Ref(),
+				status:  c.Status,
+				project: name,
+			})
+		}
+	}
+
+	for _, c := range standalone {
+		items = append(items, ContainerItem{
+			id:     c.ID,
+			title:  hostColumn(c.Host, width) + c.Name,
+			name:   c.Ref(),
+			status: c.Status,
+		})
+	}
+
+	return items
+}
+
+func (m *ContainerListModel) projectAction(project string, action func(context.Context, string) error) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(m.ctx, project); err != nil {
+			return failed(err)
+		}
+		return nil
+	}
+}
+
+// groupAction runs a compose project action, or for a pod the matching
+// container action on each of its containers
+func (m *ContainerListModel) groupAction(item ProjectItem, project, container func(context.Context, string) error) tea.Cmd {
+	if !item.pod {
+		return m.projectAction(item.name, project)
+	}
+	var ids []string
+	for _, c := range m.containers {
+		if groupOf(c) == item.name {
+			ids = append(ids, c.ID)
+		}
+	}
+	return func() tea.Msg {
+		var errs []error
+		for _, id := range ids {
+			if err := container(m.ctx, id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return failed(err)
+		}
+		return nil
+	}
+}
+
+func (m *ContainerListModel) fetchProjectLogs(project string) tea.Cmd {
+	return func() tea.Msg {
+		logs, err := m.dockerClient.GetProjectLogs(m.ctx, project, m.cfg.Logs.Tail)
+		if err != nil {
+			return failed(err)
+		}
+		return LogsMsg{Logs: logs}
+	}
+}
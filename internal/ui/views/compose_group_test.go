@@ -0,0 +1,119 @@
+package views
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+func service(id, name, project, svc, status string) docker.Container {
+	return docker.Container{ID: id, Name: name, Status: status, Labels: map[string]string{
+		docker.ComposeProjectLabel: project,
+		docker.ComposeServiceLabel: svc,
+	}}
+}
+
+// rows renders items as their titles, with descriptions for headers
+func rows(items []list.Item) []string {
+	var result []string
+	for _, item := range items {
+		switch item := item.(type) {
+		case ProjectItem:
+			result = append(result, item.Title()+" | "+item.Description())
+		case ContainerItem:
+			result = append(result, item.Title())
+		}
+	}
+	return result
+}
+
+func TestGroupContainers(t *testing.T) {
+	shop := []docker.Container{
+		service("3", "shop-web-1", "shop", "web", "running"),
+		service("1", "shop-db-1", "shop", "db", "exited"),
+		service("2", "shop-web-2", "shop", "web", "running"),
+	}
+	blog := service("4", "blog-app-1", "blog", "app", "running")
+	lone := docker.Container{ID: "5", Name: "scratch", Status: "running"}
+	pod := []docker.Container{
+		{ID: "6", Name: "api", Status: "running", Pod: "billing"},
+		{ID: "7", Name: "api-infra", Status: "running", Pod: "billing"},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		containers []docker.Container
+		collapsed  map[string]bool
+		want       []string
+	}{
+		{
+			name:       "no projects",
+			containers: []docker.Container{lone},
+			want:       []string{"scratch"},
+		},
+		{
+			// Projects by name, services by name, then containers without one
+			name:       "projects first",
+			containers: append([]docker.Container{lone, blog}, shop...),
+			want: []string{
+				"▾ blog | compose • 1 service(s) • 1 running",
+				"└─ app (blog-app-1)",
+				"▾ shop | compose • 3 service(s) • 2 running",
+				"├─ db (shop-db-1)",
+				"├─ web (shop-web-1)",
+				"└─ web (shop-web-2)",
+				"scratch",
+			},
+		},
+		{
+			name:       "collapsed",
+			containers: append([]docker.Container{blog}, shop...),
+			collapsed:  map[string]bool{"shop": true},
+			want: []string{
+				"▾ blog | compose • 1 service(s) • 1 running",
+				"└─ app (blog-app-1)",
+				"▸ shop | compose • 3 service(s) • 2 running",
+			},
+		},
+		{
+			name:       "pod",
+			containers: pod,
+			want: []string{
+				"▾ billing | pod • 2 container(s) • 2 running",
+				"├─ api",
+				"└─ api-infra",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rows(groupContainers(tc.containers, tc.collapsed)); !slices.Equal(got, tc.want) {
+				t.Errorf("rows:\n%q\nwant:\n%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGroupContainersFleet(t *testing.T) {
+	// The same project on two hosts is two groups, each under its host
+	prod := service("prod/1", "shop-web-1", "prod/shop", "web", "running")
+	prod.Host = "prod"
+	staging := service("staging/1", "shop-web-1", "staging/shop", "web", "exited")
+	staging.Host = "staging"
+
+	items := groupContainers([]docker.Container{staging, prod}, nil)
+	want := []string{
+		"prod     ▾ shop | compose • 1 service(s) • 1 running",
+		"prod     └─ web (shop-web-1)",
+		"staging  ▾ shop | compose • 1 service(s) • 0 running",
+		"staging  └─ web (shop-web-1)",
+	}
+	if got := rows(items); !slices.Equal(got, want) {
+		t.Errorf("rows:\n%q\nwant:\n%q", got, want)
+	}
+	// Actions refer to the container on its own host
+	if item := items[1].(ContainerItem); item.id != "prod/1" || item.name != "prod/shop-web-1" || item.project != "prod/shop" {
+		t.Errorf("prod container = %+v", item)
+	}
+}
@@ -0,0 +1,544 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/compose"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+type composeMode int
+
+const (
+	composeBrowseMode composeMode = iota
+	composeOpenMode
+	composeConfirmMode
+)
+
+// OpenComposeMsg asks the main model to open the compose view. Files may be
+// empty, in which case the user is prompted for a compose file.
+type OpenComposeMsg struct {
+	Project string
+	Files   []string
+}
+
+type composeLoadedMsg struct {
+	project    *compose.Project
+	containers map[string][]docker.Container
+}
+
+type composeDiffMsg struct {
+	service string
+	diffs   []docker.ConfigDiff
+}
+
+type composeDoneMsg struct {
+	status string
+}
+
+// composeService is a row in the services list
+type composeService struct {
+	name     string
+	declared bool
+	status   string
+}
+
+// ComposeViewModel shows a compose project's declared services next to
+// what is actually running and can bring the project up or down
+type ComposeViewModel struct {
+	dockerClient docker.Runtime
+	ctx          context.Context
+	cancel       context.CancelFunc
+	projectName  string
+	files        []string
+	project      *compose.Project
+	services     []composeService
+	cursor       int
+	diffs        map[string][]docker.ConfigDiff
+	details      viewport.Model
+	input        textinput.Model
+	mode         composeMode
+	cfg          config.Config
+	help         helpToggle
+	pending      string // action awaiting confirmation
+	busy         bool
+	status       string
+	activity     Activity
+	err          error
+	width        int
+	height       int
+	parentModel  tea.Model
+}
+
+// NewComposeView creates the compose view for a project
+func NewComposeView(ctx context.Context, dockerClient docker.Runtime, projectName string, files []string, cfg config.Config, width, height int, parentModel tea.Model) ComposeViewModel {
+	ti := textinput.New()
+	ti.Prompt = "Compose file: "
+	ti.SetValue("docker-compose.yml")
+	ti.CursorEnd()
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := ComposeViewModel{
+		dockerClient: dockerClient,
+		ctx:          ctx,
+		cancel:       cancel,
+		projectName:  projectName,
+		files:        files,
+		diffs:        map[string][]docker.ConfigDiff{},
+		details:      viewport.New(0, 0),
+		input:        ti,
+		cfg:          cfg,
+		activity:     NewActivity(),
+		parentModel:  parentModel,
+	}
+	if len(files) == 0 {
+		m.mode = composeOpenMode
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m ComposeViewModel) Init() tea.Cmd {
+	if m.mode == composeOpenMode {
+		return m.input.Focus()
+	}
+	return m.load()
+}
+
+func (m ComposeViewModel) load() tea.Cmd {
+	ctx, client, files, name := m.ctx, m.dockerClient, m.files, m.projectName
+	return func() tea.Msg {
+		project, err := compose.Load(files, name)
+		if err != nil {
+			return failed(err)
+		}
+		containers, err := client.ServiceContainers(ctx, project.Name)
+		if err != nil {
+			return failed(err)
+		}
+		return composeLoadedMsg{project: project, containers: containers}
+	}
+}
+
+func (m ComposeViewModel) diff(service string) tea.Cmd {
+	ctx, client, project := m.ctx, m.dockerClient, m.project
+	return func() tea.Msg {
+		diffs, err := client.DiffService(ctx, project, service)
+		if err != nil {
+			return failed(err)
+		}
+		return composeDiffMsg{service: service, diffs: diffs}
+	}
+}
+
+// actionLabel describes a running project action, e.g. "Bringing shop up"
+func (m ComposeViewModel) actionLabel(action string) string {
+	switch action {
+	case "up":
+		return "Bringing " + m.project.Name + " up"
+	case "down":
+		return "Taking " + m.project.Name + " down"
+	}
+	return "Recreating " + m.services[m.cursor].name
+}
+
+func (m ComposeViewModel) run(action string) tea.Cmd {
+	ctx, client, project := m.ctx, m.dockerClient, m.project
+	service := ""
+	if m.cursor < len(m.services) {
+		service = m.services[m.cursor].name
+	}
+	return track(m.actionLabel(action), func() tea.Msg {
+		var err error
+		var status string
+		switch action {
+		case "up":
+			err, status = client.ComposeUp(ctx, project), "Project "+project.Name+" is up"
+		case "down":
+			err, status = client.ComposeDown(ctx, project), "Project "+project.Name+" is down"
+		case "recreate":
+			err, status = client.RecreateService(ctx, project, service), "Recreated "+service
+		}
+		if err != nil {
+			return failed(err)
+		}
+		return composeDoneMsg{status: status}
+	})
+}
+
+func (m *ComposeViewModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.details.Width = width - width/3 - 8
+	m.details.Height = height - 8
+}
+
+// buildServices merges declared services with containers found by label
+func (m *ComposeViewModel) buildServices(containers map[string][]docker.Container) {
+	m.services = nil
+	seen := map[string]bool{}
+	for _, s := range m.project.Services {
+		seen[s.Name] = true
+		status := "not created"
+		if ctrs := containers[s.Name]; len(ctrs) > 0 {
+			status = ctrs[0].Status
+		}
+		m.services = append(m.services, composeService{name: s.Name, declared: true, status: status})
+	}
+
+	// Services that run under the project label but are no longer declared
+	var orphans []string
+	for name := range containers {
+		if !seen[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		m.services = append(m.services, composeService{name: name, status: containers[name][0].Status})
+	}
+
+	if m.cursor >= len(m.services) {
+		m.cursor = 0
+	}
+}
+
+// refreshDetails renders the selected service's declaration and diff
+func (m *ComposeViewModel) refreshDetails() {
+	if m.project == nil || m.cursor >= len(m.services) {
+		m.details.SetContent("")
+		return
+	}
+
+	row := m.services[m.cursor]
+	s, ok := m.project.Service(row.name)
+	if !ok {
+		m.details.SetContent(browserErrorStyle.Render(row.name + " is running but not declared in the compose file"))
+		return
+	}
+
+	lines := []string{
+		titleStyle.Render(s.Name),
+		labelStyle.Render("Container: ") + valueStyle.Render(m.project.ContainerName(s)),
+		labelStyle.Render("Image:     ") + valueStyle.Render(s.Image),
+		labelStyle.Render("Command:   ") + valueStyle.Render(orDash(strings.Join(s.Command, " "))),
+		labelStyle.Render("Restart:   ") + valueStyle.Render(orDash(s.Restart)),
+		labelStyle.Render("Ports:     ") + valueStyle.Render(orDash(strings.Join(s.Ports, ", "))),
+		labelStyle.Render("Volumes:   ") + valueStyle.Render(orDash(strings.Join(s.Volumes, ", "))),
+		labelStyle.Render("Depends:   ") + valueStyle.Render(orDash(strings.Join(s.DependsOn, ", "))),
+	}
+
+	if s.NetworkMode != "" {
+		lines = append(lines, labelStyle.Render("Network:   ")+valueStyle.Render("mode "+s.NetworkMode))
+	} else {
+		var nets []string
+		for key := range m.project.ServiceNetworks(s) {
+			nets = append(nets, key)
+		}
+		sort.Strings(nets)
+		lines = append(lines, labelStyle.Render("Networks:  ")+valueStyle.Render(strings.Join(nets, ", ")))
+	}
+
+	if diffs, ok := m.diffs[s.Name]; ok {
+		lines = append(lines, "", titleStyle.Render("Declared vs running"))
+		if len(diffs) == 0 {
+			lines = append(lines, browserStatusStyle.Render("  in sync"))
+		}
+		for _, d := range diffs {
+			lines = append(lines,
+				labelStyle.Render("  "+d.Field),
+				"    - declared: "+valueStyle.Render(orDash(d.Declared)),
+				"    + running:  "+browserErrorStyle.Render(orDash(d.Running)))
+		}
+	}
+
+	m.details.SetContent(strings.Join(lines, "\n"))
+}
+
+// Update implements tea.Model
+func (m ComposeViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case composeLoadedMsg:
+		m.err = nil
+		m.project = msg.project
+		m.projectName = msg.project.Name
+		m.buildServices(msg.containers)
+		m.refreshDetails()
+		return m, nil
+
+	case composeDiffMsg:
+		m.err = nil
+		m.diffs[msg.service] = msg.diffs
+		m.refreshDetails()
+		return m, nil
+
+	case composeDoneMsg:
+		m.busy = false
+		m.err = nil
+		m.status = msg.status
+		m.diffs = map[string][]docker.ConfigDiff{}
+		return m, m.load()
+
+	case ErrMsg:
+		m.busy = false
+		m.err = msg.Err
+		return m, nil
+
+	case ActivityMsg, spinner.TickMsg:
+		var cmd tea.Cmd
+		m.activity, cmd = m.activity.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case composeOpenMode:
+			return m.updateOpen(msg)
+		case composeConfirmMode:
+			return m.updateConfirm(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.mode == composeOpenMode {
+		m.input, cmd = m.input.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m ComposeViewModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.busy {
+		return m, nil
+	}
+
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Compose) {
+	case "back":
+		m.cancel()
+		return m.parentModel, nil
+	case "down":
+		if m.cursor < len(m.services)-1 {
+			m.cursor++
+			m.refreshDetails()
+		}
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+			m.refreshDetails()
+		}
+	case "refresh":
+		return m, m.load()
+	case "open":
+		m.mode = composeOpenMode
+		return m, m.input.Focus()
+	case "diff":
+		if m.project != nil && m.cursor < len(m.services) && m.services[m.cursor].declared {
+			m.status = "Comparing " + m.services[m.cursor].name + "..."
+			return m, m.diff(m.services[m.cursor].name)
+		}
+	case "project_up":
+		if m.project != nil {
+			m.busy = true
+			m.status = m.actionLabel("up") + "..."
+			return m, m.run("up")
+		}
+	case "project_down":
+		if m.project != nil {
+			m.pending = "down"
+			if !m.cfg.Confirm.ComposeDown {
+				return m.runPending()
+			}
+			m.mode = composeConfirmMode
+		}
+	case "recreate":
+		if m.project != nil && m.cursor < len(m.services) && m.services[m.cursor].declared {
+			m.pending = "recreate"
+			if !m.cfg.Confirm.Recreate {
+				return m.runPending()
+			}
+			m.mode = composeConfirmMode
+		}
+	case "scroll_down":
+		m.details.HalfViewDown()
+	case "scroll_up":
+		m.details.HalfViewUp()
+	}
+	return m, nil
+}
+
+func (m ComposeViewModel) updateOpen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		if m.project == nil {
+			m.cancel()
+			return m.parentModel, nil
+		}
+		m.input.Blur()
+		m.mode = composeBrowseMode
+		return m, nil
+	case "submit":
+		path := strings.TrimSpace(m.input.Value())
+		if path == "" {
+			return m, nil
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.files = []string{abs}
+		// An explicitly opened file names its own project
+		m.projectName = ""
+		m.diffs = map[string][]docker.ConfigDiff{}
+		m.input.Blur()
+		m.mode = composeBrowseMode
+		return m, m.load()
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m ComposeViewModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = composeBrowseMode
+	if !m.cfg.Keys.Matches(msg, keymap.Confirm, "yes") {
+		m.pending = ""
+		return m, nil
+	}
+	return m.runPending()
+}
+
+// runPending starts the action that was waiting for confirmation
+func (m ComposeViewModel) runPending() (tea.Model, tea.Cmd) {
+	action := m.pending
+	m.pending = ""
+	m.busy = true
+	m.status = m.actionLabel(action) + "..."
+	return m, m.run(action)
+}
+
+func (m ComposeViewModel) servicesView() string {
+	if m.project == nil {
+		return noSelectionStyle.Render("No compose file loaded")
+	}
+
+	lines := []string{
+		titleStyle.Render("Project " + m.project.Name),
+		dimStyle.Render(strings.Join(m.project.Files, ", ")),
+		"",
+	}
+	for i, s := range m.services {
+		name := pad(s.name, 20)
+		status := valueStyle.Render(s.status)
+		switch {
+		case !s.declared:
+			status = browserErrorStyle.Render(s.status + " (orphan)")
+		case s.status != "running":
+			status = browserErrorStyle.Render(s.status)
+		}
+		prefix := "  "
+		if i == m.cursor {
+			prefix = cursorStyle.Render("> ")
+			name = cursorStyle.Render(name)
+		}
+		lines = append(lines, prefix+name+" "+status)
+	}
+
+	if len(m.project.Volumes) > 0 {
+		lines = append(lines, "", labelStyle.Render("Volumes"))
+		var names []string
+		for _, v := range m.project.Volumes {
+			names = append(names, "  "+v.Name)
+		}
+		sort.Strings(names)
+		lines = append(lines, names...)
+	}
+	lines = append(lines, "", labelStyle.Render("Networks"))
+	var nets []string
+	for _, n := range m.project.Networks {
+		nets = append(nets, "  "+n.Name)
+	}
+	sort.Strings(nets)
+	lines = append(lines, nets...)
+
+	return strings.Join(lines, "\n")
+}
+
+// View implements tea.Model
+func (m ComposeViewModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Compose, keymap.Form, keymap.Confirm, keymap.Global)
+	}
+
+	keys := m.cfg.Keys
+	var help string
+	switch m.mode {
+	case composeOpenMode:
+		help = keys.Short(keymap.Form, "submit", "cancel")
+	case composeConfirmMode:
+		help = keys.Short(keymap.Confirm, "yes", "no")
+	default:
+		help = keys.Short(keymap.Compose, "diff", "project_up", "project_down", "recreate", "open", "refresh", "back") +
+			" • " + keys.Short(keymap.Global, "help")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.activity.Visible() {
+		status = browserStatusStyle.Render(m.activity.View())
+	}
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	var right string
+	switch m.mode {
+	case composeOpenMode:
+		right = m.input.View()
+	case composeConfirmMode:
+		question := fmt.Sprintf("Stop and remove all containers and networks of %s?", m.project.Name)
+		if m.pending == "recreate" {
+			question = fmt.Sprintf("Remove and recreate %s?", m.services[m.cursor].name)
+		}
+		right = lipgloss.JoinVertical(lipgloss.Left, m.details.View(), "", titleStyle.Render(question))
+	default:
+		right = m.details.View()
+	}
+
+	left := lipgloss.NewStyle().
+		Width(m.width/3).
+		Height(m.height-4).
+		Padding(0, 1).
+		Render(m.servicesView())
+
+	rightPane := statsBoxStyle.
+		Width(m.width - m.width/3 - 4).
+		Height(m.height - 4).
+		Render(right)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, left, rightPane),
+		status,
+		browserHelpStyle.Render(help))
+}
@@ -0,0 +1,714 @@
+package views
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/go-units"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/ui/components"
+)
+
+type detailTab int
+
+const (
+	overviewTab detailTab = iota
+	networksTab
+	resourcesTab
+)
+
+var detailTabNames = []string{"Overview", "Networks", "Resources"}
+
+type detailMode int
+
+const (
+	detailBrowseMode detailMode = iota
+	detailConnectMode
+	detailDisconnectMode
+	detailResourcesMode
+)
+
+// resourceFields are the inputs of the resources form, with the option an
+// *docker.InvalidOptionError names for each
+var resourceFields = []struct {
+	label, option, placeholder string
+}{
+	{"CPU shares", "cpu shares", "1024 when empty"},
+	{"CPUs", "cpus", "no limit, e.g. 1.5"},
+	{"Memory", "memory", "no limit, e.g. 512m"},
+	{"Memory + swap", "swap", "-1 for unlimited"},
+	{"PIDs limit", "pids", "no limit"},
+	{"Restart", "restart", "no, always, unless-stopped or on-failure[:N]"},
+}
+
+// OpenContainerDetailMsg asks the main model to open a container's detail view
+type OpenContainerDetailMsg struct {
+	ID   string
+	Name string
+}
+
+type containerDetailsMsg struct {
+	details *docker.ContainerDetails
+}
+
+type availableNetworksMsg struct {
+	networks []docker.Network
+}
+
+type membershipChangedMsg struct {
+	status string
+}
+
+type resourcesMsg struct {
+	resources docker.Resources
+}
+
+// ResourcesUpdatedMsg is sent to the parent after a container's limits
+// changed, so its stats show the new ones
+type ResourcesUpdatedMsg struct {
+	ID string
+}
+
+// ContainerDetailModel shows a container's configuration and lets the user
+// manage its network memberships and resource limits
+type ContainerDetailModel struct {
+	dockerClient  docker.Runtime
+	caps          docker.Capabilities
+	ctx           context.Context
+	cancel        context.CancelFunc
+	containerID   string
+	containerName string
+	details       *docker.ContainerDetails
+	tab           detailTab
+	mode          detailMode
+	cursor        int
+	networks      []docker.Network
+	pickCursor    int
+	aliasInput    textinput.Model
+	resources     *docker.Resources
+	resourceForm  components.FormModel
+	cfg           config.Config
+	help          helpToggle
+	status        string
+	err           error
+	width         int
+	height        int
+	parentModel   tea.Model
+}
+
+// NewContainerDetail creates the detail view for a container
+func NewContainerDetail(ctx context.Context, dockerClient docker.Runtime, caps docker.Capabilities, containerID, containerName string, cfg config.Config, width, height int, parentModel tea.Model) ContainerDetailModel {
+	ti := textinput.New()
+	ti.Prompt = "Aliases: "
+	ti.Placeholder = "comma separated (optional)"
+
+	labels := make([]string, len(resourceFields))
+	for i, f := range resourceFields {
+		labels[i] = f.label
+	}
+	form := components.NewForm(labels...)
+	for i, f := range resourceFields {
+		form.SetPlaceholder(i, f.placeholder)
+	}
+	form.SetKeys(cfg.Keys)
+
+	ctx, cancel := context.WithCancel(ctx)
+	return ContainerDetailModel{
+		dockerClient:  dockerClient,
+		caps:          caps,
+		ctx:           ctx,
+		cancel:        cancel,
+		containerID:   containerID,
+		containerName: containerName,
+		aliasInput:    ti,
+		resourceForm:  form,
+		cfg:           cfg,
+		width:         width,
+		height:        height,
+		parentModel:   parentModel,
+	}
+}
+
+// Init implements tea.Model
+func (m ContainerDetailModel) Init() tea.Cmd {
+	return tea.Batch(m.fetchDetails(), m.fetchResources())
+}
+
+func (m ContainerDetailModel) fetchDetails() tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		details, err := client.InspectContainer(ctx, id)
+		if err != nil {
+			return failed(err)
+		}
+		return containerDetailsMsg{details: details}
+	}
+}
+
+func (m ContainerDetailModel) fetchResources() tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		resources, err := client.ContainerResources(ctx, id)
+		if err != nil {
+			return failed(err)
+		}
+		return resourcesMsg{resources: resources}
+	}
+}
+
+func (m ContainerDetailModel) updateResources(r docker.Resources) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		if err := client.UpdateResources(ctx, id, r); err != nil {
+			return failed(err)
+		}
+		return ResourcesUpdatedMsg{ID: id}
+	}
+}
+
+func (m ContainerDetailModel) fetchAvailableNetworks() tea.Cmd {
+	ctx, client, host := m.ctx, m.dockerClient, docker.HostOf(m.containerID)
+	attached := map[string]bool{}
+	if m.details != nil {
+		for _, n := range m.details.Networks {
+			attached[n.Name] = true
+		}
+	}
+	return func() tea.Msg {
+		networks, err := client.ListNetworks(ctx)
+		if err != nil {
+			return failed(err)
+		}
+		// A fleet lists the networks of every host, but a container can
+		// only join those of its own
+		var available []docker.Network
+		for _, n := range networks {
+			if n.Host != host || attached[n.Name] {
+				continue
+			}
+			if name := n.LocalName(); name != "none" && name != "host" {
+				available = append(available, n)
+			}
+		}
+		return availableNetworksMsg{networks: available}
+	}
+}
+
+func (m ContainerDetailModel) connect(n docker.Network, aliases []string) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		if err := client.ConnectNetwork(ctx, n.ID, id, aliases); err != nil {
+			return failed(err)
+		}
+		return membershipChangedMsg{status: "Connected to " + n.Name}
+	}
+}
+
+func (m ContainerDetailModel) disconnect(n docker.ContainerNetwork) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		if err := client.DisconnectNetwork(ctx, n.NetworkID, id); err != nil {
+			return failed(err)
+		}
+		return membershipChangedMsg{status: "Disconnected from " + n.Name}
+	}
+}
+
+// Update implements tea.Model
+func (m ContainerDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case containerDetailsMsg:
+		m.details = msg.details
+		if m.cursor >= len(m.details.Networks) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case availableNetworksMsg:
+		if len(msg.networks) == 0 {
+			m.err = fmt.Errorf("no other networks available")
+			return m, nil
+		}
+		m.networks = msg.networks
+		m.pickCursor = 0
+		m.aliasInput.SetValue("")
+		m.mode = detailConnectMode
+		return m, m.aliasInput.Focus()
+
+	case membershipChangedMsg:
+		m.err = nil
+		m.status = msg.status
+		return m, m.fetchDetails()
+
+	case resourcesMsg:
+		m.resources = &msg.resources
+		return m, nil
+
+	case ResourcesUpdatedMsg:
+		m.err = nil
+		m.status = "Limits updated"
+		var cmd tea.Cmd
+		m.parentModel, cmd = m.parentModel.Update(msg)
+		return m, tea.Batch(m.fetchResources(), cmd)
+
+	case ContainerStatsMsg:
+		// The stats asked for after an update, shown once this view closes
+		var cmd tea.Cmd
+		m.parentModel, cmd = m.parentModel.Update(msg)
+		return m, cmd
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case detailConnectMode:
+			return m.updateConnect(msg)
+		case detailDisconnectMode:
+			return m.updateDisconnect(msg)
+		case detailResourcesMode:
+			return m.updateResourceForm(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.mode {
+	case detailConnectMode:
+		m.aliasInput, cmd = m.aliasInput.Update(msg)
+	case detailResourcesMode:
+		m.resourceForm, cmd = m.resourceForm.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m ContainerDetailModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	action := m.cfg.Keys.Action(msg, keymap.Detail)
+	switch action {
+	case "back":
+		m.cancel()
+		return m.parentModel, nil
+	case "next_tab":
+		m.tab = (m.tab + 1) % detailTab(len(detailTabNames))
+	case "prev_tab":
+		m.tab = (m.tab + detailTab(len(detailTabNames)) - 1) % detailTab(len(detailTabNames))
+	case "refresh":
+		return m, tea.Batch(m.fetchDetails(), m.fetchResources())
+	}
+
+	if m.tab == resourcesTab && action == "edit" {
+		return m.editResources()
+	}
+	if m.tab != networksTab || m.details == nil {
+		return m, nil
+	}
+
+	switch action {
+	case "down":
+		if m.cursor < len(m.details.Networks)-1 {
+			m.cursor++
+		}
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "connect":
+		m.err = nil
+		return m, m.fetchAvailableNetworks()
+	case "disconnect":
+		if len(m.details.Networks) > 0 {
+			m.err = nil
+			m.mode = detailDisconnectMode
+		}
+	}
+	return m, nil
+}
+
+func (m ContainerDetailModel) updateConnect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.aliasInput.Blur()
+		m.mode = detailBrowseMode
+		return m, nil
+	case "prev":
+		if m.pickCursor > 0 {
+			m.pickCursor--
+		}
+		return m, nil
+	case "next":
+		if m.pickCursor < len(m.networks)-1 {
+			m.pickCursor++
+		}
+		return m, nil
+	case "submit":
+		var aliases []string
+		for _, a := range strings.Split(m.aliasInput.Value(), ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				aliases = append(aliases, a)
+			}
+		}
+		n := m.networks[m.pickCursor]
+		m.aliasInput.Blur()
+		m.mode = detailBrowseMode
+		m.status = "Connecting to " + n.Name + "..."
+		return m, m.connect(n, aliases)
+	}
+
+	var cmd tea.Cmd
+	m.aliasInput, cmd = m.aliasInput.Update(msg)
+	return m, cmd
+}
+
+// editResources opens the resources form filled with the current limits
+func (m ContainerDetailModel) editResources() (tea.Model, tea.Cmd) {
+	if m.resources == nil {
+		return m, nil
+	}
+	if err := m.caps.Supports(docker.FeatureResourceLimits); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.dockerClient.ReadOnly() {
+		m.err = docker.ErrReadOnly
+		return m, nil
+	}
+
+	r := m.resources
+	values := []string{
+		formatCount(r.CPUShares),
+		strconv.FormatFloat(r.CPUs, 'f', -1, 64),
+		formatLimit(r.Memory),
+		formatLimit(r.MemorySwap),
+		formatCount(r.PidsLimit),
+		r.Restart,
+	}
+	if r.CPUs == 0 {
+		values[1] = ""
+	}
+	for i, v := range values {
+		m.resourceForm.SetValue(i, v)
+	}
+	m.err = nil
+	m.status = ""
+	m.mode = detailResourcesMode
+	return m, m.resourceForm.Focus(0)
+}
+
+// formResources reads the resources form. Parse errors are returned as
+// *docker.InvalidOptionError like the ones from validation.
+func (m ContainerDetailModel) formResources() (docker.Resources, error) {
+	r := docker.Resources{CPUPeriod: m.resources.CPUPeriod}
+	invalid := func(option string, err error) error {
+		return &docker.InvalidOptionError{Option: option, Err: err}
+	}
+	count := func(option, value string) (int64, error) {
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, invalid(option, fmt.Errorf("%q is not a whole number", value))
+		}
+		return n, nil
+	}
+	size := func(option, value string) (int64, error) {
+		switch value {
+		case "":
+			return 0, nil
+		case "-1", "unlimited":
+			if option == "swap" {
+				return -1, nil
+			}
+		}
+		n, err := units.RAMInBytes(value)
+		if err != nil {
+			return 0, invalid(option, err)
+		}
+		return n, nil
+	}
+
+	var err error
+	f := m.resourceForm
+	if r.CPUShares, err = count("cpu shares", f.Value(0)); err != nil {
+		return r, err
+	}
+	if v := f.Value(1); v != "" {
+		if r.CPUs, err = strconv.ParseFloat(v, 64); err != nil {
+			return r, invalid("cpus", fmt.Errorf("%q is not a number", v))
+		}
+	}
+	if r.Memory, err = size("memory", f.Value(2)); err != nil {
+		return r, err
+	}
+	if r.MemorySwap, err = size("swap", f.Value(3)); err != nil {
+		return r, err
+	}
+	if r.PidsLimit, err = count("pids", f.Value(4)); err != nil {
+		return r, err
+	}
+	r.Restart = f.Value(5)
+	if r.Restart == "" {
+		r.Restart = "no"
+	}
+	return r, r.ValidateUpdate(*m.resources)
+}
+
+func (m ContainerDetailModel) updateResourceForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.err = nil
+		m.resourceForm.Blur()
+		m.mode = detailBrowseMode
+		return m, nil
+	case "submit":
+		r, err := m.formResources()
+		if err != nil {
+			// The field at fault gets the focus
+			m.err = err
+			var invalid *docker.InvalidOptionError
+			if errors.As(err, &invalid) {
+				for i, f := range resourceFields {
+					if f.option == invalid.Option {
+						return m, m.resourceForm.Focus(i)
+					}
+				}
+			}
+			return m, nil
+		}
+		m.err = nil
+		m.resourceForm.Blur()
+		m.mode = detailBrowseMode
+		m.status = "Updating limits..."
+		return m, m.updateResources(r)
+	}
+
+	var cmd tea.Cmd
+	m.resourceForm, cmd = m.resourceForm.Update(msg)
+	return m, cmd
+}
+
+func (m ContainerDetailModel) updateDisconnect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = detailBrowseMode
+	if m.cfg.Keys.Matches(msg, keymap.Confirm, "yes") {
+		n := m.details.Networks[m.cursor]
+		m.status = "Disconnecting from " + n.Name + "..."
+		return m, m.disconnect(n)
+	}
+	return m, nil
+}
+
+func (m ContainerDetailModel) overviewView() string {
+	d := m.details
+	rows := []string{
+		labelStyle.Render("ID:      ") + valueStyle.Render(shortID(d.ID)),
+		labelStyle.Render("Image:   ") + valueStyle.Render(d.Image),
+		labelStyle.Render("State:   ") + valueStyle.Render(d.State),
+		labelStyle.Render("Created: ") + valueStyle.Render(d.Created),
+		labelStyle.Render("Command: ") + valueStyle.Render(orDash(d.Command)),
+		"",
+		labelStyle.Render("Ports"),
+	}
+	if len(d.Ports) == 0 {
+		rows = append(rows, noSelectionStyle.Render("  none published"))
+	}
+	for _, p := range d.Ports {
+		rows = append(rows, "  "+p)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m ContainerDetailModel) networksView() string {
+	var rows []string
+	if len(m.details.Networks) == 0 {
+		rows = append(rows, noSelectionStyle.Render("Not attached to any network"))
+	}
+	for i, n := range m.details.Networks {
+		prefix := "  "
+		name := n.Name
+		if i == m.cursor {
+			prefix = cursorStyle.Render("> ")
+			name = cursorStyle.Render(name)
+		}
+		rows = append(rows, fmt.Sprintf("%s%-24s %s", prefix, name, valueStyle.Render(orDash(n.IPAddress))))
+		if len(n.Aliases) > 0 {
+			rows = append(rows, "    aliases: "+strings.Join(n.Aliases, ", "))
+		}
+	}
+
+	switch m.mode {
+	case detailConnectMode:
+		rows = append(rows, "", titleStyle.Render("Connect to network"))
+		for i, n := range m.networks {
+			prefix := "  "
+			if i == m.pickCursor {
+				prefix = cursorStyle.Render("> ")
+			}
+			rows = append(rows, fmt.Sprintf("%s%s (%s)", prefix, n.Name, n.Driver))
+		}
+		rows = append(rows, "", m.aliasInput.View())
+	case detailDisconnectMode:
+		rows = append(rows, "", titleStyle.Render(fmt.Sprintf("Disconnect from %s? (y/n)", m.details.Networks[m.cursor].Name)))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (m ContainerDetailModel) resourcesView() string {
+	if m.mode == detailResourcesMode {
+		return m.resourceForm.View()
+	}
+	if m.resources == nil {
+		return noSelectionStyle.Render("Loading...")
+	}
+
+	r := m.resources
+	shares := "1024 (default)"
+	if r.CPUShares > 0 {
+		shares = strconv.FormatInt(r.CPUShares, 10)
+	}
+	cpus := "no limit"
+	switch {
+	case r.CPUs > 0 && r.CPUPeriod > 0:
+		cpus = fmt.Sprintf("%g (quota %dµs per %dµs)", r.CPUs, int64(r.CPUs*float64(r.CPUPeriod)), r.CPUPeriod)
+	case r.CPUs > 0:
+		cpus = strconv.FormatFloat(r.CPUs, 'f', -1, 64)
+	}
+	swap := cmp.Or(formatLimit(r.MemorySwap), "no limit")
+	switch {
+	case r.MemorySwap < 0:
+		swap = "unlimited"
+	case r.MemorySwap == 0 && r.Memory > 0:
+		swap = "twice the memory"
+	}
+	rows := []string{
+		labelStyle.Render("CPU shares:    ") + valueStyle.Render(shares),
+		labelStyle.Render("CPUs:          ") + valueStyle.Render(cpus),
+		labelStyle.Render("Memory:        ") + valueStyle.Render(cmp.Or(formatLimit(r.Memory), "no limit")),
+		labelStyle.Render("Memory + swap: ") + valueStyle.Render(swap),
+		labelStyle.Render("PIDs limit:    ") + valueStyle.Render(cmp.Or(formatCount(r.PidsLimit), "no limit")),
+		labelStyle.Render("Restart:       ") + valueStyle.Render(r.Restart),
+	}
+	if err := m.caps.Supports(docker.FeatureResourceLimits); err != nil {
+		rows = append(rows, "", noSelectionStyle.Render(err.Error()))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// formatLimit renders a byte limit as the resources form takes it, empty
+// for none
+func formatLimit(bytes int64) string {
+	switch {
+	case bytes < 0:
+		return "-1"
+	case bytes == 0:
+		return ""
+	}
+	return docker.FormatMemory(bytes)
+}
+
+// formatCount renders a count, empty for zero
+func formatCount(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// View implements tea.Model
+func (m ContainerDetailModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Detail, keymap.Form, keymap.Confirm, keymap.Global)
+	}
+
+	modalWidth := m.width * 85 / 100
+	modalHeight := m.height * 80 / 100
+
+	tabs := make([]string, len(detailTabNames))
+	for i, name := range detailTabNames {
+		if detailTab(i) == m.tab {
+			tabs[i] = activeTabStyle.Render(name)
+		} else {
+			tabs[i] = inactiveTabStyle.Render(name)
+		}
+	}
+
+	var body, help string
+	switch {
+	case m.details == nil:
+		body = noSelectionStyle.Render("Loading...")
+	case m.tab == networksTab:
+		body = m.networksView()
+	case m.tab == resourcesTab:
+		body = m.resourcesView()
+	default:
+		body = m.overviewView()
+	}
+
+	keys := m.cfg.Keys
+	switch {
+	case m.mode == detailConnectMode:
+		help = keys.Short(keymap.Form, "prev", "next", "submit", "cancel")
+	case m.mode == detailDisconnectMode:
+		help = keys.Short(keymap.Confirm, "yes", "no")
+	case m.mode == detailResourcesMode:
+		help = keys.Short(keymap.Form, "next", "prev", "submit", "cancel")
+	case m.tab == resourcesTab && m.caps.Supports(docker.FeatureResourceLimits) == nil:
+		help = keys.Short(keymap.Detail, "next_tab", "edit", "refresh", "back") + " • " + keys.Short(keymap.Global, "help")
+	case m.tab == networksTab:
+		help = keys.Short(keymap.Detail, "next_tab", "connect", "disconnect", "refresh", "back") + " • " + keys.Short(keymap.Global, "help")
+	default:
+		help = keys.Short(keymap.Detail, "next_tab", "refresh", "back") + " • " + keys.Short(keymap.Global, "help")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		browserTitleStyle.Render(m.containerName),
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		"",
+		body,
+		"",
+		status,
+		browserHelpStyle.Render(help),
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Width(modalWidth).
+		Height(modalHeight).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Border).
+		Padding(1, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
@@ -1,45 +1,76 @@
 package views
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/compose"
+	"github.com/shubhamku044/containix/internal/config"
 	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
 )
 
+// bannerMinHeight is the smallest pane that shows the ASCII art title
+const bannerMinHeight = 30
+
 type ContainerListModel struct {
 	list         list.Model
-	dockerClient *docker.Client
+	dockerClient docker.Runtime
+	ctx          context.Context
 	err          error
-	logs         string
-	showingLogs  bool
 	width        int
 	height       int
-	logsViewport viewport.Model
 	asciiTitle   string
+	containers   []docker.Container
+	collapsed    map[string]bool
+	hostFilter   string // only this fleet host is listed when set
+	cfg          config.Config
+	pending      *pendingAction
+	activity     Activity
+	logsID       string // the container whose logs were shown last
+	logsProject  string // or the project
+}
+
+// pendingAction is an action waiting for the user to confirm it
+type pendingAction struct {
+	prompt string
+	cmd    tea.Cmd
 }
 
 type ContainerItem struct {
-	id     string
-	title  string
-	status string
+	id      string
+	title   string
+	name    string
+	status  string
+	project string
 }
 
 func (i ContainerItem) Title() string       { return i.title }
 func (i ContainerItem) Description() string { return i.status }
-func (i ContainerItem) FilterValue() string { return i.title }
+func (i ContainerItem) FilterValue() string { return i.title + " " + i.project }
 
 type ContainersFetchedMsg struct {
-	Items []list.Item
+	Containers []docker.Container
 }
 
 type ErrMsg struct {
 	Err error
 }
 
+// failed reports an error to the screen. Work that was cancelled because
+// the user left its screen or quit reports nothing.
+func failed(err error) tea.Msg {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return ErrMsg{Err: err}
+}
+
 type LogsMsg struct {
 	Logs string
 }
@@ -51,18 +82,10 @@ type SelectedContainerMsg struct {
 	Status string
 }
 
-func NewContainerListModel() (ContainerListModel, error) {
-	cli, err := docker.NewClient()
-	if err != nil {
-		return ContainerListModel{}, err
-	}
-
+func NewContainerListModel(ctx context.Context, cli docker.Runtime, cfg config.Config) ContainerListModel {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	l.Title = "Containers"
 	l.Styles.Title = lipgloss.NewStyle().MarginLeft(2)
 
-	vp := viewport.New(0, 0)
-
 	asciiTitle := `
  ██████╗ ██████╗ ███╗   ██╗████████╗ █████╗ ██╗███╗   ██╗██╗██╗  ██╗
 ██╔════╝██╔═══██╗████╗  ██║╚══██╔══╝██╔══██╗██║████╗  ██║██║╚██╗██╔╝
@@ -72,12 +95,160 @@ func NewContainerListModel() (ContainerListModel, error) {
  ╚═════╝ ╚═════╝ ╚═╝  ╚═══╝   ╚═╝   ╚═╝  ╚═╝╚═╝╚═╝  ╚═══╝╚═╝╚═╝  ╚═╝
 `
 
-	return ContainerListModel{
+	m := ContainerListModel{
 		list:         l,
 		dockerClient: cli,
-		logsViewport: vp,
+		ctx:          ctx,
 		asciiTitle:   asciiTitle,
-	}, nil
+		collapsed:    map[string]bool{},
+		activity:     NewActivity(),
+	}
+	m.updateTitle()
+	m.SetConfig(cfg)
+	return m
+}
+
+// updateTitle names the list after the host filter and read-only mode
+func (m *ContainerListModel) updateTitle() {
+	m.list.Title = "Containers"
+	if m.hostFilter != "" {
+		m.list.Title += " on " + m.hostFilter
+	}
+	if m.dockerClient.ReadOnly() {
+		m.list.Title += " (read-only)"
+	}
+}
+
+// hosts returns the hosts the list can be filtered by, none outside a fleet
+func (m ContainerListModel) hosts() []string {
+	if fleet, ok := m.dockerClient.(*docker.Fleet); ok {
+		return fleet.Hosts()
+	}
+	return nil
+}
+
+// nextHost moves the host filter to the next host, and back to all hosts
+// after the last one
+func (m *ContainerListModel) nextHost() tea.Cmd {
+	hosts := m.hosts()
+	if len(hosts) == 0 {
+		return nil
+	}
+	next := hosts[0]
+	for i, h := range hosts {
+		if h == m.hostFilter {
+			next = ""
+			if i+1 < len(hosts) {
+				next = hosts[i+1]
+			}
+		}
+	}
+	m.hostFilter = next
+	m.updateTitle()
+	m.list.ResetSelected()
+	return m.list.SetItems(m.items())
+}
+
+// items groups the containers that pass the host filter
+func (m ContainerListModel) items() []list.Item {
+	containers := m.containers
+	if m.hostFilter != "" {
+		containers = nil
+		for _, c := range m.containers {
+			if c.Host == m.hostFilter {
+				containers = append(containers, c)
+			}
+		}
+	}
+	return groupContainers(containers, m.collapsed)
+}
+
+// SetConfig applies a new configuration, e.g. after the file was reloaded
+func (m *ContainerListModel) SetConfig(cfg config.Config) {
+	m.cfg = cfg
+	m.resizeList()
+}
+
+// containerListStyle frames the title, list and help line
+var containerListStyle = lipgloss.NewStyle().Padding(1, 2)
+
+// title is the ASCII art banner when it fits, a single line otherwise
+func (m ContainerListModel) title() string {
+	width := m.width - containerListStyle.GetHorizontalFrameSize()
+	banner := strings.Trim(m.asciiTitle, "\n")
+	if width >= lipgloss.Width(banner) && m.height >= bannerMinHeight {
+		return lipgloss.NewStyle().
+			Foreground(palette.Accent).
+			Italic(true).
+			Render(lipgloss.PlaceHorizontal(width, lipgloss.Center, banner)) + "\n"
+	}
+	return lipgloss.NewStyle().
+		Foreground(palette.Accent).
+		Bold(true).
+		Render("CONTAINIX")
+}
+
+// help is the key hint line, the prompt of an action awaiting confirmation
+// or the progress of a slow one
+func (m ContainerListModel) help() string {
+	actions := []string{"stop", "start", "restart", "logs", "inspect", "refresh"}
+	if len(m.hosts()) > 0 {
+		actions = append(actions, "hosts")
+	}
+	help := m.cfg.Keys.Short(keymap.Containers, actions...) +
+		" • " + m.cfg.Keys.Short(keymap.Global, "help")
+	if m.pending != nil {
+		help = m.pending.prompt + " " + m.cfg.Keys.Short(keymap.Confirm, "yes") + " • any other key: cancel"
+	} else if m.activity.Visible() {
+		return lipgloss.NewStyle().
+			Width(max(m.width-containerListStyle.GetHorizontalFrameSize(), 0)).
+			Render(m.activity.View())
+	}
+	return lipgloss.NewStyle().
+		Foreground(palette.Subtle).
+		Width(max(m.width-containerListStyle.GetHorizontalFrameSize(), 0)).
+		Render(help)
+}
+
+// resizeList gives the list the room left between the title and the help
+func (m *ContainerListModel) resizeList() {
+	width := max(m.width-containerListStyle.GetHorizontalFrameSize(), 0)
+	height := m.height - containerListStyle.GetVerticalFrameSize() -
+		lipgloss.Height(m.title()) - lipgloss.Height(m.help()) - 2
+	m.list.SetSize(width, max(height, 0))
+}
+
+// Filtering reports whether the user is typing a filter, in which case keys
+// must not trigger actions
+func (m ContainerListModel) Filtering() bool {
+	return m.list.FilterState() == list.Filtering
+}
+
+// Refresh reloads the container list
+func (m *ContainerListModel) Refresh() tea.Cmd {
+	return m.fetchContainers()
+}
+
+// Resync reloads the container list and the logs shown last, e.g. after
+// the daemon came back
+func (m *ContainerListModel) Resync() tea.Cmd {
+	cmds := []tea.Cmd{m.fetchContainers()}
+	switch {
+	case m.logsProject != "":
+		cmds = append(cmds, m.fetchProjectLogs(m.logsProject))
+	case m.logsID != "":
+		cmds = append(cmds, m.fetchLogs(m.logsID))
+	}
+	return tea.Batch(cmds...)
+}
+
+// confirm runs cmd straight away, or asks first when required is set
+func (m *ContainerListModel) confirm(required bool, prompt string, cmd tea.Cmd) tea.Cmd {
+	if !required {
+		return cmd
+	}
+	m.pending = &pendingAction{prompt: prompt, cmd: cmd}
+	return nil
 }
 
 func (m ContainerListModel) Init() tea.Cmd {
@@ -89,28 +260,19 @@ func (m ContainerListModel) Init() tea.Cmd {
 
 func (m *ContainerListModel) fetchContainers() tea.Cmd {
 	return func() tea.Msg {
-		containers, err := m.dockerClient.ListContainers()
+		containers, err := m.dockerClient.ListContainers(m.ctx)
 		if err != nil {
-			return ErrMsg{Err: err}
-		}
-
-		items := make([]list.Item, len(containers))
-		for i, c := range containers {
-			items[i] = ContainerItem{
-				id:     c.ID,
-				title:  c.Name,
-				status: c.Status,
-			}
+			return failed(err)
 		}
-		return ContainersFetchedMsg{Items: items}
+		return ContainersFetchedMsg{Containers: containers}
 	}
 }
 
 func (m *ContainerListModel) stopContainer(containerID string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.dockerClient.StopContainer(containerID)
+		err := m.dockerClient.StopContainer(m.ctx, containerID)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return failed(err)
 		}
 		return nil
 	}
@@ -118,9 +280,9 @@ func (m *ContainerListModel) stopContainer(containerID string) tea.Cmd {
 
 func (m *ContainerListModel) startContainer(containerID string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.dockerClient.StartContainer(containerID)
+		err := m.dockerClient.StartContainer(m.ctx, containerID)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return failed(err)
 		}
 		return nil
 	}
@@ -128,19 +290,44 @@ func (m *ContainerListModel) startContainer(containerID string) tea.Cmd {
 
 func (m *ContainerListModel) restartContainer(containerID string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.dockerClient.RestartContainer(containerID)
+		err := m.dockerClient.RestartContainer(m.ctx, containerID)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return failed(err)
 		}
 		return nil
 	}
 }
 
+// projectConfigFiles returns the compose files recorded on a project's containers
+func (m ContainerListModel) projectConfigFiles(project string) []string {
+	for _, c := range m.containers {
+		if c.Project() == project {
+			if files := compose.ConfigFilesFromLabel(c.Labels[docker.ComposeConfigFilesLabel]); len(files) > 0 {
+				return files
+			}
+		}
+	}
+	return nil
+}
+
+// toggleProject collapses or expands a compose project section
+func (m *ContainerListModel) toggleProject(project string) tea.Cmd {
+	m.collapsed[project] = !m.collapsed[project]
+	cmd := m.list.SetItems(m.items())
+	for i, item := range m.list.Items() {
+		if p, ok := item.(ProjectItem); ok && p.name == project {
+			m.list.Select(i)
+			break
+		}
+	}
+	return cmd
+}
+
 func (m *ContainerListModel) fetchLogs(containerID string) tea.Cmd {
 	return func() tea.Msg {
-		logs, err := m.dockerClient.GetContainerLogs(containerID)
+		logs, err := m.dockerClient.GetContainerLogs(m.ctx, containerID, m.cfg.Logs.Tail)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return failed(err)
 		}
 		return LogsMsg{Logs: logs}
 	}
@@ -152,73 +339,221 @@ func (m ContainerListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width-4, msg.Height-6)
+		m.resizeList()
 
 	case ContainersFetchedMsg:
-		m.list.SetItems(msg.Items)
-		return m, nil
+		m.containers = msg.Containers
+		m.err = nil
+		return m, m.list.SetItems(m.items())
 
 	case ErrMsg:
 		m.err = msg.Err
 		return m, nil
 
+	case ActivityMsg, spinner.TickMsg:
+		var cmd tea.Cmd
+		m.activity, cmd = m.activity.Update(msg)
+		m.resizeList()
+		return m, cmd
+
+	case ContainerCreatedMsg:
+		// The container is listed once the list is fetched again
+		id := msg.ID
+		return m, tea.Sequence(m.fetchContainers(), func() tea.Msg {
+			return FocusContainerMsg{ID: id}
+		})
+
+	case FocusContainerMsg:
+		// Expand the container's project and show its host so it can be
+		// selected
+		for _, c := range m.containers {
+			if c.ID != msg.ID {
+				continue
+			}
+			if m.hostFilter != "" && c.Host != m.hostFilter {
+				m.hostFilter = ""
+				m.updateTitle()
+			}
+			m.collapsed[groupOf(c)] = false
+			m.list.SetItems(m.items())
+		}
+		for i, item := range m.list.Items() {
+			if c, ok := item.(ContainerItem); ok && c.id == msg.ID {
+				m.list.ResetFilter()
+				m.list.Select(i)
+				break
+			}
+		}
+		return m, nil
+
 	case tea.KeyMsg:
-		// When user presses enter/space, emit a SelectedContainerMsg
-		switch msg.String() {
-		case "enter", " ":
+		if m.pending != nil {
+			cmd := m.pending.cmd
+			m.pending = nil
+			if m.cfg.Keys.Matches(msg, keymap.Confirm, "yes") {
+				return m, cmd
+			}
+			return m, nil
+		}
+		if m.Filtering() {
+			break
+		}
+
+		switch m.cfg.Keys.Action(msg, keymap.Containers) {
+		case "select":
+			// Emit a SelectedContainerMsg, or expand a project header
+			if project, ok := m.list.SelectedItem().(ProjectItem); ok {
+				return m, m.toggleProject(project.name)
+			}
 			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
 				return m, func() tea.Msg {
 					return SelectedContainerMsg{
 						ID:     selectedItem.id,
-						Name:   selectedItem.title,
+						Name:   selectedItem.name,
 						Status: selectedItem.status,
 					}
 				}
 			}
-		// ...existing code for other key handlers...
-		case "r":
+		case "collapse":
+			switch item := m.list.SelectedItem().(type) {
+			case ProjectItem:
+				return m, m.toggleProject(item.name)
+			case ContainerItem:
+				if item.project != "" {
+					return m, m.toggleProject(item.project)
+				}
+			}
+		case "collapse_all":
+			// Collapse every project, or expand them all if already collapsed
+			collapse := false
+			for _, c := range m.containers {
+				if p := groupOf(c); p != "" && !m.collapsed[p] {
+					collapse = true
+					break
+				}
+			}
+			for _, c := range m.containers {
+				if p := groupOf(c); p != "" {
+					m.collapsed[p] = collapse
+				}
+			}
+			return m, m.list.SetItems(m.items())
+		case "refresh":
 			return m, m.fetchContainers()
-		case "s":
+		case "hosts":
+			return m, m.nextHost()
+		case "stop":
+			if project, ok := m.list.SelectedItem().(ProjectItem); ok {
+				return m, m.confirm(m.cfg.Confirm.Stop, "Stop every container of "+project.label()+"?", track(
+					"Stopping "+project.label(),
+					m.groupAction(project, m.dockerClient.StopProject, m.dockerClient.StopContainer),
+					m.fetchContainers(),
+				))
+			}
 			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
-				return m, tea.Sequence(
+				return m, m.confirm(m.cfg.Confirm.Stop, "Stop "+selectedItem.name+"?", track(
+					"Stopping "+selectedItem.name,
 					m.stopContainer(selectedItem.id),
 					m.fetchContainers(),
+				))
+			}
+		case "start":
+			if project, ok := m.list.SelectedItem().(ProjectItem); ok {
+				return m, track(
+					"Starting "+project.label(),
+					m.groupAction(project, m.dockerClient.StartProject, m.dockerClient.StartContainer),
+					m.fetchContainers(),
 				)
 			}
-		case "t":
 			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
-				return m, tea.Sequence(
+				return m, track(
+					"Starting "+selectedItem.name,
 					m.startContainer(selectedItem.id),
 					m.fetchContainers(),
 				)
 			}
-		case "x":
+		case "restart":
+			if project, ok := m.list.SelectedItem().(ProjectItem); ok {
+				return m, m.confirm(m.cfg.Confirm.Restart, "Restart every container of "+project.label()+"?", track(
+					"Restarting "+project.label(),
+					m.groupAction(project, m.dockerClient.RestartProject, m.dockerClient.RestartContainer),
+					m.fetchContainers(),
+				))
+			}
 			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
-				return m, tea.Sequence(
+				return m, m.confirm(m.cfg.Confirm.Restart, "Restart "+selectedItem.name+"?", track(
+					"Restarting "+selectedItem.name,
 					m.restartContainer(selectedItem.id),
 					m.fetchContainers(),
-				)
+				))
+			}
+		case "logs":
+			if project, ok := m.list.SelectedItem().(ProjectItem); ok {
+				if project.pod {
+					// Pods have no combined log
+					return m, nil
+				}
+				m.logsID, m.logsProject = "", project.name
+				return m, m.fetchProjectLogs(project.name)
 			}
-		case "l":
 			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
-				m.showingLogs = true
-				fmt.Println("Fetching logs for container:", selectedItem.id)
+				m.logsID, m.logsProject = selectedItem.id, ""
 				return m, m.fetchLogs(selectedItem.id)
 			}
-		case "q":
-			if m.showingLogs {
-				m.showingLogs = false
-				m.logs = ""
-			} else {
-				return m, tea.Quit
+		case "files":
+			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
+				return m, func() tea.Msg {
+					return OpenFileBrowserMsg{ID: selectedItem.id, Name: selectedItem.name}
+				}
+			}
+		case "upload":
+			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
+				return m, func() tea.Msg {
+					return OpenUploadMsg{ID: selectedItem.id, Name: selectedItem.name}
+				}
+			}
+		case "export":
+			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
+				return m, func() tea.Msg {
+					return OpenExportMsg{ID: selectedItem.id, Name: selectedItem.name}
+				}
+			}
+		case "inspect":
+			if selectedItem, ok := m.list.SelectedItem().(ContainerItem); ok {
+				return m, func() tea.Msg {
+					return OpenContainerDetailMsg{ID: selectedItem.id, Name: selectedItem.name}
+				}
+			}
+		case "networks":
+			return m, func() tea.Msg {
+				return OpenNetworksMsg{}
+			}
+		case "compose":
+			project := ""
+			switch item := m.list.SelectedItem().(type) {
+			case ProjectItem:
+				project = item.name
+			case ContainerItem:
+				project = item.project
+			}
+			if project != "" && !strings.HasPrefix(project, podGroup) {
+				files := m.projectConfigFiles(project)
+				return m, func() tea.Msg {
+					return OpenComposeMsg{Project: project, Files: files}
+				}
+			}
+		case "open_compose":
+			return m, func() tea.Msg {
+				return OpenComposeMsg{}
+			}
+		case "topology":
+			return m, func() tea.Msg {
+				return OpenTopologyMsg{}
+			}
+		case "run":
+			return m, func() tea.Msg {
+				return OpenRunMsg{}
 			}
-		}
-
-	case LogsMsg:
-		m.logs = msg.Logs
-		m.logsViewport.SetContent(msg.Logs)
-		return m, func() tea.Msg {
-			return msg
 		}
 	}
 
@@ -236,31 +571,9 @@ func (m ContainerListModel) View() string {
 			Render("Error: " + m.err.Error() + "\nPress R to retry")
 	}
 
-	if m.showingLogs {
-		return lipgloss.NewStyle().
-			Width(m.width).
-			Height(m.height).
-			Padding(1, 2).
-			Render(m.logsViewport.View() + "\n\nPress 'q' to return")
-	}
-
-	asciiTitle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("226")).
-		Italic(true).
-		Render(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.asciiTitle))
-
-	listView := lipgloss.NewStyle().
-		Width(m.width - 4).
-		Height(m.height - 6).
-		Render(m.list.View())
-
-	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("\n  s: stop • t: start • x: restart • l: logs • r: refresh • q: quit")
-
-	return lipgloss.NewStyle().
+	return containerListStyle.
 		Width(m.width).
 		Height(m.height).
-		Padding(1, 2).
-		Render(asciiTitle + "\n\n" + listView + helpText)
+		MaxHeight(m.height).
+		Render(m.title() + "\n" + m.list.View() + "\n\n" + m.help())
 }
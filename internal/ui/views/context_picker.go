@@ -0,0 +1,241 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/ui/components"
+)
+
+// SwitchContextMsg asks the main model to connect to another daemon, either
+// through a docker CLI context or directly to a host address
+type SwitchContextMsg struct {
+	Context string
+	Host    string
+}
+
+// Target names the daemon the message selects, as shown in the status bar
+func (m SwitchContextMsg) Target() string {
+	if m.Host != "" {
+		return m.Host
+	}
+	return m.Context
+}
+
+type contextsFetchedMsg struct {
+	contexts []docker.Context
+}
+
+type contextItem struct {
+	context docker.Context
+	active  bool
+}
+
+func (i contextItem) Title() string {
+	if i.active {
+		return i.context.Name + " (active)"
+	}
+	return i.context.Name
+}
+
+func (i contextItem) Description() string {
+	if i.context.Description == "" {
+		return orDash(i.context.Host)
+	}
+	return orDash(i.context.Host) + " • " + i.context.Description
+}
+
+func (i contextItem) FilterValue() string { return i.context.Name }
+
+// ContextPickerModel lists the docker CLI contexts and switches between them
+type ContextPickerModel struct {
+	list        list.Model
+	form        components.FormModel
+	cfg         config.Config
+	help        helpToggle
+	active      string
+	entering    bool
+	err         error
+	width       int
+	height      int
+	parentModel tea.Model
+}
+
+// NewContextPicker creates the context picker. active is the target the
+// main screen is connected to.
+func NewContextPicker(active string, cfg config.Config, width, height int, parentModel tea.Model) ContextPickerModel {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Docker contexts"
+	l.Styles.Title = lipgloss.NewStyle().MarginLeft(2)
+	l.SetShowHelp(false)
+
+	form := components.NewForm("Host")
+	form.SetPlaceholder(0, "unix:///var/run/docker.sock, tcp://host:2376 or ssh://user@host")
+	form.SetKeys(cfg.Keys)
+
+	m := ContextPickerModel{
+		list:        l,
+		form:        form,
+		cfg:         cfg,
+		active:      active,
+		parentModel: parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m ContextPickerModel) Init() tea.Cmd {
+	return fetchContexts
+}
+
+func fetchContexts() tea.Msg {
+	contexts, err := docker.ListContexts()
+	if err != nil {
+		return failed(err)
+	}
+	return contextsFetchedMsg{contexts: contexts}
+}
+
+func (m *ContextPickerModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.list.SetSize(width-4, height-7)
+}
+
+// Update implements tea.Model
+func (m ContextPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case contextsFetchedMsg:
+		items := make([]list.Item, len(msg.contexts))
+		for i, c := range msg.contexts {
+			active := c.Name == m.active
+			items[i] = contextItem{context: c, active: active}
+			if active {
+				m.list.Select(i)
+			}
+		}
+		return m, m.list.SetItems(items)
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.entering {
+			return m.updateHost(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+
+	var cmd tea.Cmd
+	if m.entering {
+		m.form, cmd = m.form.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m ContextPickerModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Contexts) {
+	case "back":
+		return m.parentModel, nil
+	case "refresh":
+		m.err = nil
+		return m, fetchContexts
+	case "host":
+		m.err = nil
+		m.entering = true
+		m.form.SetValue(0, "")
+		return m, m.form.Focus(0)
+	case "switch":
+		item, ok := m.list.SelectedItem().(contextItem)
+		if !ok {
+			return m, nil
+		}
+		if item.active {
+			return m.parentModel, nil
+		}
+		return m.parentModel, func() tea.Msg {
+			return SwitchContextMsg{Context: item.context.Name}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m ContextPickerModel) updateHost(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.form.Blur()
+		m.entering = false
+		return m, nil
+	case "submit":
+		host := m.form.Value(0)
+		if host == "" {
+			m.err = fmt.Errorf("host address is required")
+			return m, nil
+		}
+		m.form.Blur()
+		return m.parentModel, func() tea.Msg {
+			return SwitchContextMsg{Host: host}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m ContextPickerModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Contexts, keymap.Form, keymap.Global)
+	}
+
+	keys := m.cfg.Keys
+	body := m.list.View()
+	help := keys.Short(keymap.Contexts, "switch", "host", "refresh") + " • /: filter • " +
+		keys.Short(keymap.Contexts, "back") + " • " + keys.Short(keymap.Global, "help")
+	if m.entering {
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render("Connect to a daemon address"),
+			"",
+			m.form.View())
+		help = keys.Short(keymap.Form, "submit", "cancel")
+	}
+
+	status := browserStatusStyle.Render("Connected to " + m.active)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		body,
+		"",
+		status,
+		browserHelpStyle.Width(m.width-4).Render(help),
+	))
+}
@@ -0,0 +1,255 @@
+package views
+
+import (
+	"context"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+type exportTab int
+
+const (
+	runTab exportTab = iota
+	composeTab
+)
+
+var exportTabNames = []string{"docker run", "Compose"}
+
+// OpenExportMsg asks the main model to show a container as a `docker run`
+// command or a compose service
+type OpenExportMsg struct {
+	ID   string
+	Name string
+}
+
+type runOptionsMsg struct {
+	opts docker.RunOptions
+}
+
+type copiedMsg struct {
+	status string
+}
+
+// ExportModel shows the `docker run` command and the compose service that
+// would recreate a container, ready to copy
+type ExportModel struct {
+	dockerClient  docker.Runtime
+	ctx           context.Context
+	cancel        context.CancelFunc
+	containerID   string
+	containerName string
+	opts          *docker.RunOptions
+	compose       string
+	tab           exportTab
+	viewport      viewport.Model
+	cfg           config.Config
+	help          helpToggle
+	status        string
+	err           error
+	width         int
+	height        int
+	parentModel   tea.Model
+}
+
+// NewExport creates the export view for a container
+func NewExport(ctx context.Context, dockerClient docker.Runtime, containerID, containerName string, cfg config.Config, width, height int, parentModel tea.Model) ExportModel {
+	ctx, cancel := context.WithCancel(ctx)
+	m := ExportModel{
+		dockerClient:  dockerClient,
+		ctx:           ctx,
+		cancel:        cancel,
+		containerID:   containerID,
+		containerName: containerName,
+		viewport:      viewport.New(0, 0),
+		cfg:           cfg,
+		parentModel:   parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m ExportModel) Init() tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		opts, err := client.RunOptionsOf(ctx, id)
+		if err != nil {
+			return failed(err)
+		}
+		return runOptionsMsg{opts: opts}
+	}
+}
+
+func (m *ExportModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width*85/100 - 6
+	m.viewport.Height = height*80/100 - 9
+	m.render()
+}
+
+// text is what the current tab shows and copies
+func (m ExportModel) text() string {
+	if m.opts == nil {
+		return ""
+	}
+	if m.tab == composeTab {
+		return m.compose
+	}
+	lines := []string{docker.FormatCommand(m.opts.Args(), m.viewport.Width)}
+	for _, args := range m.opts.ConnectArgs() {
+		lines = append(lines, docker.FormatCommand(args, m.viewport.Width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render puts the current tab into the viewport
+func (m *ExportModel) render() {
+	m.viewport.SetContent(m.text())
+}
+
+// copyText copies to the system clipboard, or through the terminal with
+// OSC 52 where there is none, as over SSH
+func copyText(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			termenv.Copy(text)
+			return copiedMsg{status: "Sent to the terminal's clipboard"}
+		}
+		return copiedMsg{status: "Copied to clipboard"}
+	}
+}
+
+// Update implements tea.Model
+func (m ExportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case runOptionsMsg:
+		m.opts = &msg.opts
+		compose, err := msg.opts.ComposeService()
+		if err != nil {
+			m.err = err
+		}
+		m.compose = compose
+		m.render()
+		return m, nil
+
+	case copiedMsg:
+		m.err = nil
+		m.status = msg.status
+		return m, nil
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.help.handle(m.cfg.Keys, msg) {
+			return m, nil
+		}
+		switch m.cfg.Keys.Action(msg, keymap.Export) {
+		case "back":
+			m.cancel()
+			return m.parentModel, nil
+		case "next_tab":
+			m.tab = (m.tab + 1) % exportTab(len(exportTabNames))
+			m.status = ""
+			m.render()
+			m.viewport.GotoTop()
+		case "prev_tab":
+			m.tab = (m.tab + exportTab(len(exportTabNames)) - 1) % exportTab(len(exportTabNames))
+			m.status = ""
+			m.render()
+			m.viewport.GotoTop()
+		case "copy":
+			if m.opts != nil {
+				// The copy drops the line breaks added to fit the screen
+				text := m.compose
+				if m.tab == runTab {
+					text = m.unwrapped()
+				}
+				return m, copyText(text)
+			}
+		case "down":
+			m.viewport.LineDown(1)
+		case "up":
+			m.viewport.LineUp(1)
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// unwrapped is the docker run tab with one command per line
+func (m ExportModel) unwrapped() string {
+	lines := []string{docker.FormatCommand(m.opts.Args(), 0)}
+	for _, args := range m.opts.ConnectArgs() {
+		lines = append(lines, docker.FormatCommand(args, 0))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// View implements tea.Model
+func (m ExportModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Export, keymap.Global)
+	}
+
+	tabs := make([]string, len(exportTabNames))
+	for i, name := range exportTabNames {
+		if exportTab(i) == m.tab {
+			tabs[i] = activeTabStyle.Render(name)
+		} else {
+			tabs[i] = inactiveTabStyle.Render(name)
+		}
+	}
+
+	body := m.viewport.View()
+	if m.opts == nil && m.err == nil {
+		body = noSelectionStyle.Render("Inspecting...")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+	help := m.cfg.Keys.Short(keymap.Export, "next_tab", "copy", "back") + " • " + m.cfg.Keys.Short(keymap.Global, "help")
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		browserTitleStyle.Render("Export "+m.containerName),
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		"",
+		body,
+		"",
+		status,
+		browserHelpStyle.Render(help),
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Width(m.width*85/100).
+		Height(m.height*80/100).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Border).
+		Padding(1, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
@@ -0,0 +1,464 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+// fileReadLimit caps how much of a file is loaded into the viewer
+const fileReadLimit = 1 << 20
+
+type fileBrowserMode int
+
+const (
+	browseMode fileBrowserMode = iota
+	viewMode
+	saveMode
+)
+
+// OpenFileBrowserMsg asks the main model to open the file browser
+type OpenFileBrowserMsg struct {
+	ID   string
+	Name string
+}
+
+type dirListedMsg struct {
+	dir       string
+	entries   []docker.FileEntry
+	truncated bool
+}
+
+type fileReadMsg struct {
+	entry   docker.FileEntry
+	content string
+}
+
+type fileSavedMsg struct {
+	dst string
+}
+
+type fileItem struct {
+	entry docker.FileEntry
+}
+
+func (i fileItem) Title() string {
+	switch {
+	case i.entry.IsDir():
+		return i.entry.Name + "/"
+	case i.entry.LinkTarget != "":
+		return i.entry.Name + " → " + i.entry.LinkTarget
+	}
+	return i.entry.Name
+}
+
+func (i fileItem) Description() string {
+	return fmt.Sprintf("%s  %s  %s",
+		i.entry.Mode.String(),
+		formatBytes(i.entry.Size),
+		i.entry.ModTime.Format("2006-01-02 15:04"))
+}
+
+func (i fileItem) FilterValue() string { return i.entry.Name }
+
+// FileBrowserModel lets the user navigate and download a container's filesystem
+type FileBrowserModel struct {
+	dockerClient  docker.Runtime
+	ctx           context.Context
+	cancel        context.CancelFunc
+	containerID   string
+	containerName string
+	cwd           string
+	list          list.Model
+	viewport      viewport.Model
+	input         textinput.Model
+	mode          fileBrowserMode
+	viewing       docker.FileEntry
+	sections      []int
+	saveTarget    docker.FileEntry
+	extract       bool
+	cfg           config.Config
+	help          helpToggle
+	status        string
+	err           error
+	width         int
+	height        int
+	parentModel   tea.Model
+}
+
+// NewFileBrowser creates a file browser rooted at "/" of the given container
+func NewFileBrowser(ctx context.Context, dockerClient docker.Runtime, containerID, containerName string, cfg config.Config, width, height int, parentModel tea.Model) FileBrowserModel {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+
+	ti := textinput.New()
+	ti.Prompt = "Save to: "
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := FileBrowserModel{
+		dockerClient:  dockerClient,
+		ctx:           ctx,
+		cancel:        cancel,
+		containerID:   containerID,
+		containerName: containerName,
+		cwd:           "/",
+		list:          l,
+		viewport:      viewport.New(0, 0),
+		input:         ti,
+		extract:       true,
+		cfg:           cfg,
+		parentModel:   parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m FileBrowserModel) Init() tea.Cmd {
+	return m.listDir(m.cwd)
+}
+
+func (m FileBrowserModel) listDir(dir string) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		entries, truncated, err := client.ListDir(ctx, id, dir)
+		if err != nil {
+			return failed(err)
+		}
+		return dirListedMsg{dir: dir, entries: entries, truncated: truncated}
+	}
+}
+
+func (m FileBrowserModel) readFile(entry docker.FileEntry) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		content, err := client.ReadFile(ctx, id, entry.Path, fileReadLimit)
+		if err != nil {
+			return failed(err)
+		}
+		return fileReadMsg{entry: entry, content: content}
+	}
+}
+
+func (m FileBrowserModel) saveFile(entry docker.FileEntry, dst string, extract bool) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		if err := client.SaveToHost(ctx, id, entry.Path, dst, extract); err != nil {
+			return failed(err)
+		}
+		return fileSavedMsg{dst: dst}
+	}
+}
+
+func (m *FileBrowserModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.list.SetSize(width*85/100-6, height*80/100-8)
+	m.viewport.Width = width*85/100 - 6
+	m.viewport.Height = height*80/100 - 8
+}
+
+// startSave switches to the save prompt for the given entry
+func (m *FileBrowserModel) startSave(entry docker.FileEntry) tea.Cmd {
+	m.saveTarget = entry
+	m.extract = true
+	name := entry.Name
+	if name == "" || name == "/" {
+		name = m.containerName + "-root"
+	}
+	m.input.SetValue(filepath.Join(".", name))
+	m.input.CursorEnd()
+	m.mode = saveMode
+	return m.input.Focus()
+}
+
+// Update implements tea.Model
+func (m FileBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		// Keep the parent in sync so it renders correctly once we close
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case dirListedMsg:
+		m.cwd = msg.dir
+		m.err = nil
+		items := make([]list.Item, len(msg.entries))
+		for i, e := range msg.entries {
+			items[i] = fileItem{entry: e}
+		}
+		m.list.ResetFilter()
+		m.list.Select(0)
+		m.status = fmt.Sprintf("%d entries", len(items))
+		if msg.truncated {
+			m.status += " (truncated)"
+		}
+		return m, m.list.SetItems(items)
+
+	case fileReadMsg:
+		m.err = nil
+		m.viewing = msg.entry
+		m.sections = nil
+		if isBinary(msg.content) {
+			m.viewport.SetContent(fmt.Sprintf("Binary file (%s). Press d to download.", formatBytes(msg.entry.Size)))
+		} else {
+			var content string
+			content, m.sections = highlightSource(msg.entry.Name, msg.content)
+			m.viewport.SetContent(content)
+		}
+		m.viewport.GotoTop()
+		m.status = ""
+		if msg.entry.Size > fileReadLimit {
+			m.status = fmt.Sprintf("showing first %s of %s", formatBytes(fileReadLimit), formatBytes(msg.entry.Size))
+		}
+		m.mode = viewMode
+		return m, nil
+
+	case UploadFinishedMsg:
+		if m.mode == browseMode {
+			return m, m.listDir(m.cwd)
+		}
+		return m, nil
+
+	case fileSavedMsg:
+		m.err = nil
+		m.status = "Saved to " + msg.dst
+		return m, nil
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case saveMode:
+			return m.updateSave(msg)
+		case viewMode:
+			return m.updateView(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	// Forward everything else (filter results, cursor blinks) to the active widget
+	var cmd tea.Cmd
+	if m.mode == saveMode {
+		m.input, cmd = m.input.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m FileBrowserModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Let the list consume keys while the user is typing a filter
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Files) {
+	case "back":
+		m.cancel()
+		return m.parentModel, nil
+	case "open":
+		if item, ok := m.list.SelectedItem().(fileItem); ok {
+			if item.entry.IsDir() {
+				return m, m.listDir(item.entry.Path)
+			}
+			return m, m.readFile(item.entry)
+		}
+		return m, nil
+	case "parent":
+		if m.cwd != "/" {
+			return m, m.listDir(path.Dir(m.cwd))
+		}
+		return m, nil
+	case "download":
+		if item, ok := m.list.SelectedItem().(fileItem); ok {
+			return m, m.startSave(item.entry)
+		}
+		return m, nil
+	case "upload":
+		upload := NewUpload(m.ctx, m.dockerClient, m.containerID, m.containerName, m.cwd, m.cfg, m.width, m.height, m)
+		return upload, upload.Init()
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m FileBrowserModel) updateView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Viewer) {
+	case "back":
+		m.mode = browseMode
+		m.status = ""
+	case "down":
+		m.viewport.LineDown(1)
+	case "up":
+		m.viewport.LineUp(1)
+	case "top":
+		m.viewport.GotoTop()
+	case "bottom":
+		m.viewport.GotoBottom()
+	case "page_down":
+		m.viewport.HalfViewDown()
+	case "page_up":
+		m.viewport.HalfViewUp()
+	case "next_section":
+		// Jump to the next section below the top of the viewport
+		for _, line := range m.sections {
+			if line > m.viewport.YOffset {
+				m.viewport.SetYOffset(line)
+				break
+			}
+		}
+	case "prev_section":
+		for i := len(m.sections) - 1; i >= 0; i-- {
+			if m.sections[i] < m.viewport.YOffset {
+				m.viewport.SetYOffset(m.sections[i])
+				break
+			}
+		}
+	case "download":
+		return m, m.startSave(m.viewing)
+	}
+	return m, nil
+}
+
+func (m FileBrowserModel) updateSave(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.cfg.Keys.Matches(msg, keymap.Files, "toggle_format") {
+		// Toggle between an extracted copy and a raw tar archive
+		m.extract = !m.extract
+		value := m.input.Value()
+		if m.extract {
+			value = strings.TrimSuffix(value, ".tar")
+		} else if !strings.HasSuffix(value, ".tar") {
+			value += ".tar"
+		}
+		m.input.SetValue(value)
+		m.input.CursorEnd()
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.input.Blur()
+		m.mode = browseMode
+		if m.viewing.Path == m.saveTarget.Path {
+			m.mode = viewMode
+		}
+		return m, nil
+	case "submit":
+		dst := strings.TrimSpace(m.input.Value())
+		if dst == "" {
+			return m, nil
+		}
+		m.input.Blur()
+		m.mode = browseMode
+		if m.viewing.Path == m.saveTarget.Path {
+			m.mode = viewMode
+		}
+		m.status = "Saving " + m.saveTarget.Path + "..."
+		return m, m.saveFile(m.saveTarget, dst, m.extract)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m FileBrowserModel) View() string {
+	if m.help.visible {
+		scope := keymap.Files
+		if m.mode == viewMode {
+			scope = keymap.Viewer
+		}
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, scope, keymap.Form, keymap.Global)
+	}
+
+	modalWidth := m.width * 85 / 100
+	modalHeight := m.height * 80 / 100
+
+	location := m.cwd
+	if m.mode == viewMode {
+		location = m.viewing.Path
+	}
+	header := browserTitleStyle.Render(fmt.Sprintf("%s:%s", m.containerName, location))
+
+	keys := m.cfg.Keys
+	var body, help string
+	switch m.mode {
+	case viewMode:
+		body = m.viewport.View()
+		help = keys.Short(keymap.Viewer, "page_down", "page_up", "next_section", "prev_section", "download", "back") +
+			" • " + keys.Short(keymap.Global, "help")
+	case saveMode:
+		format := "extracted copy"
+		if !m.extract {
+			format = "tar archive"
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			"Download "+m.saveTarget.Path+" as "+format,
+			"",
+			m.input.View())
+		help = keys.Short(keymap.Files, "toggle_format") + " • " + keys.Short(keymap.Form, "submit", "cancel")
+	default:
+		body = m.list.View()
+		help = keys.Short(keymap.Files, "open", "parent", "download", "upload") + " • /: filter • " +
+			keys.Short(keymap.Files, "back") + " • " + keys.Short(keymap.Global, "help")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		body,
+		"",
+		status,
+		browserHelpStyle.Render(help),
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Width(modalWidth).
+		Height(modalHeight).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Border).
+		Padding(1, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
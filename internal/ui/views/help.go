@@ -0,0 +1,61 @@
+package views
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+// helpToggle tracks whether a screen shows the help overlay
+type helpToggle struct {
+	visible bool
+}
+
+// handle opens the overlay on the help key and closes it on any key. It
+// reports whether the key was consumed.
+func (h *helpToggle) handle(keys keymap.KeyMap, msg tea.KeyMsg) bool {
+	if h.visible {
+		h.visible = false
+		return true
+	}
+	if keys.Matches(msg, keymap.Global, "help") {
+		h.visible = true
+		return true
+	}
+	return false
+}
+
+// HelpOverlay lists the active bindings of the given scopes in a box
+// centered on the screen
+func HelpOverlay(keys keymap.KeyMap, width, height int, scopes ...keymap.Scope) string {
+	var sections []string
+	for _, scope := range scopes {
+		bindings := keys.Bindings(scope)
+
+		keyWidth := 0
+		for _, b := range bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(b.Help().Key))
+		}
+
+		lines := []string{titleStyle.Render(scope.Title())}
+		for _, b := range bindings {
+			h := b.Help()
+			lines = append(lines, helpKeyStyle.Width(keyWidth+2).Render(h.Key)+helpDescStyle.Render(h.Desc))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	footer := noSelectionStyle.Render("Press any key to close")
+	box := helpBoxStyle.Render(strings.Join(sections, "\n\n") + "\n\n" + footer)
+	if height > 0 && lipgloss.Height(box) > height {
+		// Too tall for the terminal, put the scopes side by side instead
+		for i := range sections[1:] {
+			sections[i+1] = lipgloss.NewStyle().PaddingLeft(4).Render(sections[i+1])
+		}
+		box = helpBoxStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, sections...) + "\n\n" + footer)
+		box = lipgloss.NewStyle().MaxWidth(width).MaxHeight(height).Render(box)
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
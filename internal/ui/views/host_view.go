@@ -0,0 +1,271 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+// certWarning is how early a certificate's expiry is flagged
+const certWarning = 30 * 24 * time.Hour
+
+type hostInfoMsg struct {
+	info docker.HostInfo
+	caps docker.Capabilities
+	err  error
+}
+
+// HostViewModel shows the daemon the UI is connected to and the expiry of
+// its TLS certificates
+type HostViewModel struct {
+	dockerClient docker.Runtime
+	ctx          context.Context
+	cancel       context.CancelFunc
+	target       string
+	info         docker.HostInfo
+	caps         docker.Capabilities
+	viewport     viewport.Model
+	cfg          config.Config
+	help         helpToggle
+	loaded       bool
+	err          error
+	width        int
+	height       int
+	parentModel  tea.Model
+}
+
+// NewHostView creates the host details screen for the connected daemon
+func NewHostView(ctx context.Context, dockerClient docker.Runtime, target string, cfg config.Config, width, height int, parentModel tea.Model) HostViewModel {
+	ctx, cancel := context.WithCancel(ctx)
+	m := HostViewModel{
+		dockerClient: dockerClient,
+		ctx:          ctx,
+		cancel:       cancel,
+		target:       target,
+		viewport:     viewport.New(0, 0),
+		cfg:          cfg,
+		parentModel:  parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m HostViewModel) Init() tea.Cmd {
+	return m.fetchHostInfo()
+}
+
+func (m HostViewModel) fetchHostInfo() tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		info, err := client.HostInfo(ctx)
+		if err != nil {
+			return hostInfoMsg{info: info, caps: docker.DefaultCapabilities(), err: err}
+		}
+		caps, err := client.Capabilities(ctx)
+		return hostInfoMsg{info: info, caps: caps, err: err}
+	}
+}
+
+func (m *HostViewModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width - 4
+	m.viewport.Height = height - 6
+}
+
+// Update implements tea.Model
+func (m HostViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+
+	case hostInfoMsg:
+		// A failed call still carries what could be learned offline
+		m.info, m.caps, m.err, m.loaded = msg.info, msg.caps, msg.err, true
+
+	case tea.KeyMsg:
+		if m.help.handle(m.cfg.Keys, msg) {
+			return m, nil
+		}
+		switch m.cfg.Keys.Action(msg, keymap.Host) {
+		case "back":
+			m.cancel()
+			return m.parentModel, nil
+		case "refresh":
+			return m, m.fetchHostInfo()
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	m.viewport.SetContent(m.details())
+	return m, nil
+}
+
+// details renders the host information
+func (m HostViewModel) details() string {
+	if !m.loaded {
+		return noSelectionStyle.Render("Loading...")
+	}
+
+	info := m.info
+	if len(info.Members) > 0 {
+		return m.members()
+	}
+	row := func(label, value string) string {
+		return labelStyle.Render(fmt.Sprintf("%-10s", label)) + " " + valueStyle.Render(orDash(value))
+	}
+	api := info.APIVersion
+	if info.ClientAPIVersion != "" && info.ClientAPIVersion != api {
+		api += ", using " + info.ClientAPIVersion
+	}
+	rows := []string{
+		row("Target:", m.target),
+		row("Address:", info.Address),
+		row("Context:", info.Context),
+		row("Engine:", strings.TrimSpace(info.Product+" "+info.ServerVersion)),
+		row("API:", api),
+		row("Platform:", strings.Trim(info.OS+"/"+info.Arch, "/")),
+		row("Kernel:", info.KernelVersion),
+	}
+	rows = append(rows, m.capabilities(row)...)
+	rows = append(rows, "")
+
+	if info.TLS == nil {
+		rows = append(rows, labelStyle.Render("TLS")+" "+noSelectionStyle.Render("not used"))
+		return strings.Join(rows, "\n")
+	}
+
+	verify := "yes"
+	if !info.TLS.Verify {
+		verify = "no (the daemon's certificate is not checked)"
+	}
+	rows = append(rows,
+		labelStyle.Render("TLS"),
+		row("CA:", info.TLS.CA),
+		row("Cert:", info.TLS.Cert),
+		row("Key:", info.TLS.Key),
+		row("Verify:", verify),
+		"",
+		labelStyle.Render("Certificates"),
+	)
+	for _, c := range info.Certificates {
+		rows = append(rows,
+			fmt.Sprintf("  %-8s %s", c.Role, c.Subject),
+			fmt.Sprintf("  %-8s issued by %s", "", c.Issuer),
+			fmt.Sprintf("  %-8s %s", "", m.expiry(c)),
+		)
+	}
+	for _, e := range info.CertErrors {
+		rows = append(rows, browserErrorStyle.Render("  "+e))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// members renders the health of every host of a fleet
+func (m HostViewModel) members() string {
+	rows := []string{
+		labelStyle.Render(fmt.Sprintf("%-10s", "Target:")) + " " + valueStyle.Render(m.target),
+		"",
+	}
+	for _, h := range m.info.Members {
+		state := valueStyle.Render("up")
+		if !h.Up {
+			state = browserErrorStyle.Render("down")
+		}
+		rows = append(rows,
+			labelStyle.Render(h.Name)+" "+state,
+			fmt.Sprintf("  %-11s %s", "Address:", orDash(h.Address)),
+			fmt.Sprintf("  %-11s %s", "Engine:", orDash(h.ServerVersion)),
+			fmt.Sprintf("  %-11s %d running of %d", "Containers:", h.Running, h.Containers),
+			fmt.Sprintf("  %-11s %s", "Last seen:", lastSeen(h.LastSeen)),
+		)
+		if h.Err != nil {
+			rows = append(rows, browserErrorStyle.Render("  "+h.Err.Error()))
+		}
+		rows = append(rows, "")
+	}
+	return strings.Join(rows, "\n")
+}
+
+// capabilities renders what the daemon is and the features it lacks
+func (m HostViewModel) capabilities(row func(label, value string) string) []string {
+	caps := m.caps
+	cgroup := caps.CgroupVersion
+	if cgroup != "" {
+		cgroup = "v" + cgroup
+	}
+	var traits []string
+	for _, t := range []struct {
+		on   bool
+		name string
+	}{{caps.Rootless, "rootless"}, {caps.Experimental, "experimental"}, {caps.Podman, "podman"}} {
+		if t.on {
+			traits = append(traits, t.name)
+		}
+	}
+	rows := []string{
+		row("Storage:", caps.StorageDriver),
+		row("Cgroup:", cgroup),
+		row("Swarm:", caps.Swarm),
+		row("Mode:", strings.Join(traits, ", ")),
+	}
+	for _, f := range []docker.Feature{docker.FeatureStats, docker.FeatureFiles, docker.FeatureResourceLimits} {
+		if err := caps.Supports(f); err != nil {
+			rows = append(rows, browserErrorStyle.Render("  "+err.Error()))
+		}
+	}
+	return rows
+}
+
+// lastSeen describes how long ago a host last answered
+func lastSeen(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
+
+// expiry describes when a certificate lapses, flagged when it is close
+func (m HostViewModel) expiry(c docker.CertInfo) string {
+	left := c.ExpiresIn(time.Now())
+	date := c.NotAfter.Format("2006-01-02")
+	days := int(left.Hours() / 24)
+	switch {
+	case left <= 0:
+		return browserErrorStyle.Render(fmt.Sprintf("EXPIRED on %s (%d days ago)", date, -days))
+	case left < certWarning:
+		return browserErrorStyle.Render(fmt.Sprintf("expires on %s, in %d days", date, days))
+	}
+	return valueStyle.Render(fmt.Sprintf("valid until %s (%d days)", date, days))
+}
+
+// View implements tea.Model
+func (m HostViewModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Host, keymap.Global)
+	}
+
+	status := ""
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		browserTitleStyle.Render("Host details"),
+		m.viewport.View(),
+		status,
+		browserHelpStyle.Width(m.width-4).Render(m.cfg.Keys.Short(keymap.Host, "refresh", "back")+" • "+m.cfg.Keys.Short(keymap.Global, "help")),
+	))
+}
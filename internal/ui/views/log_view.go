@@ -4,10 +4,16 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/keymap"
 )
 
+// logStyle frames the log pane
+var logStyle = lipgloss.NewStyle().Padding(1)
+
 type LogViewModel struct {
 	viewport viewport.Model
+	width    int
+	height   int
 }
 
 func NewLogViewModel() LogViewModel {
@@ -20,13 +26,32 @@ func (m *LogViewModel) SetContent(content string) {
 	m.viewport.SetContent(content)
 }
 
+// SetKeys applies the log pane's scroll bindings
+func (m *LogViewModel) SetKeys(keys keymap.KeyMap) {
+	m.viewport.KeyMap = viewport.KeyMap{
+		Up:           keys.Get(keymap.Logs, "up"),
+		Down:         keys.Get(keymap.Logs, "down"),
+		PageUp:       keys.Get(keymap.Logs, "page_up"),
+		PageDown:     keys.Get(keymap.Logs, "page_down"),
+		HalfPageUp:   keys.Get(keymap.Logs, "half_up"),
+		HalfPageDown: keys.Get(keymap.Logs, "half_down"),
+	}
+}
+
 func (m LogViewModel) Update(msg tea.Msg) (LogViewModel, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = max(msg.Width-logStyle.GetHorizontalFrameSize(), 0)
+		m.viewport.Height = max(msg.Height-logStyle.GetVerticalFrameSize(), 0)
+		return m, nil
+	}
+
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
 func (m LogViewModel) View() string {
-	return lipgloss.NewStyle().Padding(1).Width(50).Render(m.viewport.View())
+	return logStyle.Width(m.width).Render(m.viewport.View())
 }
-
@@ -0,0 +1,398 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/ui/components"
+)
+
+type networkMode int
+
+const (
+	networkBrowseMode networkMode = iota
+	networkCreateMode
+	networkConfirmMode
+)
+
+// Indexes of the fields in the create network form
+const (
+	netFieldName = iota
+	netFieldDriver
+	netFieldSubnet
+	netFieldGateway
+)
+
+// OpenNetworksMsg asks the main model to open the networks screen
+type OpenNetworksMsg struct{}
+
+// NetworksFetchedMsg carries the result of listing networks
+type NetworksFetchedMsg struct {
+	Networks []docker.Network
+}
+
+type networkChangedMsg struct {
+	status string
+}
+
+type networkItem struct {
+	network docker.Network
+}
+
+func (i networkItem) Title() string { return i.network.Name }
+
+func (i networkItem) Description() string {
+	return fmt.Sprintf("%s • %d container(s)", i.network.Driver, len(i.network.Containers))
+}
+
+func (i networkItem) FilterValue() string { return i.network.Name }
+
+// NetworkViewModel lists networks and shows their attached containers
+type NetworkViewModel struct {
+	dockerClient docker.Runtime
+	ctx          context.Context
+	cancel       context.CancelFunc
+	list         list.Model
+	details      viewport.Model
+	form         components.FormModel
+	caps         docker.Capabilities
+	cfg          config.Config
+	help         helpToggle
+	mode         networkMode
+	status       string
+	err          error
+	width        int
+	height       int
+	parentModel  tea.Model
+}
+
+// NewNetworkView creates the networks screen
+func NewNetworkView(ctx context.Context, dockerClient docker.Runtime, caps docker.Capabilities, cfg config.Config, width, height int, parentModel tea.Model) NetworkViewModel {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Networks"
+	l.Styles.Title = lipgloss.NewStyle().MarginLeft(2)
+	l.SetShowHelp(false)
+
+	form := components.NewForm("Name", "Driver", "Subnet", "Gateway")
+	form.SetPlaceholder(netFieldDriver, caps.DefaultNetworkDriver())
+	form.SetPlaceholder(netFieldSubnet, "172.30.0.0/16 (optional)")
+	form.SetPlaceholder(netFieldGateway, "172.30.0.1 (optional)")
+	form.SetKeys(cfg.Keys)
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := NetworkViewModel{
+		dockerClient: dockerClient,
+		ctx:          ctx,
+		cancel:       cancel,
+		list:         l,
+		details:      viewport.New(0, 0),
+		form:         form,
+		caps:         caps,
+		cfg:          cfg,
+		parentModel:  parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m NetworkViewModel) Init() tea.Cmd {
+	return m.fetchNetworks()
+}
+
+func (m NetworkViewModel) fetchNetworks() tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		networks, err := client.ListNetworks(ctx)
+		if err != nil {
+			return failed(err)
+		}
+		return NetworksFetchedMsg{Networks: networks}
+	}
+}
+
+func (m NetworkViewModel) createNetwork(opts docker.NetworkOptions) tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		if _, err := client.CreateNetwork(ctx, opts); err != nil {
+			return failed(err)
+		}
+		return networkChangedMsg{status: "Created network " + opts.Name}
+	}
+}
+
+func (m NetworkViewModel) removeNetwork(n docker.Network) tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		if err := client.RemoveNetwork(ctx, n.ID); err != nil {
+			return failed(err)
+		}
+		return networkChangedMsg{status: "Removed network " + n.Name}
+	}
+}
+
+func (m *NetworkViewModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.list.SetSize(width/3, height-4)
+	m.details.Width = width - width/3 - 6
+	m.details.Height = height - 6
+}
+
+// selected returns the highlighted network, if any
+func (m NetworkViewModel) selected() (docker.Network, bool) {
+	item, ok := m.list.SelectedItem().(networkItem)
+	return item.network, ok
+}
+
+// refreshDetails renders the selected network into the details pane
+func (m *NetworkViewModel) refreshDetails() {
+	n, ok := m.selected()
+	if !ok {
+		m.details.SetContent(noSelectionStyle.Render("No networks"))
+		return
+	}
+
+	internal := "no"
+	if n.Internal {
+		internal = "yes"
+	}
+
+	rows := []string{
+		titleStyle.Render(n.Name),
+		"",
+		labelStyle.Render("ID:      ") + valueStyle.Render(shortID(n.ID)),
+		labelStyle.Render("Driver:  ") + valueStyle.Render(n.Driver),
+		labelStyle.Render("Scope:   ") + valueStyle.Render(n.Scope),
+		labelStyle.Render("Subnet:  ") + valueStyle.Render(orDash(n.Subnet)),
+		labelStyle.Render("Gateway: ") + valueStyle.Render(orDash(n.Gateway)),
+		labelStyle.Render("Internal:") + " " + valueStyle.Render(internal),
+		"",
+		labelStyle.Render(fmt.Sprintf("Containers (%d)", len(n.Containers))),
+	}
+	if len(n.Containers) == 0 {
+		rows = append(rows, noSelectionStyle.Render("  none attached"))
+	}
+	for _, ep := range n.Containers {
+		rows = append(rows, fmt.Sprintf("  %-30s %s", ep.Name, valueStyle.Render(orDash(ep.IPv4Address))))
+	}
+
+	m.details.SetContent(strings.Join(rows, "\n"))
+}
+
+// Update implements tea.Model
+func (m NetworkViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case NetworksFetchedMsg:
+		items := make([]list.Item, len(msg.Networks))
+		for i, n := range msg.Networks {
+			items[i] = networkItem{network: n}
+		}
+		cmd := m.list.SetItems(items)
+		m.refreshDetails()
+		return m, cmd
+
+	case networkChangedMsg:
+		m.err = nil
+		m.status = msg.status
+		return m, m.fetchNetworks()
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case networkCreateMode:
+			return m.updateCreate(msg)
+		case networkConfirmMode:
+			return m.updateConfirm(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.mode == networkCreateMode {
+		m.form, cmd = m.form.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m NetworkViewModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Networks) {
+	case "back":
+		m.cancel()
+		return m.parentModel, nil
+	case "refresh":
+		return m, m.fetchNetworks()
+	case "create":
+		m.err = nil
+		m.mode = networkCreateMode
+		for i := 0; i < m.form.Len(); i++ {
+			m.form.SetValue(i, "")
+		}
+		return m, m.form.Focus(netFieldName)
+	case "remove":
+		if n, ok := m.selected(); ok {
+			if !n.IsUserDefined() {
+				m.err = fmt.Errorf("%s is a predefined network and cannot be removed", n.Name)
+				return m, nil
+			}
+			m.err = nil
+			if !m.cfg.Confirm.RemoveNetwork {
+				m.status = "Removing network " + n.Name + "..."
+				return m, m.removeNetwork(n)
+			}
+			m.mode = networkConfirmMode
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	m.refreshDetails()
+	return m, cmd
+}
+
+func (m NetworkViewModel) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.form.Blur()
+		m.mode = networkBrowseMode
+		return m, nil
+	case "submit":
+		opts := docker.NetworkOptions{
+			Name:    m.form.Value(netFieldName),
+			Driver:  m.form.Value(netFieldDriver),
+			Subnet:  m.form.Value(netFieldSubnet),
+			Gateway: m.form.Value(netFieldGateway),
+		}
+		if opts.Name == "" {
+			m.err = fmt.Errorf("network name is required")
+			return m, m.form.Focus(netFieldName)
+		}
+		if opts.Driver == "" {
+			opts.Driver = m.caps.DefaultNetworkDriver()
+		}
+		if opts.Driver == "overlay" {
+			if err := m.caps.Supports(docker.FeatureOverlayNetworks); err != nil {
+				m.err = err
+				return m, m.form.Focus(netFieldDriver)
+			}
+		}
+		m.err = nil
+		m.form.Blur()
+		m.mode = networkBrowseMode
+		m.status = "Creating network " + opts.Name + "..."
+		return m, m.createNetwork(opts)
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+	return m, cmd
+}
+
+func (m NetworkViewModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = networkBrowseMode
+	if m.cfg.Keys.Matches(msg, keymap.Confirm, "yes") {
+		if n, ok := m.selected(); ok {
+			m.status = "Removing network " + n.Name + "..."
+			return m, m.removeNetwork(n)
+		}
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m NetworkViewModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Networks, keymap.Form, keymap.Confirm, keymap.Global)
+	}
+
+	keys := m.cfg.Keys
+	var right, help string
+	switch m.mode {
+	case networkCreateMode:
+		right = lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render("Create network"),
+			"",
+			m.form.View())
+		help = keys.Short(keymap.Form, "next", "submit", "cancel")
+	case networkConfirmMode:
+		n, _ := m.selected()
+		right = lipgloss.JoinVertical(lipgloss.Left,
+			m.details.View(),
+			"",
+			titleStyle.Render(fmt.Sprintf("Remove network %s?", n.Name)))
+		help = keys.Short(keymap.Confirm, "yes", "no")
+	default:
+		right = m.details.View()
+		help = keys.Short(keymap.Networks, "create", "remove", "refresh") + " • /: filter • " +
+			keys.Short(keymap.Networks, "back") + " • " + keys.Short(keymap.Global, "help")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	// The help wraps on narrow terminals, the panes give up the extra lines
+	help = browserHelpStyle.Width(m.width).Render(help)
+	paneHeight := m.height - 3 - lipgloss.Height(help)
+
+	left := lipgloss.NewStyle().
+		Width(m.width / 3).
+		Height(paneHeight).
+		Render(m.list.View())
+
+	rightPane := statsBoxStyle.
+		Width(m.width - m.width/3 - 4).
+		Height(paneHeight).
+		Render(right)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, left, rightPane),
+		status,
+		help)
+}
+
+// shortID trims a Docker ID to the usual 12 characters
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// orDash returns "-" for empty values
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
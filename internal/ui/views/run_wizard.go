@@ -0,0 +1,736 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/go-units"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+	"github.com/shubhamku044/containix/internal/shellwords"
+	"github.com/shubhamku044/containix/internal/ui/components"
+)
+
+// OpenRunMsg asks the main model to open the run container wizard
+type OpenRunMsg struct{}
+
+// ContainerCreatedMsg asks the container list to show and select a
+// container that was just created
+type ContainerCreatedMsg struct {
+	ID string
+}
+
+type runMode int
+
+const (
+	runPresetMode runMode = iota
+	runStepMode
+	runReviewMode
+	runSaveMode
+)
+
+// runField is one input of the wizard. Its option is the name
+// docker.InvalidOptionError reports it under.
+type runField struct {
+	option      string
+	label       string
+	placeholder string
+}
+
+// runSteps are the pages of the wizard in order
+var runSteps = []struct {
+	title  string
+	fields []runField
+}{
+	{"Image", []runField{
+		{"host", "Host", ""},
+		{"image", "Image", "e.g. nginx:1.25"},
+		{"name", "Name", "optional"},
+		{"command", "Command", "optional, e.g. sh -c 'sleep 60'"},
+	}},
+	{"Environment and ports", []runField{
+		{"env", "Environment", "KEY=value, separated by spaces"},
+		{"ports", "Ports", "e.g. 8080:80 127.0.0.1:8443:443"},
+	}},
+	{"Storage and network", []runField{
+		{"volumes", "Volumes", "e.g. /srv/data:/data:ro cache:/cache"},
+		{"network", "Network", "bridge"},
+		{"restart", "Restart policy", "no, always, unless-stopped or on-failure[:N]"},
+	}},
+	{"Resources", []runField{
+		{"cpus", "CPUs", "e.g. 0.5, empty for no limit"},
+		{"memory", "Memory", "e.g. 512m, empty for no limit"},
+	}},
+}
+
+// runStep is a page of the wizard with its form
+type runStep struct {
+	title  string
+	fields []runField
+	form   components.FormModel
+}
+
+type presetsLoadedMsg struct {
+	presets map[string]config.Preset
+	err     error
+}
+
+type presetSavedMsg struct {
+	name   string
+	preset config.Preset
+}
+
+type runNetworksMsg struct {
+	names []string
+}
+
+type containerRunMsg struct {
+	id string
+}
+
+// presetItem is a preset in the picker, or an empty form when name is ""
+type presetItem struct {
+	name   string
+	preset config.Preset
+}
+
+func (i presetItem) Title() string {
+	if i.name == "" {
+		return "New container"
+	}
+	return i.name
+}
+
+func (i presetItem) Description() string {
+	if i.name == "" {
+		return "start from an empty form"
+	}
+	return i.preset.Image
+}
+
+func (i presetItem) FilterValue() string { return i.name }
+
+// RunWizardModel walks through the options of a new container, shows the
+// equivalent docker run command and creates it
+type RunWizardModel struct {
+	dockerClient docker.Runtime
+	ctx          context.Context
+	cancel       context.CancelFunc
+	caps         docker.Capabilities
+	cfg          config.Config
+	hosts        []string // fleet hosts, empty for a single daemon
+	presetsPath  string
+	presets      map[string]config.Preset
+	presetList   list.Model
+	networks     []string
+	steps        []runStep
+	step         int
+	mode         runMode
+	nameForm     components.FormModel
+	opts         docker.RunOptions // what the review shows
+	help         helpToggle
+	activity     Activity
+	busy         bool
+	status       string
+	err          error
+	width        int
+	height       int
+	parentModel  tea.Model
+}
+
+// NewRunWizard creates the run container wizard. Presets are kept in the
+// file at presetsPath, and cannot be saved when it is empty.
+func NewRunWizard(ctx context.Context, dockerClient docker.Runtime, caps docker.Capabilities, cfg config.Config, presetsPath string, width, height int, parentModel tea.Model) RunWizardModel {
+	var hosts []string
+	if fleet, ok := dockerClient.(*docker.Fleet); ok {
+		hosts = fleet.Hosts()
+	}
+
+	var steps []runStep
+	for _, s := range runSteps {
+		step := runStep{title: s.title}
+		for _, f := range s.fields {
+			if f.option == "host" {
+				if len(hosts) == 0 {
+					continue
+				}
+				f.placeholder = hosts[0]
+			}
+			step.fields = append(step.fields, f)
+		}
+		labels := make([]string, len(step.fields))
+		for i, f := range step.fields {
+			labels[i] = f.label
+		}
+		step.form = components.NewForm(labels...)
+		for i, f := range step.fields {
+			step.form.SetPlaceholder(i, f.placeholder)
+		}
+		step.form.SetKeys(cfg.Keys)
+		steps = append(steps, step)
+	}
+
+	nameForm := components.NewForm("Preset name")
+	nameForm.SetKeys(cfg.Keys)
+
+	presetList := list.New([]list.Item{presetItem{}}, list.NewDefaultDelegate(), 0, 0)
+	presetList.SetShowTitle(false)
+	presetList.SetShowHelp(false)
+	presetList.SetShowStatusBar(false)
+	presetList.SetFilteringEnabled(false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := RunWizardModel{
+		dockerClient: dockerClient,
+		ctx:          ctx,
+		cancel:       cancel,
+		caps:         caps,
+		cfg:          cfg,
+		hosts:        hosts,
+		presetsPath:  presetsPath,
+		presets:      map[string]config.Preset{},
+		presetList:   presetList,
+		steps:        steps,
+		mode:         runStepMode,
+		nameForm:     nameForm,
+		activity:     NewActivity(),
+		parentModel:  parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m RunWizardModel) Init() tea.Cmd {
+	return tea.Batch(m.loadPresets(), m.fetchNetworks(), m.steps[0].form.Focus(0))
+}
+
+func (m RunWizardModel) loadPresets() tea.Cmd {
+	path := m.presetsPath
+	return func() tea.Msg {
+		presets, err := config.LoadPresets(path)
+		return presetsLoadedMsg{presets: presets, err: err}
+	}
+}
+
+func (m RunWizardModel) savePreset(name string, preset config.Preset) tea.Cmd {
+	path := m.presetsPath
+	return func() tea.Msg {
+		if err := config.SavePreset(path, name, preset); err != nil {
+			return ErrMsg{Err: err}
+		}
+		return presetSavedMsg{name: name, preset: preset}
+	}
+}
+
+func (m RunWizardModel) fetchNetworks() tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		networks, err := client.ListNetworks(ctx)
+		if err != nil {
+			// The networks are only a hint, the daemon checks the name
+			return runNetworksMsg{}
+		}
+		names := make([]string, len(networks))
+		for i, n := range networks {
+			names[i] = n.Name
+		}
+		slices.Sort(names)
+		return runNetworksMsg{names: names}
+	}
+}
+
+func (m RunWizardModel) runContainer(opts docker.RunOptions) tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		id, err := client.RunContainer(ctx, opts)
+		if err != nil {
+			return failed(err)
+		}
+		return containerRunMsg{id: id}
+	}
+}
+
+func (m *RunWizardModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.presetList.SetSize(width-8, height-10)
+}
+
+// value returns what was typed for an option
+func (m RunWizardModel) value(option string) string {
+	for _, s := range m.steps {
+		for i, f := range s.fields {
+			if f.option == option {
+				return s.form.Value(i)
+			}
+		}
+	}
+	return ""
+}
+
+// locate returns the step and field index of an option
+func (m RunWizardModel) locate(option string) (int, int, bool) {
+	for step, s := range m.steps {
+		for i, f := range s.fields {
+			if f.option == option {
+				return step, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// options turns what was typed into run options, returning an
+// *docker.InvalidOptionError for the first problem
+func (m RunWizardModel) options() (docker.RunOptions, error) {
+	invalid := func(option string, err error) (docker.RunOptions, error) {
+		return docker.RunOptions{}, &docker.InvalidOptionError{Option: option, Err: err}
+	}
+
+	opts := docker.RunOptions{
+		Image:   m.value("image"),
+		Name:    m.value("name"),
+		Network: m.value("network"),
+		Restart: m.value("restart"),
+	}
+	if len(m.hosts) > 0 {
+		opts.Host = m.value("host")
+		if opts.Host == "" {
+			opts.Host = m.hosts[0]
+		}
+		if !slices.Contains(m.hosts, opts.Host) {
+			return invalid("host", fmt.Errorf("unknown host %q (hosts: %s)", opts.Host, strings.Join(m.hosts, ", ")))
+		}
+	}
+
+	for _, list := range []struct {
+		option string
+		dst    *[]string
+	}{
+		{"command", &opts.Command},
+		{"env", &opts.Env},
+		{"ports", &opts.Ports},
+		{"volumes", &opts.Volumes},
+	} {
+		words, err := shellwords.Split(m.value(list.option))
+		if err != nil {
+			return invalid(list.option, err)
+		}
+		*list.dst = words
+	}
+
+	if opts.Network != "" && len(m.networks) > 0 && !m.knownNetwork(opts.Network) {
+		return invalid("network", fmt.Errorf("no network %q", opts.Network))
+	}
+	if cpus := m.value("cpus"); cpus != "" {
+		n, err := strconv.ParseFloat(cpus, 64)
+		if err != nil {
+			return invalid("cpus", fmt.Errorf("%q is not a number", cpus))
+		}
+		opts.CPUs = n
+	}
+	if memory := m.value("memory"); memory != "" {
+		n, err := units.RAMInBytes(memory)
+		if err != nil {
+			return invalid("memory", fmt.Errorf("%q is not a size such as 512m", memory))
+		}
+		opts.Memory = n
+	}
+	if opts.CPUs > 0 || opts.Memory > 0 {
+		if err := m.caps.Supports(docker.FeatureResourceLimits); err != nil {
+			option := "cpus"
+			if opts.CPUs == 0 {
+				option = "memory"
+			}
+			return invalid(option, err)
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return docker.RunOptions{}, err
+	}
+	return opts, nil
+}
+
+// knownNetwork reports whether a network of that name exists. Fleet
+// networks may also be named without their host.
+func (m RunWizardModel) knownNetwork(name string) bool {
+	for _, n := range m.networks {
+		if n == name || strings.HasSuffix(n, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// preset returns what was typed, to be saved. The host is left out, as
+// presets are shared between daemons.
+func (m RunWizardModel) preset() config.Preset {
+	return config.Preset{
+		Image:   m.value("image"),
+		Name:    m.value("name"),
+		Command: m.value("command"),
+		Env:     m.opts.Env,
+		Ports:   m.opts.Ports,
+		Volumes: m.opts.Volumes,
+		Network: m.value("network"),
+		Restart: m.value("restart"),
+		CPUs:    m.value("cpus"),
+		Memory:  m.value("memory"),
+	}
+}
+
+// fill replaces what was typed with a preset
+func (m *RunWizardModel) fill(p config.Preset) {
+	quote := func(words []string) string {
+		quoted := make([]string, len(words))
+		for i, w := range words {
+			quoted[i] = docker.ShellQuote(w)
+		}
+		return strings.Join(quoted, " ")
+	}
+	values := map[string]string{
+		"image":   p.Image,
+		"name":    p.Name,
+		"command": p.Command,
+		"env":     quote(p.Env),
+		"ports":   quote(p.Ports),
+		"volumes": quote(p.Volumes),
+		"network": p.Network,
+		"restart": p.Restart,
+		"cpus":    p.CPUs,
+		"memory":  p.Memory,
+	}
+	for s := range m.steps {
+		for i, f := range m.steps[s].fields {
+			m.steps[s].form.SetValue(i, values[f.option])
+		}
+	}
+}
+
+// check validates the steps up to and including last. On a problem it
+// moves to the field at fault and returns false.
+func (m *RunWizardModel) check(last int) (tea.Cmd, bool) {
+	opts, err := m.options()
+	if err == nil {
+		m.err = nil
+		m.opts = opts
+		return nil, true
+	}
+	var invalid *docker.InvalidOptionError
+	step, field, found := 0, 0, false
+	if errors.As(err, &invalid) {
+		step, field, found = m.locate(invalid.Option)
+	}
+	if !found {
+		step, field = last, m.steps[last].form.Focused()
+	}
+	if step > last {
+		// A later step's problem is raised when that step is done
+		m.err = nil
+		return nil, true
+	}
+	m.err = err
+	m.mode = runStepMode
+	m.step = step
+	return m.steps[step].form.Focus(field), false
+}
+
+// touched reports whether anything was typed yet
+func (m RunWizardModel) touched() bool {
+	for _, s := range m.steps {
+		for i := range s.fields {
+			if s.form.Value(i) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *RunWizardModel) setPresetItems() tea.Cmd {
+	items := []list.Item{presetItem{}}
+	for _, name := range config.PresetNames(m.presets) {
+		items = append(items, presetItem{name: name, preset: m.presets[name]})
+	}
+	return m.presetList.SetItems(items)
+}
+
+// close hands the screen back to the main model
+func (m RunWizardModel) close() (tea.Model, tea.Cmd) {
+	m.cancel()
+	return m.parentModel, nil
+}
+
+// Update implements tea.Model
+func (m RunWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case presetsLoadedMsg:
+		m.presets = msg.presets
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		cmd := m.setPresetItems()
+		if len(m.presets) > 0 && m.mode == runStepMode && m.step == 0 && !m.touched() {
+			m.steps[0].form.Blur()
+			m.mode = runPresetMode
+		}
+		return m, cmd
+
+	case runNetworksMsg:
+		m.networks = msg.names
+		return m, nil
+
+	case presetSavedMsg:
+		m.presets[msg.name] = msg.preset
+		m.err = nil
+		m.status = "Saved preset " + msg.name
+		m.mode = runReviewMode
+		return m, m.setPresetItems()
+
+	case containerRunMsg:
+		m.cancel()
+		id := msg.id
+		return m.parentModel, func() tea.Msg { return ContainerCreatedMsg{ID: id} }
+
+	case ErrMsg:
+		m.busy = false
+		m.err = msg.Err
+		return m, nil
+
+	case ActivityMsg, spinner.TickMsg:
+		var cmd tea.Cmd
+		m.activity, cmd = m.activity.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case runPresetMode:
+			return m.updatePreset(msg)
+		case runReviewMode:
+			return m.updateReview(msg)
+		case runSaveMode:
+			return m.updateSave(msg)
+		default:
+			return m.updateStep(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.mode {
+	case runStepMode:
+		m.steps[m.step].form, cmd = m.steps[m.step].form.Update(msg)
+	case runSaveMode:
+		m.nameForm, cmd = m.nameForm.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m RunWizardModel) updatePreset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		return m.close()
+	case "submit":
+		item, _ := m.presetList.SelectedItem().(presetItem)
+		m.fill(item.preset)
+		m.mode = runStepMode
+		m.step = 0
+		if item.name == "" {
+			return m, m.steps[0].form.Focus(0)
+		}
+		// A complete preset goes straight to the review
+		if cmd, ok := m.check(len(m.steps) - 1); !ok {
+			return m, cmd
+		}
+		m.mode = runReviewMode
+		m.status = "Loaded preset " + item.name
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.presetList, cmd = m.presetList.Update(msg)
+	return m, cmd
+}
+
+func (m RunWizardModel) updateStep(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.err = nil
+		m.steps[m.step].form.Blur()
+		if m.step == 0 {
+			if len(m.presets) > 0 {
+				m.mode = runPresetMode
+				return m, nil
+			}
+			return m.close()
+		}
+		m.step--
+		return m, m.steps[m.step].form.Focus(0)
+	case "submit":
+		if cmd, ok := m.check(m.step); !ok {
+			return m, cmd
+		}
+		m.steps[m.step].form.Blur()
+		if m.step == len(m.steps)-1 {
+			m.mode = runReviewMode
+			m.status = ""
+			return m, nil
+		}
+		m.step++
+		return m, m.steps[m.step].form.Focus(0)
+	}
+
+	var cmd tea.Cmd
+	m.steps[m.step].form, cmd = m.steps[m.step].form.Update(msg)
+	return m, cmd
+}
+
+func (m RunWizardModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.busy {
+		return m, nil
+	}
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Run) {
+	case "create":
+		m.busy = true
+		m.err = nil
+		name := m.opts.Name
+		if name == "" {
+			name = path.Base(m.opts.Image)
+		}
+		m.status = "Creating " + name + "..."
+		return m, track("Starting "+name, m.runContainer(m.opts))
+	case "save":
+		m.err = nil
+		m.mode = runSaveMode
+		name := m.opts.Name
+		if name == "" {
+			name, _, _ = strings.Cut(path.Base(m.opts.Image), ":")
+		}
+		m.nameForm.SetValue(0, name)
+		return m, m.nameForm.Focus(0)
+	case "edit":
+		m.mode = runStepMode
+		m.step = len(m.steps) - 1
+		return m, m.steps[m.step].form.Focus(0)
+	case "back":
+		return m.close()
+	}
+	return m, nil
+}
+
+func (m RunWizardModel) updateSave(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.nameForm.Blur()
+		m.mode = runReviewMode
+		return m, nil
+	case "submit":
+		name := m.nameForm.Value(0)
+		if name == "" {
+			m.err = errors.New("a preset needs a name")
+			return m, nil
+		}
+		m.nameForm.Blur()
+		return m, m.savePreset(name, m.preset())
+	}
+
+	var cmd tea.Cmd
+	m.nameForm, cmd = m.nameForm.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m RunWizardModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Run, keymap.Form, keymap.Global)
+	}
+
+	keys := m.cfg.Keys
+	var title, body, help string
+	switch m.mode {
+	case runPresetMode:
+		title = "Run container: start from a preset"
+		body = m.presetList.View()
+		help = keys.Short(keymap.Form, "submit", "cancel")
+	case runStepMode:
+		s := m.steps[m.step]
+		title = fmt.Sprintf("Run container: step %d of %d, %s", m.step+1, len(m.steps), s.title)
+		body = lipgloss.JoinVertical(lipgloss.Left, s.form.View(), "", m.hint())
+		help = keys.Short(keymap.Form, "next", "prev", "submit", "cancel")
+	case runReviewMode, runSaveMode:
+		title = "Run container: review"
+		rows := []string{valueStyle.Render(docker.FormatCommand(m.opts.Args(), m.width-8))}
+		if m.opts.Host != "" {
+			rows = append(rows, "", labelStyle.Render("on host ")+valueStyle.Render(m.opts.Host))
+		}
+		if slices.ContainsFunc(m.opts.Env, func(kv string) bool { return !strings.Contains(kv, "=") }) {
+			rows = append(rows, "", noSelectionStyle.Render("Variables given without a value are taken from the environment containix runs in, and left out when unset"))
+		}
+		if m.mode == runSaveMode {
+			rows = append(rows, "", m.nameForm.View())
+			help = keys.Short(keymap.Form, "submit", "cancel")
+		} else {
+			help = keys.Short(keymap.Run, "create", "save", "edit", "back") + " • " + keys.Short(keymap.Global, "help")
+		}
+		body = strings.Join(rows, "\n")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.activity.Visible() {
+		status = browserStatusStyle.Render(m.activity.View())
+	}
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	help = browserHelpStyle.Width(m.width).Render(help)
+	paneHeight := m.height - 3 - lipgloss.Height(help)
+	pane := statsBoxStyle.
+		Width(m.width - 4).
+		Height(paneHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), "", body))
+
+	return lipgloss.JoinVertical(lipgloss.Left, pane, status, help)
+}
+
+// hint explains the fields of the current step
+func (m RunWizardModel) hint() string {
+	var lines []string
+	for _, f := range m.steps[m.step].fields {
+		switch f.option {
+		case "command", "env", "ports", "volumes":
+			if len(lines) == 0 {
+				lines = append(lines, "Quote values that contain spaces, e.g. 'GREETING=hello world'")
+			}
+		case "network":
+			if len(m.networks) > 0 {
+				lines = append(lines, "Networks: "+strings.Join(m.networks, ", "))
+			}
+		case "cpus":
+			if err := m.caps.Supports(docker.FeatureResourceLimits); err != nil {
+				lines = append(lines, browserErrorStyle.Render(err.Error()))
+			}
+		}
+	}
+	return noSelectionStyle.Render(strings.Join(lines, "\n"))
+}
@@ -1,7 +1,9 @@
 package views
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,45 +11,27 @@ import (
 	"github.com/shubhamku044/containix/internal/docker"
 )
 
-// Styling constants
-var (
-	statsBoxStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Padding(0, 1)
-
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
-			Bold(true)
-
-	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("111")).
-			Bold(true)
-
-	valueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("86"))
-
-	noSelectionStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("240")).
-				Italic(true)
-)
-
-// StatsViewModel displays detailed stats for a selected container
+// StatsViewModel displays detailed stats for a selected container, or the
+// usage of every host of a fleet when none is selected
 type StatsViewModel struct {
 	viewport     viewport.Model
 	width        int
 	height       int
 	containerID  string
-	dockerClient *docker.Client
+	dockerClient docker.Runtime
+	ctx          context.Context
 	stats        *docker.ContainerStats
+	usage        []docker.HostUsage
+	unsupported  error // why the daemon has no stats, nil when it has
 }
 
 // NewStatsView creates a new stats view component
-func NewStatsView(dockerClient *docker.Client) StatsViewModel {
+func NewStatsView(ctx context.Context, dockerClient docker.Runtime) StatsViewModel {
 	vp := viewport.New(0, 5) // Height will be adjusted based on window size
 	return StatsViewModel{
 		viewport:     vp,
 		dockerClient: dockerClient,
+		ctx:          ctx,
 	}
 }
 
@@ -56,21 +40,56 @@ func (m *StatsViewModel) SetContainerID(id string) tea.Cmd {
 	m.containerID = id
 	if id == "" {
 		m.stats = nil
-		return nil
+		return m.fetchUsage()
+	}
+	if m.unsupported != nil {
+		m.updateViewportContent()
+	}
+	return m.fetchStats()
+}
+
+// SetCapabilities stops asking a daemon that cannot report stats and says
+// why instead
+func (m *StatsViewModel) SetCapabilities(caps docker.Capabilities) {
+	m.unsupported = caps.Supports(docker.FeatureStats)
+	m.updateViewportContent()
+}
+
+// Refresh fetches fresh stats for the selected container, or the fleet's
+// usage when none is selected
+func (m *StatsViewModel) Refresh() tea.Cmd {
+	if m.containerID == "" {
+		return m.fetchUsage()
 	}
 	return m.fetchStats()
 }
 
+// fetchUsage sums the usage of every host, outside a fleet there is nothing
+// to show
+func (m *StatsViewModel) fetchUsage() tea.Cmd {
+	fleet, ok := m.dockerClient.(*docker.Fleet)
+	if !ok {
+		return nil
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		return FleetUsageMsg{Usage: fleet.Usage(ctx)}
+	}
+}
+
 // fetchStats retrieves stats for the currently selected container
 func (m *StatsViewModel) fetchStats() tea.Cmd {
+	if m.unsupported != nil {
+		return nil
+	}
 	return func() tea.Msg {
 		if m.containerID == "" {
 			return nil
 		}
 
-		stats, err := m.dockerClient.GetContainerStats(m.containerID)
+		stats, err := m.dockerClient.GetContainerStats(m.ctx, m.containerID)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return failed(err)
 		}
 
 		return ContainerStatsMsg{Stats: stats}
@@ -97,6 +116,10 @@ func (m StatsViewModel) Update(msg tea.Msg) (StatsViewModel, tea.Cmd) {
 	case ContainerStatsMsg:
 		m.stats = msg.Stats
 		m.updateViewportContent()
+
+	case FleetUsageMsg:
+		m.usage = msg.Usage
+		m.updateViewportContent()
 	}
 
 	m.viewport, cmd = m.viewport.Update(msg)
@@ -105,6 +128,14 @@ func (m StatsViewModel) Update(msg tea.Msg) (StatsViewModel, tea.Cmd) {
 
 // updateViewportContent refreshes the content in the viewport based on current stats
 func (m *StatsViewModel) updateViewportContent() {
+	if m.containerID == "" && m.usage != nil {
+		m.viewport.SetContent(fleetUsage(m.usage))
+		return
+	}
+	if m.containerID != "" && m.unsupported != nil {
+		m.viewport.SetContent(noSelectionStyle.Width(max(m.viewport.Width, 0)).Render(m.unsupported.Error()))
+		return
+	}
 	if m.containerID == "" || m.stats == nil {
 		m.viewport.SetContent(noSelectionStyle.Render("Select a container to view stats"))
 		return
@@ -153,15 +184,53 @@ func (m *StatsViewModel) updateViewportContent() {
 func (m StatsViewModel) View() string {
 	// Render the title and content
 	title := titleStyle.Render("Container Stats")
+	if m.containerID == "" && m.usage != nil {
+		title = titleStyle.Render("Fleet")
+	}
 	content := m.viewport.View()
 
 	// Render the stats box with adjusted dimensions
 	return statsBoxStyle.
-		Width(m.width).
-		Height(m.height).
+		Width(max(m.width-statsBoxStyle.GetHorizontalBorderSize(), 0)).
+		Height(max(m.height-statsBoxStyle.GetVerticalBorderSize(), 0)).
 		Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
 }
 
+// fleetUsage renders a row per host and the fleet's totals
+func fleetUsage(usage []docker.HostUsage) string {
+	width := len("total")
+	for _, u := range usage {
+		width = max(width, len(u.Name))
+	}
+	row := func(host, status, running, cpu, memory string) string {
+		return fmt.Sprintf("%-*s  %-6s  %-7s  %7s  %s", width, host, status, running, cpu, memory)
+	}
+
+	rows := []string{labelStyle.Render(row("HOST", "STATUS", "RUNNING", "CPU", "MEMORY"))}
+	var total docker.HostUsage
+	up := 0
+	for _, u := range usage {
+		if !u.Up {
+			rows = append(rows, browserErrorStyle.Render(row(u.Name, "down", "-", "-", "-")))
+			continue
+		}
+		up++
+		total.Containers += u.Containers
+		total.Running += u.Running
+		total.CPUPercentage += u.CPUPercentage
+		total.MemoryUsage += u.MemoryUsage
+		rows = append(rows, valueStyle.Render(row(u.Name, "up",
+			fmt.Sprintf("%d/%d", u.Running, u.Containers),
+			fmt.Sprintf("%.1f%%", u.CPUPercentage),
+			formatBytes(uint64ToInt64(u.MemoryUsage)))))
+	}
+	rows = append(rows, labelStyle.Render(row("total", fmt.Sprintf("%d/%d", up, len(usage)),
+		fmt.Sprintf("%d/%d", total.Running, total.Containers),
+		fmt.Sprintf("%.1f%%", total.CPUPercentage),
+		formatBytes(uint64ToInt64(total.MemoryUsage)))))
+	return strings.Join(rows, "\n")
+}
+
 // Helper function to format bytes to human-readable format
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -189,3 +258,8 @@ func uint64ToInt64(val uint64) int64 {
 type ContainerStatsMsg struct {
 	Stats *docker.ContainerStats
 }
+
+// FleetUsageMsg is sent when the usage of a fleet's hosts is fetched
+type FleetUsageMsg struct {
+	Usage []docker.HostUsage
+}
@@ -0,0 +1,64 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shubhamku044/containix/internal/docker"
+)
+
+// StatusBar renders the one-line bar under the main screen: the daemon the
+// UI is connected to, followed by notes such as read-only mode
+func StatusBar(width int, target string, notes ...string) string {
+	parts := append([]string{"context: " + statusTargetStyle.Render(target)}, notes...)
+	return statusBarStyle.
+		Width(width).
+		MaxWidth(width).
+		Render(strings.Join(parts, " • "))
+}
+
+// HealthNote describes the connection to the daemon for the status bar,
+// empty until it was first checked
+func HealthNote(h docker.Health) string {
+	if h.Since.IsZero() {
+		return ""
+	}
+	switch h.State {
+	case docker.Degraded:
+		if errors.Is(h.Err, docker.ErrDegraded) {
+			// The hosts note names the hosts that are down
+			return browserErrorStyle.Render("degraded")
+		}
+		return browserErrorStyle.Render("degraded: " + h.Err.Error())
+	case docker.Disconnected:
+		return browserErrorStyle.Render(fmt.Sprintf("disconnected, retrying in %s", h.Backoff()))
+	}
+	return "connected"
+}
+
+// HostsNote summarizes the health of a fleet's hosts for the status bar,
+// naming the ones that are down
+func HostsNote(hosts []docker.HostStatus) string {
+	up, checked := 0, 0
+	var down []string
+	for _, h := range hosts {
+		switch {
+		case h.Checked.IsZero():
+			continue
+		case h.Up:
+			up++
+		default:
+			down = append(down, h.Name)
+		}
+		checked++
+	}
+	if checked == 0 {
+		return "checking hosts..."
+	}
+	note := fmt.Sprintf("%d/%d hosts up", up, len(hosts))
+	if len(down) > 0 {
+		note += " " + browserErrorStyle.Render("(down: "+strings.Join(down, ", ")+")")
+	}
+	return note
+}
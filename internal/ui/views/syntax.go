@@ -0,0 +1,113 @@
+package views
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// syntax describes the few rules needed to highlight a config-style file
+type syntax struct {
+	comment   string // line comment prefix
+	separator string // key/value separator, empty if keys are not highlighted
+	sections  bool   // whether [section] headers are used
+}
+
+var syntaxByExt = map[string]syntax{
+	".yaml":       {comment: "#", separator: ":"},
+	".yml":        {comment: "#", separator: ":"},
+	".toml":       {comment: "#", separator: "=", sections: true},
+	".ini":        {comment: ";", separator: "=", sections: true},
+	".cfg":        {comment: "#", separator: "=", sections: true},
+	".conf":       {comment: "#"},
+	".env":        {comment: "#", separator: "="},
+	".properties": {comment: "#", separator: "="},
+	".sh":         {comment: "#"},
+	".py":         {comment: "#"},
+	".rb":         {comment: "#"},
+	".json":       {separator: ":"},
+	".go":         {comment: "//"},
+	".js":         {comment: "//"},
+	".ts":         {comment: "//"},
+	".java":       {comment: "//"},
+	".c":          {comment: "//"},
+	".h":          {comment: "//"},
+	".sql":        {comment: "--"},
+	".lua":        {comment: "--"},
+}
+
+var syntaxByName = map[string]syntax{
+	"dockerfile":  {comment: "#"},
+	"makefile":    {comment: "#"},
+	"nginx.conf":  {comment: "#"},
+	"hosts":       {comment: "#"},
+	"resolv.conf": {comment: "#"},
+	"passwd":      {separator: ":"},
+	"group":       {separator: ":"},
+}
+
+// detectSyntax picks highlighting rules from a file name
+func detectSyntax(name string) syntax {
+	base := strings.ToLower(path.Base(name))
+	if s, ok := syntaxByName[base]; ok {
+		return s
+	}
+	if s, ok := syntaxByExt[path.Ext(base)]; ok {
+		return s
+	}
+	if strings.HasPrefix(base, ".") || strings.HasSuffix(base, "rc") {
+		return syntax{comment: "#"}
+	}
+	return syntax{}
+}
+
+// highlightSource renders file content with line numbers and light
+// highlighting. It also returns the line indexes that start a section so
+// the viewer can page between them.
+func highlightSource(name, content string) (string, []int) {
+	s := detectSyntax(name)
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	width := len(fmt.Sprint(len(lines)))
+
+	var sections []int
+	var b strings.Builder
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indented := len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+
+		var rendered string
+		switch {
+		case s.comment != "" && strings.HasPrefix(trimmed, s.comment):
+			rendered = commentStyle.Render(line)
+		case s.sections && strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			rendered = sectionStyle.Render(line)
+			sections = append(sections, i)
+		case s.separator != "" && strings.Contains(line, s.separator):
+			idx := strings.Index(line, s.separator)
+			rendered = keyStyle.Render(line[:idx]) + line[idx:]
+			// Top-level keys act as sections in files without headers
+			if !s.sections && !indented && trimmed != "" {
+				sections = append(sections, i)
+			}
+		default:
+			rendered = line
+		}
+
+		b.WriteString(lineNumberStyle.Render(fmt.Sprintf("%*d ", width, i+1)))
+		b.WriteString(rendered)
+		if i < len(lines)-1 {
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String(), sections
+}
+
+// isBinary reports whether content looks like a non-text file
+func isBinary(content string) bool {
+	sample := content
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return strings.ContainsRune(sample, 0)
+}
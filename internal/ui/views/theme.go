@@ -0,0 +1,138 @@
+package views
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/theme"
+	"github.com/shubhamku044/containix/internal/ui/components"
+)
+
+// palette is the theme the styles below were built from
+var palette theme.Theme
+
+// Styles shared by every screen, rebuilt by SetTheme
+var (
+	statsBoxStyle    lipgloss.Style
+	titleStyle       lipgloss.Style
+	labelStyle       lipgloss.Style
+	valueStyle       lipgloss.Style
+	noSelectionStyle lipgloss.Style
+
+	browserTitleStyle  lipgloss.Style
+	browserHelpStyle   lipgloss.Style
+	browserStatusStyle lipgloss.Style
+	browserErrorStyle  lipgloss.Style
+
+	activeTabStyle   lipgloss.Style
+	inactiveTabStyle lipgloss.Style
+	cursorStyle      lipgloss.Style
+
+	lineNumberStyle lipgloss.Style
+	commentStyle    lipgloss.Style
+	keyStyle        lipgloss.Style
+	sectionStyle    lipgloss.Style
+
+	busStyle       lipgloss.Style
+	activeBusStyle lipgloss.Style
+	dimStyle       lipgloss.Style
+	portStyle      lipgloss.Style
+
+	helpBoxStyle  lipgloss.Style
+	helpKeyStyle  lipgloss.Style
+	helpDescStyle lipgloss.Style
+
+	statusBarStyle    lipgloss.Style
+	statusTargetStyle lipgloss.Style
+)
+
+func init() {
+	SetTheme(theme.Default())
+}
+
+// SetTheme restyles every view and component. It is not safe to call while
+// a frame is being rendered; the main model calls it from Update.
+func SetTheme(t theme.Theme) {
+	palette = t
+	components.SetTheme(t)
+
+	statsBoxStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(0, 1)
+	titleStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true)
+	labelStyle = lipgloss.NewStyle().
+		Foreground(t.Info).
+		Bold(true)
+	valueStyle = lipgloss.NewStyle().
+		Foreground(t.Success)
+	noSelectionStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle).
+		Italic(true)
+
+	browserTitleStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true).
+		Padding(0, 1)
+	browserHelpStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle).
+		Italic(true)
+	browserStatusStyle = lipgloss.NewStyle().
+		Foreground(t.Success)
+	browserErrorStyle = lipgloss.NewStyle().
+		Foreground(t.Error)
+
+	activeTabStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true).
+		Underline(true).
+		Padding(0, 1)
+	inactiveTabStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle).
+		Padding(0, 1)
+	cursorStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true)
+
+	lineNumberStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle)
+	commentStyle = lipgloss.NewStyle().
+		Foreground(t.Muted).
+		Italic(true)
+	keyStyle = lipgloss.NewStyle().
+		Foreground(t.Info)
+	sectionStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true)
+
+	busStyle = lipgloss.NewStyle().
+		Foreground(t.Border)
+	activeBusStyle = lipgloss.NewStyle().
+		Foreground(t.Success).
+		Bold(true)
+	dimStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle)
+	portStyle = lipgloss.NewStyle().
+		Foreground(t.Info)
+
+	helpBoxStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(1, 2)
+	helpKeyStyle = lipgloss.NewStyle().
+		Foreground(t.Success)
+	helpDescStyle = lipgloss.NewStyle().
+		Foreground(t.Text)
+
+	statusBarStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle).
+		Padding(0, 1)
+	statusTargetStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true)
+}
+
+// BorderColor is the current theme's border and focus color
+func BorderColor() lipgloss.TerminalColor {
+	return palette.Border
+}
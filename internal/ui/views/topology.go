@@ -0,0 +1,362 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+// OpenTopologyMsg asks the main model to open the topology diagram
+type OpenTopologyMsg struct{}
+
+// FocusContainerMsg asks the container list to select a container
+type FocusContainerMsg struct {
+	ID string
+}
+
+type topologyFetchedMsg struct {
+	containers []docker.Container
+	networks   []docker.Network
+}
+
+// topologyNode is a container row in the diagram
+type topologyNode struct {
+	container docker.Container
+	networks  map[string]bool // network ID -> attached
+}
+
+// TopologyModel draws which containers share which user-defined networks
+type TopologyModel struct {
+	dockerClient docker.Runtime
+	ctx          context.Context
+	cancel       context.CancelFunc
+	nodes        []topologyNode
+	networks     []docker.Network
+	cursor       int
+	marked       int
+	viewport     viewport.Model
+	cfg          config.Config
+	help         helpToggle
+	err          error
+	width        int
+	height       int
+	parentModel  tea.Model
+}
+
+// NewTopology creates the network topology view
+func NewTopology(ctx context.Context, dockerClient docker.Runtime, cfg config.Config, width, height int, parentModel tea.Model) TopologyModel {
+	ctx, cancel := context.WithCancel(ctx)
+	m := TopologyModel{
+		dockerClient: dockerClient,
+		ctx:          ctx,
+		cancel:       cancel,
+		marked:       -1,
+		viewport:     viewport.New(0, 0),
+		cfg:          cfg,
+		parentModel:  parentModel,
+	}
+	m.resize(width, height)
+	return m
+}
+
+// Init implements tea.Model
+func (m TopologyModel) Init() tea.Cmd {
+	return m.fetchTopology()
+}
+
+func (m TopologyModel) fetchTopology() tea.Cmd {
+	ctx, client := m.ctx, m.dockerClient
+	return func() tea.Msg {
+		containers, err := client.ListContainers(ctx)
+		if err != nil {
+			return failed(err)
+		}
+		networks, err := client.ListNetworks(ctx)
+		if err != nil {
+			return failed(err)
+		}
+		return topologyFetchedMsg{containers: containers, networks: networks}
+	}
+}
+
+func (m *TopologyModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = width - 4
+	m.viewport.Height = height - 6
+}
+
+// build turns containers and networks into diagram rows and columns
+func (m *TopologyModel) build(containers []docker.Container, networks []docker.Network) {
+	m.networks = nil
+	attached := map[string]map[string]bool{}
+	for _, n := range networks {
+		if !n.IsUserDefined() {
+			continue
+		}
+		m.networks = append(m.networks, n)
+		for _, ep := range n.Containers {
+			if attached[ep.ContainerID] == nil {
+				attached[ep.ContainerID] = map[string]bool{}
+			}
+			attached[ep.ContainerID][n.ID] = true
+		}
+	}
+
+	m.nodes = make([]topologyNode, len(containers))
+	for i, c := range containers {
+		m.nodes[i] = topologyNode{container: c, networks: attached[c.ID]}
+	}
+	// Containers on user-defined networks first, then by name
+	sort.SliceStable(m.nodes, func(i, j int) bool {
+		ni, nj := len(m.nodes[i].networks) > 0, len(m.nodes[j].networks) > 0
+		if ni != nj {
+			return ni
+		}
+		return m.nodes[i].container.Name < m.nodes[j].container.Name
+	})
+
+	if m.cursor >= len(m.nodes) {
+		m.cursor = 0
+	}
+	m.marked = -1
+}
+
+// sharedNetworks returns the names of networks both nodes are attached to
+func (m TopologyModel) sharedNetworks(a, b int) []string {
+	var shared []string
+	for _, n := range m.networks {
+		if m.nodes[a].networks[n.ID] && m.nodes[b].networks[n.ID] {
+			shared = append(shared, n.Name)
+		}
+	}
+	return shared
+}
+
+// reachable reports whether node i shares any network with the cursor node
+func (m TopologyModel) reachable(i int) bool {
+	return i == m.cursor || len(m.sharedNetworks(i, m.cursor)) > 0
+}
+
+// Update implements tea.Model
+func (m TopologyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+
+	case topologyFetchedMsg:
+		m.err = nil
+		m.build(msg.containers, msg.networks)
+
+	case ErrMsg:
+		m.err = msg.Err
+
+	case tea.KeyMsg:
+		if m.help.handle(m.cfg.Keys, msg) {
+			return m, nil
+		}
+
+		switch m.cfg.Keys.Action(msg, keymap.Topology) {
+		case "back":
+			m.cancel()
+			return m.parentModel, nil
+		case "down":
+			if m.cursor < len(m.nodes)-1 {
+				m.cursor++
+			}
+		case "up":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "top":
+			m.cursor = 0
+		case "bottom":
+			m.cursor = len(m.nodes) - 1
+		case "mark":
+			// Mark the current container as the source for a reachability check
+			if m.marked == m.cursor {
+				m.marked = -1
+			} else {
+				m.marked = m.cursor
+			}
+		case "refresh":
+			return m, m.fetchTopology()
+		case "focus":
+			if m.cursor < len(m.nodes) {
+				id := m.nodes[m.cursor].container.ID
+				m.cancel()
+				return m.parentModel, func() tea.Msg {
+					return FocusContainerMsg{ID: id}
+				}
+			}
+		}
+	}
+
+	m.viewport.SetContent(m.diagram())
+	// Keep the cursor row visible; two header lines precede the rows
+	row := m.cursor + 2
+	if row < m.viewport.YOffset {
+		m.viewport.SetYOffset(row)
+	} else if row >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(row - m.viewport.Height + 1)
+	}
+	return m, nil
+}
+
+// diagram renders the bus diagram: one vertical line per user-defined
+// network, with a dot on every container attached to it and published host
+// ports on the left
+func (m TopologyModel) diagram() string {
+	if len(m.nodes) == 0 {
+		return noSelectionStyle.Render("No containers")
+	}
+
+	portWidth, nameWidth := len("Host ports"), len("Container")
+	for _, node := range m.nodes {
+		portWidth = max(portWidth, runewidth.StringWidth(publishedPorts(node.container)))
+		nameWidth = max(nameWidth, runewidth.StringWidth(node.container.Name))
+	}
+
+	colWidths := make([]int, len(m.networks))
+	header := pad("Host ports", portWidth) + "     " + pad("Container", nameWidth+2)
+	for j, n := range m.networks {
+		colWidths[j] = max(runewidth.StringWidth(n.Name), 3) + 2
+		header += center(n.Name, colWidths[j])
+	}
+
+	// First and last attached row for every network, to draw the bus
+	first := make([]int, len(m.networks))
+	last := make([]int, len(m.networks))
+	for j, n := range m.networks {
+		first[j], last[j] = -1, -1
+		for i, node := range m.nodes {
+			if node.networks[n.ID] {
+				if first[j] < 0 {
+					first[j] = i
+				}
+				last[j] = i
+			}
+		}
+	}
+
+	lines := []string{titleStyle.Render(header), dimStyle.Render(strings.Repeat("─", runewidth.StringWidth(header)))}
+	current := m.nodes[m.cursor]
+	for i, node := range m.nodes {
+		ports := publishedPorts(node.container)
+		arrow := "     "
+		if ports != "" {
+			arrow = " ──▶ "
+		}
+
+		name := pad(node.container.Name, nameWidth)
+		prefix := "  "
+		switch {
+		case i == m.cursor:
+			prefix = cursorStyle.Render("> ")
+			name = cursorStyle.Render(name)
+		case i == m.marked:
+			prefix = cursorStyle.Render("* ")
+		case !m.reachable(i):
+			name = dimStyle.Render(name)
+		}
+
+		row := portStyle.Render(pad(ports, portWidth)) + arrow + prefix + name
+		for j, n := range m.networks {
+			style := busStyle
+			if current.networks[n.ID] {
+				style = activeBusStyle
+			}
+			var cell string
+			switch {
+			case node.networks[n.ID]:
+				cell = "●"
+			case first[j] >= 0 && i > first[j] && i < last[j]:
+				cell = "│"
+			default:
+				cell = " "
+			}
+			row += style.Render(center(cell, colWidths[j]))
+		}
+		lines = append(lines, row)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// reachabilityStatus explains whether the marked container can reach the
+// current one by name
+func (m TopologyModel) reachabilityStatus() string {
+	if m.marked < 0 || m.marked >= len(m.nodes) || m.cursor >= len(m.nodes) {
+		return ""
+	}
+	a, b := m.nodes[m.marked].container.Name, m.nodes[m.cursor].container.Name
+	if m.marked == m.cursor {
+		return fmt.Sprintf("Marked %s; move to another container to compare", a)
+	}
+	shared := m.sharedNetworks(m.marked, m.cursor)
+	if len(shared) == 0 {
+		return browserErrorStyle.Render(fmt.Sprintf("%s and %s share no user-defined network: they cannot resolve each other by name", a, b))
+	}
+	return fmt.Sprintf("%s ⇄ %s via %s", a, b, strings.Join(shared, ", "))
+}
+
+// View implements tea.Model
+func (m TopologyModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Topology, keymap.Global)
+	}
+
+	status := browserStatusStyle.Render(m.reachabilityStatus())
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		browserTitleStyle.Render("Network topology"),
+		m.viewport.View(),
+		status,
+		browserHelpStyle.Width(m.width-4).Render(m.cfg.Keys.Short(keymap.Topology, "mark", "focus", "refresh", "back")+" • "+m.cfg.Keys.Short(keymap.Global, "help")),
+	))
+}
+
+// publishedPorts lists the host-published ports of a container
+func publishedPorts(c docker.Container) string {
+	var ports []string
+	seen := map[string]bool{}
+	for _, p := range c.Ports {
+		if !p.IsPublished() {
+			continue
+		}
+		s := fmt.Sprintf(":%d→%d/%s", p.PublicPort, p.PrivatePort, p.Type)
+		// IPv4 and IPv6 bindings of the same port show up twice
+		if !seen[s] {
+			seen[s] = true
+			ports = append(ports, s)
+		}
+	}
+	return strings.Join(ports, " ")
+}
+
+// pad right-pads s with spaces to the given display width
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-runewidth.StringWidth(s)))
+}
+
+// center centres s within the given display width
+func center(s string, width int) string {
+	left := (width - runewidth.StringWidth(s)) / 2
+	if left < 0 {
+		left = 0
+	}
+	return pad(strings.Repeat(" ", left)+s, width)
+}
@@ -0,0 +1,491 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shubhamku044/containix/internal/config"
+	"github.com/shubhamku044/containix/internal/docker"
+	"github.com/shubhamku044/containix/internal/keymap"
+)
+
+type uploadMode int
+
+const (
+	pickMode uploadMode = iota
+	destMode
+	confirmMode
+	progressMode
+)
+
+// OpenUploadMsg asks the main model to open the upload dialog
+type OpenUploadMsg struct {
+	ID   string
+	Name string
+	Dest string
+}
+
+type uploadConflictsMsg struct {
+	conflicts []string
+}
+
+type uploadProgressMsg struct {
+	written int64
+	total   int64
+}
+
+type uploadDoneMsg struct {
+	err error
+}
+
+// UploadFinishedMsg is sent to the parent after a successful upload so it
+// can refresh whatever it shows
+type UploadFinishedMsg struct {
+	Dest string
+}
+
+type localItem struct {
+	path   string
+	info   os.FileInfo
+	marked bool
+}
+
+func (i localItem) Title() string {
+	name := i.info.Name()
+	if i.info.IsDir() {
+		name += "/"
+	}
+	if i.marked {
+		return "● " + name
+	}
+	return "  " + name
+}
+
+func (i localItem) Description() string {
+	return fmt.Sprintf("  %s  %s", i.info.Mode().String(), formatBytes(i.info.Size()))
+}
+
+func (i localItem) FilterValue() string { return i.info.Name() }
+
+// UploadModel picks local files and copies them into a container
+type UploadModel struct {
+	dockerClient  docker.Runtime
+	ctx           context.Context
+	cancel        context.CancelFunc
+	containerID   string
+	containerName string
+	localDir      string
+	list          list.Model
+	input         textinput.Model
+	progress      progress.Model
+	mode          uploadMode
+	selected      []string
+	conflicts     []string
+	cfg           config.Config
+	help          helpToggle
+	percent       float64
+	uploadCh      chan tea.Msg
+	status        string
+	err           error
+	width         int
+	height        int
+	parentModel   tea.Model
+}
+
+// NewUpload creates an upload dialog that starts in the current working
+// directory and targets dest inside the container
+func NewUpload(ctx context.Context, dockerClient docker.Runtime, containerID, containerName, dest string, cfg config.Config, width, height int, parentModel tea.Model) UploadModel {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+
+	ti := textinput.New()
+	ti.Prompt = "Destination: "
+	if dest == "" {
+		dest = "/tmp"
+	}
+	ti.SetValue(dest)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "/"
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := UploadModel{
+		dockerClient:  dockerClient,
+		ctx:           ctx,
+		cancel:        cancel,
+		containerID:   containerID,
+		containerName: containerName,
+		list:          l,
+		input:         ti,
+		progress:      progress.New(progress.WithDefaultGradient()),
+		cfg:           cfg,
+		parentModel:   parentModel,
+	}
+	m.resize(width, height)
+	m.readLocalDir(cwd)
+	return m
+}
+
+// Init implements tea.Model
+func (m UploadModel) Init() tea.Cmd {
+	return nil
+}
+
+// readLocalDir lists a host directory into the picker
+func (m *UploadModel) readLocalDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	items := make([]list.Item, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		full := filepath.Join(dir, e.Name())
+		items = append(items, localItem{path: full, info: info, marked: m.isSelected(full)})
+	}
+
+	m.err = nil
+	m.localDir = dir
+	m.list.ResetFilter()
+	m.list.SetItems(items)
+	m.list.Select(0)
+}
+
+func (m UploadModel) isSelected(p string) bool {
+	for _, s := range m.selected {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *UploadModel) toggle(item localItem) {
+	item.marked = !item.marked
+	m.list.SetItem(m.list.Index(), item)
+	if item.marked {
+		m.selected = append(m.selected, item.path)
+		return
+	}
+	for i, s := range m.selected {
+		if s == item.path {
+			m.selected = append(m.selected[:i], m.selected[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *UploadModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.list.SetSize(width*85/100-6, height*80/100-10)
+	m.progress.Width = width*85/100 - 10
+}
+
+// checkConflicts looks for destination paths that already exist
+func (m UploadModel) checkConflicts(dest string, sources []string) tea.Cmd {
+	ctx, client, id := m.ctx, m.dockerClient, m.containerID
+	return func() tea.Msg {
+		stat, err := client.StatPath(ctx, id, dest)
+		if err != nil {
+			return failed(err)
+		}
+		if !stat.IsDir() {
+			return ErrMsg{Err: fmt.Errorf("%s is not a directory", dest)}
+		}
+
+		var conflicts []string
+		for _, src := range sources {
+			target := path.Join(dest, filepath.Base(src))
+			if _, err := client.StatPath(ctx, id, target); err == nil {
+				conflicts = append(conflicts, target)
+			}
+		}
+		return uploadConflictsMsg{conflicts: conflicts}
+	}
+}
+
+// startUpload runs the copy in the background and streams progress back
+func (m *UploadModel) startUpload(dest string, overwrite bool) tea.Cmd {
+	ctx, client, id, sources := m.ctx, m.dockerClient, m.containerID, m.selected
+	ch := make(chan tea.Msg, 1)
+
+	go func() {
+		defer close(ch)
+		var lastPercent int64 = -1
+		err := client.UploadToContainer(ctx, id, sources, dest, overwrite, func(written, total int64) {
+			// Only report whole-percent changes to avoid flooding the UI
+			percent := int64(100)
+			if total > 0 {
+				percent = written * 100 / total
+			}
+			if percent == lastPercent {
+				return
+			}
+			lastPercent = percent
+			select {
+			case ch <- uploadProgressMsg{written: written, total: total}:
+			default:
+			}
+		})
+		ch <- uploadDoneMsg{err: err}
+	}()
+
+	m.uploadCh = ch
+	return waitForUpload(ch)
+}
+
+// waitForUpload delivers the next progress or completion message
+func waitForUpload(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Update implements tea.Model
+func (m UploadModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		m.parentModel, _ = m.parentModel.Update(msg)
+		return m, nil
+
+	case uploadConflictsMsg:
+		if len(msg.conflicts) > 0 && m.cfg.Confirm.Overwrite {
+			m.conflicts = msg.conflicts
+			m.mode = confirmMode
+			return m, nil
+		}
+		m.mode = progressMode
+		return m, m.startUpload(m.input.Value(), len(msg.conflicts) > 0)
+
+	case uploadProgressMsg:
+		if msg.total > 0 {
+			m.percent = float64(msg.written) / float64(msg.total)
+		}
+		m.status = fmt.Sprintf("%s / %s", formatBytes(msg.written), formatBytes(msg.total))
+		return m, waitForUpload(m.uploadCh)
+
+	case uploadDoneMsg:
+		m.uploadCh = nil
+		if msg.err != nil {
+			m.err = msg.err
+			m.mode = destMode
+			return m, m.input.Focus()
+		}
+		m.percent = 1
+		m.status = fmt.Sprintf("Uploaded %d item(s) to %s", len(m.selected), m.input.Value())
+		return m, nil
+
+	case ErrMsg:
+		m.err = msg.Err
+		if m.mode != pickMode {
+			m.mode = destMode
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case destMode:
+			return m.updateDest(msg)
+		case confirmMode:
+			return m.updateConfirm(msg)
+		case progressMode:
+			if m.uploadCh == nil {
+				if m.cfg.Keys.Matches(msg, keymap.Upload, "back") || m.cfg.Keys.Matches(msg, keymap.Form, "submit") {
+					dest := m.input.Value()
+					m.cancel()
+					return m.parentModel, func() tea.Msg {
+						return UploadFinishedMsg{Dest: dest}
+					}
+				}
+			}
+			return m, nil
+		default:
+			return m.updatePick(msg)
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.mode == destMode {
+		m.input, cmd = m.input.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m UploadModel) updatePick(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+	if m.help.handle(m.cfg.Keys, msg) {
+		return m, nil
+	}
+
+	switch m.cfg.Keys.Action(msg, keymap.Upload) {
+	case "back":
+		m.cancel()
+		return m.parentModel, nil
+	case "mark":
+		if item, ok := m.list.SelectedItem().(localItem); ok {
+			m.toggle(item)
+		}
+		return m, nil
+	case "open":
+		if item, ok := m.list.SelectedItem().(localItem); ok {
+			if item.info.IsDir() {
+				m.readLocalDir(item.path)
+			} else {
+				m.toggle(item)
+			}
+		}
+		return m, nil
+	case "parent":
+		m.readLocalDir(filepath.Dir(m.localDir))
+		return m, nil
+	case "continue":
+		if len(m.selected) == 0 {
+			m.err = fmt.Errorf("mark at least one file with %s", m.cfg.Keys.Get(keymap.Upload, "mark").Help().Key)
+			return m, nil
+		}
+		m.err = nil
+		m.mode = destMode
+		return m, m.input.Focus()
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m UploadModel) updateDest(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Form) {
+	case "cancel":
+		m.input.Blur()
+		m.mode = pickMode
+		return m, nil
+	case "submit":
+		dest := strings.TrimSpace(m.input.Value())
+		if dest == "" || !strings.HasPrefix(dest, "/") {
+			m.err = fmt.Errorf("destination must be an absolute path")
+			return m, nil
+		}
+		m.err = nil
+		m.input.Blur()
+		m.status = "Checking destination..."
+		return m, m.checkConflicts(dest, m.selected)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m UploadModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.cfg.Keys.Action(msg, keymap.Confirm) {
+	case "yes":
+		m.mode = progressMode
+		return m, m.startUpload(m.input.Value(), true)
+	case "no":
+		m.conflicts = nil
+		m.mode = destMode
+		return m, m.input.Focus()
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m UploadModel) View() string {
+	if m.help.visible {
+		return HelpOverlay(m.cfg.Keys, m.width, m.height, keymap.Upload, keymap.Form, keymap.Confirm, keymap.Global)
+	}
+
+	modalWidth := m.width * 85 / 100
+	modalHeight := m.height * 80 / 100
+
+	header := browserTitleStyle.Render("Upload to " + m.containerName)
+
+	keys := m.cfg.Keys
+	var body, help string
+	switch m.mode {
+	case destMode:
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			fmt.Sprintf("%d item(s) selected", len(m.selected)),
+			"",
+			m.input.View())
+		help = keys.Short(keymap.Form, "submit", "cancel")
+	case confirmMode:
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			"These paths already exist and will be overwritten:",
+			"",
+			strings.Join(m.conflicts, "\n"),
+			"",
+			"Overwrite? (y/n)")
+		help = keys.Short(keymap.Confirm, "yes", "no")
+	case progressMode:
+		body = m.progress.ViewAs(m.percent)
+		help = "please wait..."
+		if m.uploadCh == nil {
+			help = keys.Short(keymap.Upload, "back")
+		}
+	default:
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			m.localDir,
+			m.list.View())
+		help = keys.Short(keymap.Upload, "mark", "open", "parent", "continue", "back") + " • " + keys.Short(keymap.Global, "help")
+	}
+
+	status := browserStatusStyle.Render(m.status)
+	if m.err != nil {
+		status = browserErrorStyle.Render("Error: " + m.err.Error())
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		body,
+		"",
+		status,
+		browserHelpStyle.Render(help),
+	)
+
+	modalStyle := lipgloss.NewStyle().
+		Width(modalWidth).
+		Height(modalHeight).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Border).
+		Padding(1, 2)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		modalStyle.Render(content),
+	)
+}
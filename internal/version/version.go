@@ -0,0 +1,22 @@
+// Package version holds build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/shubhamku044/containix/internal/version.Version=v0.2.0"
+package version
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Set with -ldflags -X at build time
+var (
+	Version = "dev"
+	Commit  = "unknown"
+	Date    = "unknown"
+)
+
+// String returns a one-line description of the build
+func String() string {
+	return fmt.Sprintf("containix %s (commit %s, built %s, %s %s/%s)",
+		Version, Commit, Date, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
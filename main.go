@@ -1,15 +1,7 @@
 package main
 
-import (
-	"fmt"
-
-	"github.com/shubhamku044/containix/internal/app"
-)
+import "github.com/shubhamku044/containix/cmd"
 
 func main() {
-	// Run the application directly without building a binary
-	fmt.Println("Starting Containix...")
-
-	// Call the application's Run function directly
-	app.Run()
+	cmd.Execute()
 }